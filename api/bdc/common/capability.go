@@ -45,6 +45,10 @@ type SchematicCategory string
 // CUECategory categories of capability schematic
 const (
 	CUECategory SchematicCategory = "cue"
+	// HelmCategory categories of capability schematic rendered by Helm chart
+	HelmCategory SchematicCategory = "helm"
+	// GoTemplateCategory categories of capability schematic rendered by Go template
+	GoTemplateCategory SchematicCategory = "gotemplate"
 )
 
 const (
@@ -99,4 +103,7 @@ type Capability struct {
 
 	// KubeTemplate
 	KubeTemplate runtime.RawExtension `json:"kubetemplate,omitempty"`
+
+	// GoTemplate
+	ParameterSchema string `json:"parameterSchema,omitempty"`
 }
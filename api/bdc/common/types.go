@@ -28,9 +28,46 @@ type CUE struct {
 	Template string `json:"template"`
 }
 
+// Helm defines a Helm chart rendering the manifests of the capability, the parameter is merged into the chart values
+type Helm struct {
+	// Chart is the path of a local chart directory or chart archive in the operator container
+	// +optional
+	Chart string `json:"chart,omitempty"`
+	// ChartArchive refers to a chart archive stored in a ConfigMap
+	// +optional
+	ChartArchive *ChartArchiveReference `json:"chartArchive,omitempty"`
+	// Values overrides the default values of the chart, which are overridden by the parameter in turn
+	// +optional
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Values *runtime.RawExtension `json:"values,omitempty"`
+}
+
+// ChartArchiveReference refers to a chart archive (.tgz) stored in the binaryData of a ConfigMap
+type ChartArchiveReference struct {
+	// Name of the ConfigMap
+	Name string `json:"name"`
+	// Namespace of the ConfigMap, defaults to the namespace of the operator
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// Key of the chart archive in the ConfigMap
+	Key string `json:"key"`
+}
+
+// GoTemplate defines a Go text/template rendering the manifests of the capability over parameter and context
+type GoTemplate struct {
+	// Template renders one or more YAML manifests separated by "---", the sprig functions and toYaml are supported
+	Template string `json:"template"`
+	// Schema is the JSON Schema of parameter in JSON or YAML, which is used to validate the properties,
+	// set the default values of parameter and generate the UI schema
+	// +optional
+	Schema string `json:"schema,omitempty"`
+}
+
 // Schematic defines the encapsulation of this capability
 type Schematic struct {
-	CUE *CUE `json:"cue,omitempty"`
+	CUE        *CUE        `json:"cue,omitempty"`
+	Helm       *Helm       `json:"helm,omitempty"`
+	GoTemplate *GoTemplate `json:"goTemplate,omitempty"`
 }
 
 type Status struct {
@@ -122,6 +159,17 @@ type ApplicationTraitStatus struct {
 	Message string `json:"message,omitempty"`
 }
 
+// DryRunStatus records the result of the last dry-run of an object
+type DryRunStatus struct {
+	// ConfigMapRef is the name of the ConfigMap in the system namespace storing the rendered manifests
+	// and their diff against the live objects
+	ConfigMapRef string `json:"configMapRef"`
+	// Changes are the resources which would be created, updated or pruned by the dispatch,
+	// in the form of "<action> <kind> <namespace>/<name>"
+	// +optional
+	Changes []string `json:"changes,omitempty"`
+}
+
 const (
 	// ApplicationInitializing means the app is preparing for initializing
 	ApplicationInitializing string = "initializing"
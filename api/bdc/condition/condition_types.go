@@ -35,6 +35,14 @@ const (
 	// TypeSynced resources are believed to be in sync with the
 	// Kubernetes resources that manage their lifecycle.
 	TypeSynced ConditionType = "Synced"
+
+	// TypeSuspended resources have the frozen or disabled state of their
+	// BigDataCluster applied.
+	TypeSuspended ConditionType = "Suspended"
+
+	// TypePaused resources are not reconciled because they are annotated
+	// with bdc.kdp.io/paused.
+	TypePaused ConditionType = "Paused"
 )
 
 // A ConditionReason represents the reason a resource is in a condition.
@@ -54,6 +62,22 @@ const (
 	ReasonReconcileVela    ConditionReason = "VelaApplicationGenerated"
 	ReasonReconcileSuccess ConditionReason = "ReconcileSuccess"
 	ReasonReconcileError   ConditionReason = "ReconcileError"
+	ReasonSyncConflict     ConditionReason = "SyncConflict"
+)
+
+// Reasons a resource is or is not suspended.
+const (
+	ReasonBigDataClusterActive   ConditionReason = "BigDataClusterActive"
+	ReasonBigDataClusterFrozen   ConditionReason = "BigDataClusterFrozen"
+	ReasonBigDataClusterDisabled ConditionReason = "BigDataClusterDisabled"
+	ReasonScalingDown            ConditionReason = "ScalingDown"
+	ReasonRestoring              ConditionReason = "Restoring"
+)
+
+// Reasons a resource is or is not paused.
+const (
+	ReasonPausedByAnnotation ConditionReason = "PausedByAnnotation"
+	ReasonUnpaused           ConditionReason = "Unpaused"
 )
 
 type ConditionedStatus struct {
@@ -242,6 +266,18 @@ func ReconcileError(err error) Condition {
 	}
 }
 
+// SyncConflict returns a condition indicating that the resource and its
+// adopted resource are both changed since they were last synced.
+func SyncConflict(msg string) Condition {
+	return Condition{
+		Type:               TypeSynced,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonSyncConflict,
+		Message:            msg,
+	}
+}
+
 // ReadyCondition generate ready condition for conditionType
 func ReadyCondition(tpy string) Condition {
 	return Condition{
@@ -262,3 +298,73 @@ func ErrorCondition(tpy string, err error) Condition {
 		Message:            err.Error(),
 	}
 }
+
+// Suspended returns a condition indicating that the frozen or disabled state
+// of the BigDataCluster has fully taken effect on the resource.
+func Suspended(reason ConditionReason) Condition {
+	return Condition{
+		Type:               TypeSuspended,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             reason,
+	}
+}
+
+// Suspending returns a condition indicating that the resource is being
+// scaled down because its BigDataCluster is disabled.
+func Suspending(msg string) Condition {
+	return Condition{
+		Type:               TypeSuspended,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonScalingDown,
+		Message:            msg,
+	}
+}
+
+// Resuming returns a condition indicating that the resource is being
+// restored because its BigDataCluster is active again.
+func Resuming(msg string) Condition {
+	return Condition{
+		Type:               TypeSuspended,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonRestoring,
+		Message:            msg,
+	}
+}
+
+// Resumed returns a condition indicating that the resource runs normally
+// because its BigDataCluster is active.
+func Resumed() Condition {
+	return Condition{
+		Type:               TypeSuspended,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonBigDataClusterActive,
+	}
+}
+
+// Paused returns a condition indicating that the reconciliation of the
+// resource is paused by the annotation.
+func Paused() Condition {
+	return Condition{
+		Type:               TypePaused,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonPausedByAnnotation,
+	}
+}
+
+// Unpaused returns a condition indicating that the reconciliation of the
+// resource is resumed, msg describes the downstream fields drifted from the
+// template during the pause.
+func Unpaused(msg string) Condition {
+	return Condition{
+		Type:               TypePaused,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonUnpaused,
+		Message:            msg,
+	}
+}
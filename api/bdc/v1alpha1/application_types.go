@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"kdp-oam-operator/api/bdc/common"
@@ -37,6 +38,10 @@ type ApplicationSpec struct {
 	Type string `json:"type,omitempty"`
 	// Application properties, such as resources etc.
 	Properties *runtime.RawExtension `json:"properties,omitempty"`
+	// DefinitionRevision pins the application to a revision of the XDefinition, by the name or the number of
+	// the XDefinitionRevision. The latest XDefinition is used if it is empty or "latest".
+	// +optional
+	DefinitionRevision string `json:"definitionRevision,omitempty"`
 }
 
 // ApplicationStatus defines the observed state of Application
@@ -47,6 +52,25 @@ type ApplicationStatus struct {
 	// ConditionedStatus reflects the observed status of a resource
 	condition.ConditionedStatus `json:",inline"`
 	SchemaConfigMapRef          string `json:"schemaConfigMapRef"`
+	// Message is a human-readable message of the health of the resources rendered by the XDefinition
+	// +optional
+	Message string `json:"message,omitempty"`
+	// OutputResources record the resources applied for the object, the ones no longer rendered by the XDefinition are pruned
+	// +optional
+	OutputResources []corev1.ObjectReference `json:"outputResources,omitempty"`
+	// ContextKeys are the keys of the KDP context ConfigMaps referred by the last render
+	// +optional
+	ContextKeys []string `json:"contextKeys,omitempty"`
+	// ContextHash is the hash of the KDP context data referred by the last render,
+	// the object is out of date if it differs from the current context data
+	// +optional
+	ContextHash string `json:"contextHash,omitempty"`
+	// DryRun is the result of the last dry-run, it's set when the object is annotated with bdc.kdp.io/dry-run
+	// +optional
+	DryRun *common.DryRunStatus `json:"dryRun,omitempty"`
+	// DefinitionRevision is the XDefinitionRevision rendering the application, empty if the latest XDefinition is used
+	// +optional
+	DefinitionRevision string `json:"definitionRevision,omitempty"`
 	// Services record the status of the application services
 	Services []common.ApplicationComponentStatus `json:"services,omitempty"`
 	// AppliedResources record the resources that the  workflow step apply.
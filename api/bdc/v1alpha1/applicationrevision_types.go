@@ -0,0 +1,69 @@
+/*
+Copyright 2023 KDP(Kubernetes Data Platform).
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// ApplicationRevisionSpec defines a snapshot of an Application which is rendered effectively
+type ApplicationRevisionSpec struct {
+	// ApplicationName is the name of the Application
+	ApplicationName string `json:"applicationName"`
+	// Revision is the number of the revision, which increases by one on every effective change
+	Revision int64 `json:"revision"`
+	// Application properties of the revision
+	Properties *runtime.RawExtension `json:"properties,omitempty"`
+	// XDefinitionName is the name of the XDefinition rendering the revision
+	XDefinitionName string `json:"xDefinitionName,omitempty"`
+	// XDefinitionGeneration is the generation of the XDefinition rendering the revision
+	XDefinitionGeneration int64 `json:"xDefinitionGeneration,omitempty"`
+	// ManifestHash is the hash of the rendered manifests
+	ManifestHash string `json:"manifestHash"`
+	// Author is the user who made the change
+	// +optional
+	Author string `json:"author,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:scope=Cluster,shortName=apprev
+//+kubebuilder:printcolumn:name="Application",type="string",JSONPath=`.spec.applicationName`
+//+kubebuilder:printcolumn:name="Revision",type="integer",JSONPath=`.spec.revision`
+//+kubebuilder:printcolumn:name="Hash",type="string",JSONPath=`.spec.manifestHash`
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=`.metadata.creationTimestamp`
+
+// ApplicationRevision is the Schema for the applicationrevisions API
+type ApplicationRevision struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ApplicationRevisionSpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ApplicationRevisionList contains a list of ApplicationRevision
+type ApplicationRevisionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ApplicationRevision `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ApplicationRevision{}, &ApplicationRevisionList{})
+}
@@ -17,7 +17,9 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"kdp-oam-operator/api/bdc/common"
 	"kdp-oam-operator/api/bdc/condition"
 )
 
@@ -49,6 +51,18 @@ const (
 	TerminatingBigDataCluster BigDataClusterStatusCategory = "Terminating"
 )
 
+// ApplicationStateStatus records how far the state of a BigDataCluster has been enforced on one of its Applications
+type ApplicationStateStatus struct {
+	// Name of the Application
+	Name string `json:"name"`
+	// State is the BigDataCluster state being enforced on the Application
+	State BigDataClusterStatusCategory `json:"state"`
+	// Enforced reports whether the state has fully taken effect on the Application
+	Enforced bool `json:"enforced"`
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
 // BigDataClusterStatus defines the observed state of BigDataCluster
 type BigDataClusterStatus struct {
 	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
@@ -57,6 +71,25 @@ type BigDataClusterStatus struct {
 	// ConditionedStatus reflects the observed status of a resource
 	condition.ConditionedStatus `json:",inline"`
 	SchemaConfigMapRef          string `json:"schemaConfigMapRef"`
+	// Message is a human-readable message of the health of the resources rendered by the XDefinition
+	// +optional
+	Message string `json:"message,omitempty"`
+	// OutputResources record the resources applied for the object, the ones no longer rendered by the XDefinition are pruned
+	// +optional
+	OutputResources []corev1.ObjectReference `json:"outputResources,omitempty"`
+	// ContextKeys are the keys of the KDP context ConfigMaps referred by the last render
+	// +optional
+	ContextKeys []string `json:"contextKeys,omitempty"`
+	// ContextHash is the hash of the KDP context data referred by the last render,
+	// the object is out of date if it differs from the current context data
+	// +optional
+	ContextHash string `json:"contextHash,omitempty"`
+	// DryRun is the result of the last dry-run, it's set when the object is annotated with bdc.kdp.io/dry-run
+	// +optional
+	DryRun *common.DryRunStatus `json:"dryRun,omitempty"`
+	// Applications record the progress of enforcing the BigDataCluster state on each of its Applications
+	// +optional
+	Applications []ApplicationStateStatus `json:"applications,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -83,6 +116,19 @@ func (cd *BigDataCluster) GetCondition(conditionType condition.ConditionType) co
 	return cd.Status.GetCondition(conditionType)
 }
 
+// DesiredState returns the state requested by the spec of BigDataCluster,
+// a disabled BigDataCluster is also frozen so Disabled takes precedence.
+func (cd *BigDataCluster) DesiredState() BigDataClusterStatusCategory {
+	switch {
+	case cd.Spec.Disabled:
+		return DisabledBigDataCluster
+	case cd.Spec.Frozen:
+		return FrozenBigDataCluster
+	default:
+		return ActiveBigDataCluster
+	}
+}
+
 //+kubebuilder:object:root=true
 
 // BigDataClusterList contains a list of BigDataCluster
@@ -17,8 +17,10 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"kdp-oam-operator/api/bdc/common"
 	"kdp-oam-operator/api/bdc/condition"
 )
 
@@ -43,6 +45,22 @@ type ContextSecretStatus struct {
 	// ConditionedStatus reflects the observed status of a resource
 	condition.ConditionedStatus `json:",inline"`
 	SchemaConfigMapRef          string `json:"schemaConfigMapRef"`
+	// Message is a human-readable message of the health of the resources rendered by the XDefinition
+	// +optional
+	Message string `json:"message,omitempty"`
+	// OutputResources record the resources applied for the object, the ones no longer rendered by the XDefinition are pruned
+	// +optional
+	OutputResources []corev1.ObjectReference `json:"outputResources,omitempty"`
+	// ContextKeys are the keys of the KDP context ConfigMaps referred by the last render
+	// +optional
+	ContextKeys []string `json:"contextKeys,omitempty"`
+	// ContextHash is the hash of the KDP context data referred by the last render,
+	// the object is out of date if it differs from the current context data
+	// +optional
+	ContextHash string `json:"contextHash,omitempty"`
+	// DryRun is the result of the last dry-run, it's set when the object is annotated with bdc.kdp.io/dry-run
+	// +optional
+	DryRun *common.DryRunStatus `json:"dryRun,omitempty"`
 }
 
 //+kubebuilder:object:root=true
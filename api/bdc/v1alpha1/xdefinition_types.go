@@ -19,6 +19,7 @@ package v1alpha1
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"kdp-oam-operator/api/bdc/common"
+	"kdp-oam-operator/api/bdc/condition"
 )
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
@@ -51,7 +52,7 @@ type XDefinitionSpec struct {
 	// +optional
 	Status *common.Status `json:"status,omitempty"`
 	// Schematic defines the data format and template of the encapsulation of the definition.
-	// Only CUE schematic is supported for now.
+	// One of CUE, Helm and GoTemplate schematic is supported.
 	Schematic   *common.Schematic `json:"schematic"`
 	APIResource APIResource       `json:"apiResource"`
 	// +optional
@@ -62,8 +63,20 @@ type XDefinitionSpec struct {
 type XDefinitionStatus struct {
 	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
+	// ConditionedStatus reflects the observed status of a resource
+	condition.ConditionedStatus `json:",inline"`
 	SchemaConfigMapRef          string `json:"schemaConfigMapRef"`
 	SchemaConfigMapRefNamespace string `json:"schemaConfigMapRefNamespace"`
+	// LatestRevision is the latest XDefinitionRevision of the XDefinition
+	// +optional
+	LatestRevision *XDefinitionRevisionRef `json:"latestRevision,omitempty"`
+}
+
+// XDefinitionRevisionRef refers to an XDefinitionRevision
+type XDefinitionRevisionRef struct {
+	Name         string `json:"name"`
+	Revision     int64  `json:"revision"`
+	RevisionHash string `json:"revisionHash"`
 }
 
 //+kubebuilder:object:root=true
@@ -84,6 +97,16 @@ type XDefinition struct {
 	Status XDefinitionStatus `json:"status,omitempty"`
 }
 
+// SetConditions set condition for XDefinition
+func (in *XDefinition) SetConditions(c ...condition.Condition) {
+	in.Status.SetConditions(c...)
+}
+
+// GetCondition gets condition from XDefinition
+func (in *XDefinition) GetCondition(conditionType condition.ConditionType) condition.Condition {
+	return in.Status.GetCondition(conditionType)
+}
+
 //+kubebuilder:object:root=true
 
 // XDefinitionList contains a list of XDefinition
@@ -0,0 +1,68 @@
+/*
+Copyright 2023 KDP(Kubernetes Data Platform).
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// XDefinitionRevisionSpec defines an immutable snapshot of an XDefinition
+type XDefinitionRevisionSpec struct {
+	// DefinitionName is the name of the XDefinition
+	DefinitionName string `json:"definitionName"`
+	// Revision is the number of the revision, which increases by one on every change of the XDefinition spec
+	Revision int64 `json:"revision"`
+	// DefinitionGeneration is the generation of the XDefinition when the revision is created
+	// +optional
+	DefinitionGeneration int64 `json:"definitionGeneration,omitempty"`
+	// RevisionHash is the hash of the XDefinition spec
+	RevisionHash string `json:"revisionHash"`
+	// Definition is the snapshot of the XDefinition spec, including the template
+	Definition XDefinitionSpec `json:"definition"`
+	// OpenAPISchema is the snapshot of the OpenAPI v3 JSON schema of the parameters
+	// +optional
+	OpenAPISchema string `json:"openAPISchema,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:scope=Cluster,shortName=xdefrev
+//+kubebuilder:printcolumn:name="Definition",type="string",JSONPath=`.spec.definitionName`
+//+kubebuilder:printcolumn:name="Revision",type="integer",JSONPath=`.spec.revision`
+//+kubebuilder:printcolumn:name="Hash",type="string",JSONPath=`.spec.revisionHash`
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=`.metadata.creationTimestamp`
+
+// XDefinitionRevision is the Schema for the xdefinitionrevisions API
+type XDefinitionRevision struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="spec of XDefinitionRevision is immutable"
+	Spec XDefinitionRevisionSpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// XDefinitionRevisionList contains a list of XDefinitionRevision
+type XDefinitionRevisionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []XDefinitionRevision `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&XDefinitionRevision{}, &XDefinitionRevisionList{})
+}
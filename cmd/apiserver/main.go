@@ -37,6 +37,7 @@ import (
 	bdcv1alpha1 "kdp-oam-operator/api/bdc/v1alpha1"
 	"kdp-oam-operator/pkg/apiserver"
 	"kdp-oam-operator/pkg/apiserver/config"
+	"kdp-oam-operator/pkg/apiserver/infrastructure/auth"
 	"kdp-oam-operator/pkg/apiserver/infrastructure/clients"
 	"kdp-oam-operator/pkg/utils/log"
 	"kdp-oam-operator/version"
@@ -61,6 +62,22 @@ func (s *Server) Flags() cliflag.NamedFlagSets {
 	cfs.StringVar(&s.serverConfig.LeaderConfig.ID, "id", s.serverConfig.LeaderConfig.ID, "the holder identity name")
 	cfs.StringVar(&s.serverConfig.LeaderConfig.LockName, "lock-name", s.serverConfig.LeaderConfig.LockName, "the lease lock resource name")
 	cfs.DurationVar(&s.serverConfig.LeaderConfig.Duration, "duration", s.serverConfig.LeaderConfig.Duration, "the lease lock resource name")
+	cfs.IntVar(&s.serverConfig.WatchCacheSize, "watch-cache-size", s.serverConfig.WatchCacheSize, "The number of the recent changes of each resource buffered for the watches to resume from, which is also the max number of the events pending for a watch.")
+	afs := fss.FlagSet("auth")
+	afs.StringVar(&s.serverConfig.Authentication.TokenAuthFile, "token-auth-file", s.serverConfig.Authentication.TokenAuthFile, "The static token file used to authenticate the bearer tokens, in the format of the kube-apiserver token file.")
+	afs.BoolVar(&s.serverConfig.Authentication.TokenReview, "authentication-token-review", s.serverConfig.Authentication.TokenReview, "Authenticate the bearer tokens by TokenReview of the kube-apiserver.")
+	afs.StringVar(&s.serverConfig.AuthorizationMode, "authorization-mode", s.serverConfig.AuthorizationMode, "The authorization mode of the requests, one of AlwaysAllow, SubjectAccessReview and Impersonation.")
+	tfs := fss.FlagSet("tenancy")
+	tfs.StringVar(&s.serverConfig.Tenancy.MembershipSource, "tenancy-membership-source", s.serverConfig.Tenancy.MembershipSource, "The source of the orgs and bigdata clusters the users belong to, one of Groups and ConfigMap. The tenancy is disabled if it's empty.")
+	tfs.StringVar(&s.serverConfig.Tenancy.OrgGroupPrefix, "tenancy-org-group-prefix", s.serverConfig.Tenancy.OrgGroupPrefix, "The prefix of the groups naming the orgs of the user, used by the Groups membership source.")
+	tfs.StringVar(&s.serverConfig.Tenancy.BDCGroupPrefix, "tenancy-bdc-group-prefix", s.serverConfig.Tenancy.BDCGroupPrefix, "The prefix of the groups naming the bigdata clusters of the user, used by the Groups membership source.")
+	tfs.StringVar(&s.serverConfig.Tenancy.MembershipConfigMap, "tenancy-membership-configmap", s.serverConfig.Tenancy.MembershipConfigMap, "The [namespace/]name of the membership configmap used by the ConfigMap membership source, the namespace defaults to the NAMESPACE env.")
+	tfs.StringSliceVar(&s.serverConfig.Tenancy.AdminGroups, "tenancy-admin-groups", s.serverConfig.Tenancy.AdminGroups, "The groups of the users seeing the objects of all the orgs and bigdata clusters.")
+	aufs := fss.FlagSet("audit")
+	aufs.StringVar(&s.serverConfig.Audit.LogPath, "audit-log-path", s.serverConfig.Audit.LogPath, "The path of the JSON lines file recording the mutating requests. The audit is disabled if it's empty.")
+	aufs.IntVar(&s.serverConfig.Audit.MaxSize, "audit-log-maxsize", s.serverConfig.Audit.MaxSize, "The size in megabytes of the audit log file to be rotated.")
+	aufs.IntVar(&s.serverConfig.Audit.MaxBackups, "audit-log-maxbackup", s.serverConfig.Audit.MaxBackups, "The max number of the rotated audit log files retained.")
+	aufs.IntVar(&s.serverConfig.Audit.MaxAge, "audit-log-maxage", s.serverConfig.Audit.MaxAge, "The max days of the rotated audit log files retained.")
 	s.serverConfig.GenericOptions.AddFlags(fss.FlagSet("generic"), &s.serverConfig.GenericOptions)
 
 	return fss
@@ -73,6 +90,19 @@ func main() {
 			SwaggerDocEnabled: false,
 			KubeQPS:           100,
 			KubeBurst:         300,
+			AuthorizationMode: auth.AuthorizationModeAlwaysAllow,
+			WatchCacheSize:    1000,
+			Tenancy: config.TenancyConfig{
+				OrgGroupPrefix:      "kdp:org:",
+				BDCGroupPrefix:      "kdp:bdc:",
+				MembershipConfigMap: "kdp-oam-apiserver-membership",
+				AdminGroups:         []string{"system:masters"},
+			},
+			Audit: config.AuditConfig{
+				MaxSize:    100,
+				MaxBackups: 10,
+				MaxAge:     30,
+			},
 			GenericOptions: options.GenericOptions{
 				LogLevel: "info",
 			},
@@ -22,8 +22,9 @@ import (
 	bdcv1alpha1 "kdp-oam-operator/api/bdc/v1alpha1"
 	"kdp-oam-operator/cmd/bdc/controller/options"
 	"kdp-oam-operator/pkg/common"
+	"kdp-oam-operator/pkg/controllers/adopt"
 	"kdp-oam-operator/pkg/controllers/bdc/v1alpha1"
-	"kdp-oam-operator/pkg/controllers/configmap"
+	"kdp-oam-operator/pkg/controllers/utils/metrics"
 	"kdp-oam-operator/pkg/webhook/bdc"
 	"kdp-oam-operator/version"
 	"strconv"
@@ -39,6 +40,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/manager/signals"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 )
 
 var (
@@ -129,6 +131,11 @@ func run(ctx context.Context, s *options.CoreOptions) error {
 		return err
 	}
 
+	if err := ctrlmetrics.Registry.Register(metrics.NewObjectCollector(mgr.GetCache())); err != nil {
+		klog.ErrorS(err, "Unable to register the object metrics collector")
+		return err
+	}
+
 	if s.WebhookEnable {
 		klog.InfoS("Enable webhook", "server port", strconv.Itoa(s.WebhookPort))
 		bdc.Register(mgr)
@@ -139,10 +146,10 @@ func run(ctx context.Context, s *options.CoreOptions) error {
 		return err
 	}
 
-	klog.InfoS("starting configmap syncer to ContextSetting process")
-	go func() {
-		err = configmap.RunCMSyncer(mgr, s)
-	}()
+	if err := adopt.Setup(mgr, *s.ControllerArgs); err != nil {
+		klog.ErrorS(err, "Unable to setup the configmap and secret syncers")
+		return err
+	}
 
 	setupLog.Info("starting manager")
 	if err := mgr.Start(ctx); err != nil {
@@ -65,6 +65,20 @@ func ConvertApplicationEntityToDTO(entity *entity.ApplicationEntity) (*v1dto.App
 	return appBase, nil
 }
 
+func ConvertApplicationRevisionEntityToDTO(entity *entity.ApplicationRevisionEntity) *v1dto.ApplicationRevisionBase {
+	return &v1dto.ApplicationRevisionBase{
+		Name:                  entity.Name,
+		AppName:               entity.AppName,
+		Revision:              entity.Revision,
+		Properties:            entity.Properties,
+		XDefinitionName:       entity.XDefinitionName,
+		XDefinitionGeneration: entity.XDefinitionGeneration,
+		ManifestHash:          entity.ManifestHash,
+		Author:                entity.Author,
+		CreateTime:            entity.CreateTime,
+	}
+}
+
 func ConvertContextSecretEntityToDTO(entity *entity.ContextSecretEntity) (*v1dto.ContextSecretBase, error) {
 	bdcBase, err := ConvertBigDataClusterEntityToDTO(entity.BDC)
 	if err != nil {
@@ -81,6 +95,7 @@ func ConvertContextSecretEntityToDTO(entity *entity.ContextSecretEntity) (*v1dto
 		BDC:         bdcBase,
 		Labels:      entity.Labels,
 		Annotations: entity.Annotations,
+		Status:      entity.Status,
 	}
 	return ctxSecretBase, nil
 }
@@ -101,6 +116,7 @@ func ConvertContextSettingEntityToDTO(entity *entity.ContextSettingEntity) (*v1d
 		BDC:         bdcBase,
 		Labels:      entity.Labels,
 		Annotations: entity.Annotations,
+		Status:      entity.Status,
 	}
 	return ctxSettingBase, nil
 }
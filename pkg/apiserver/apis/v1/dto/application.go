@@ -85,9 +85,11 @@ type GetApplicationsResponse struct {
 
 // ListApplicationsResponse list applications by query params
 type ListApplicationsResponse struct {
-	Data    []*ApplicationBase `json:"data"`
-	Message string             `json:"message"`
-	Status  int                `json:"status"`
+	Data     []*ApplicationBase `json:"data"`
+	Message  string             `json:"message"`
+	Status   int                `json:"status"`
+	Total    int                `json:"total"`
+	Continue string             `json:"continue,omitempty"`
 }
 
 type ApplicationStatus struct {
@@ -100,3 +102,28 @@ type ApplicationRawResponse struct {
 	Message string                `json:"message"`
 	Status  int                   `json:"status"`
 }
+
+type ApplicationRevisionBase struct {
+	Name                  string                `json:"name"`
+	AppName               string                `json:"appName"`
+	Revision              int64                 `json:"revision"`
+	Properties            *runtime.RawExtension `json:"properties"`
+	XDefinitionName       string                `json:"xDefinitionName"`
+	XDefinitionGeneration int64                 `json:"xDefinitionGeneration"`
+	ManifestHash          string                `json:"manifestHash"`
+	Author                string                `json:"author"`
+	CreateTime            metav1.Time           `json:"createTime"`
+}
+
+type GetApplicationRevisionResponse struct {
+	Data    *ApplicationRevisionBase `json:"data"`
+	Message string                   `json:"message"`
+	Status  int                      `json:"status"`
+}
+
+// ListApplicationRevisionsResponse list revisions of an application from the latest to the oldest
+type ListApplicationRevisionsResponse struct {
+	Data    []*ApplicationRevisionBase `json:"data"`
+	Message string                     `json:"message"`
+	Status  int                        `json:"status"`
+}
@@ -58,9 +58,11 @@ type GetBigDataClusterResponse struct {
 
 // ListBigDataClustersResponse list bigdata clusters by query params
 type ListBigDataClustersResponse struct {
-	Data    []*BigDataClusterBase `json:"data"`
-	Message string                `json:"message"`
-	Status  int                   `json:"status"`
+	Data     []*BigDataClusterBase `json:"data"`
+	Message  string                `json:"message"`
+	Status   int                   `json:"status"`
+	Total    int                   `json:"total"`
+	Continue string                `json:"continue,omitempty"`
 }
 
 type BigDataClusterStatus struct {
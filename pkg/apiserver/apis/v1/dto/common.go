@@ -17,10 +17,34 @@ limitations under the License.
 
 package dto
 
+import (
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
 type CommonResponse struct {
 	Message string `json:"message"`
 	Status  int    `json:"status"`
 }
 type ListOptions struct {
 	Labels map[string]string `json:"labels"`
+	// LabelSelector restricts the objects by their labels together with Labels, supports the set-based requirements
+	LabelSelector labels.Selector `json:"-"`
+	// Types and Statuses restrict the objects by their types and status phases, empty matches everything
+	Types    sets.String `json:"types,omitempty"`
+	Statuses sets.String `json:"statuses,omitempty"`
+	// SortBy is one of name, createTime, updateTime and status, defaults to name
+	SortBy string `json:"sortBy,omitempty"`
+	// Order is asc or desc, defaults to asc
+	Order string `json:"order,omitempty"`
+	// Limit is the max number of the objects returned, 0 returns all of them
+	Limit int64 `json:"limit,omitempty"`
+	// Continue is the token of the next page returned by the previous list
+	Continue string `json:"continue,omitempty"`
+}
+
+// ListMeta is the total number of the objects matching the list options, and the token of the next page
+type ListMeta struct {
+	Total    int    `json:"total"`
+	Continue string `json:"continue,omitempty"`
 }
@@ -42,9 +42,11 @@ type GetContextSecretResponse struct {
 }
 
 type ListContextSecretsResponse struct {
-	Data    []*ContextSecretBase `json:"data"`
-	Message string               `json:"message"`
-	Status  int                  `json:"status"`
+	Data     []*ContextSecretBase `json:"data"`
+	Message  string               `json:"message"`
+	Status   int                  `json:"status"`
+	Total    int                  `json:"total"`
+	Continue string               `json:"continue,omitempty"`
 }
 
 type GetContextSecretDefSchemaResponse struct {
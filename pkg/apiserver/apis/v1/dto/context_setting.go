@@ -42,9 +42,11 @@ type GetContextSettingResponse struct {
 }
 
 type ListContextSettingsResponse struct {
-	Data    []*ContextSettingBase `json:"data"`
-	Message string                `json:"message"`
-	Status  int                   `json:"status"`
+	Data     []*ContextSettingBase `json:"data"`
+	Message  string                `json:"message"`
+	Status   int                   `json:"status"`
+	Total    int                   `json:"total"`
+	Continue string                `json:"continue,omitempty"`
 }
 
 type GetContextSettingDefSchemaResponse struct {
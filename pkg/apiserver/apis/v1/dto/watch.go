@@ -0,0 +1,24 @@
+/*
+Copyright 2024 KDP(Kubernetes Data Platform).
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dto
+
+// WatchEvent is the data of the server-sent event of a watch, Type is ADDED, MODIFIED or DELETED, Object is the base
+// of the object, the deleted object is its last state
+type WatchEvent struct {
+	Type   string      `json:"type"`
+	Object interface{} `json:"object"`
+}
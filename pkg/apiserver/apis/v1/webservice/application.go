@@ -24,7 +24,6 @@ import (
 	"kdp-oam-operator/pkg/controllers/bdc/constants"
 	pkgutils "kdp-oam-operator/pkg/utils"
 	"kdp-oam-operator/pkg/utils/log"
-	"strings"
 
 	apiErrors "k8s.io/apimachinery/pkg/api/errors"
 
@@ -32,8 +31,8 @@ import (
 	"k8s.io/klog/v2"
 )
 
-func (c *BigDataClusterWebService) applicationMetaCacheParse(appName string) (*v1dto.ApplicationBase, error) {
-	bdcApp, err := c.ApplicationService.GetApplication(context.Background(), appName)
+func (c *BigDataClusterWebService) applicationMetaCacheParse(ctx context.Context, appName string) (*v1dto.ApplicationBase, error) {
+	bdcApp, err := c.ApplicationService.GetApplication(ctx, appName)
 	if err != nil {
 		log.Logger.Errorw("get bigdata cluster failure", "error", err)
 		return nil, err
@@ -48,26 +47,21 @@ func (c *BigDataClusterWebService) applicationMetaCacheParse(appName string) (*v
 
 func (c *BigDataClusterWebService) listApplications(request *restful.Request, response *restful.Response) {
 	bdcName := request.QueryParameter("bdcName")
-	labels := map[string]string{}
-	if request.QueryParameter("labelSelector") != "" {
-		allLabels := strings.Split(request.QueryParameter("labelSelector"), ",")
-		for _, label := range allLabels {
-			kv := strings.Split(label, "=")
-			if len(kv) == 2 {
-				labels[kv[0]] = kv[1]
-			}
-		}
+	options, err := parseListOptions(request)
+	if err != nil {
+		exception.ReturnError(request, response, err)
+		return
 	}
 	if bdcName != "" {
 		// If specify bdcName, then we need to check the bdc first
-		bdc, err := c.bigDataClusterMetaCacheParse(bdcName)
+		bdc, err := c.bigDataClusterMetaCacheParse(request.Request.Context(), bdcName)
 		if err != nil {
 			exception.ReturnError(request, response, exception.ErrBigDataClusterNotFound)
 			return
 		}
-		labels[constants.AnnotationBDCName] = bdc.Name
+		options.Labels[constants.AnnotationBDCName] = bdc.Name
 	}
-	apps, err := c.ApplicationService.ListApplications(request.Request.Context(), v1dto.ListOptions{Labels: labels})
+	apps, listMeta, err := c.ApplicationService.ListApplications(request.Request.Context(), options)
 	if err != nil {
 		exception.ReturnError(request, response, err)
 		return
@@ -82,9 +76,11 @@ func (c *BigDataClusterWebService) listApplications(request *restful.Request, re
 		listRtn = append(listRtn, appBase)
 	}
 	if err := response.WriteEntity(v1dto.ListApplicationsResponse{
-		Data:    listRtn,
-		Message: "success",
-		Status:  0,
+		Data:     listRtn,
+		Message:  "success",
+		Status:   0,
+		Total:    listMeta.Total,
+		Continue: listMeta.Continue,
 	}); err != nil {
 		// bcode.ReturnError(req, res, err)
 		return
@@ -143,7 +139,7 @@ func (c *BigDataClusterWebService) createApplication(request *restful.Request, r
 		exception.ReturnError(request, response, err)
 		return
 	}
-	bdc, err := c.bigDataClusterMetaCacheParse(bdcName)
+	bdc, err := c.bigDataClusterMetaCacheParse(request.Request.Context(), bdcName)
 	if err != nil {
 		exception.ReturnError(request, response, exception.ErrBigDataClusterNotFound)
 		return
@@ -167,7 +163,7 @@ func (c *BigDataClusterWebService) createApplication(request *restful.Request, r
 
 func (c *BigDataClusterWebService) updateApplication(request *restful.Request, response *restful.Response) {
 	appName := request.PathParameter("appName")
-	app, err := c.applicationMetaCacheParse(appName)
+	app, err := c.applicationMetaCacheParse(request.Request.Context(), appName)
 	if err != nil {
 		if apiErrors.IsNotFound(err) {
 			exception.ReturnError(request, response, exception.ErrApplicationNotFound)
@@ -241,7 +237,7 @@ func (c *BigDataClusterWebService) getApplicationDefinitionSchema(request *restf
 
 func (c *BigDataClusterWebService) deleteApplicationPod(request *restful.Request, response *restful.Response) {
 	appName := request.PathParameter("appName")
-	app, err := c.applicationMetaCacheParse(appName)
+	app, err := c.applicationMetaCacheParse(request.Request.Context(), appName)
 	if err != nil {
 		if apiErrors.IsNotFound(err) {
 			exception.ReturnError(request, response, exception.ErrApplicationNotFound)
@@ -266,3 +262,68 @@ func (c *BigDataClusterWebService) deleteApplicationPod(request *restful.Request
 		return
 	}
 }
+
+func (c *BigDataClusterWebService) listApplicationRevisions(request *restful.Request, response *restful.Response) {
+	appName := request.PathParameter("appName")
+	if _, err := c.ApplicationService.GetApplication(request.Request.Context(), appName); err != nil {
+		exception.ReturnError(request, response, exception.ErrApplicationNotFound)
+		return
+	}
+	revisions, err := c.ApplicationService.ListApplicationRevisions(request.Request.Context(), appName)
+	if err != nil {
+		exception.ReturnError(request, response, err)
+		return
+	}
+	listRtn := make([]*v1dto.ApplicationRevisionBase, 0, len(revisions))
+	for _, item := range revisions {
+		listRtn = append(listRtn, assembler.ConvertApplicationRevisionEntityToDTO(item))
+	}
+	if err := response.WriteEntity(v1dto.ListApplicationRevisionsResponse{
+		Data:    listRtn,
+		Message: "success",
+		Status:  0,
+	}); err != nil {
+		return
+	}
+}
+
+func (c *BigDataClusterWebService) getApplicationRevision(request *restful.Request, response *restful.Response) {
+	appName := request.PathParameter("appName")
+	revision, err := c.ApplicationService.GetApplicationRevision(request.Request.Context(), appName, request.PathParameter("revision"))
+	if err != nil {
+		if apiErrors.IsNotFound(err) {
+			exception.ReturnError(request, response, exception.ErrApplicationRevisionNotFound)
+			return
+		}
+		exception.ReturnError(request, response, err)
+		return
+	}
+	if err := response.WriteEntity(v1dto.GetApplicationRevisionResponse{
+		Data:    assembler.ConvertApplicationRevisionEntityToDTO(revision),
+		Message: "success",
+		Status:  0,
+	}); err != nil {
+		return
+	}
+}
+
+func (c *BigDataClusterWebService) rollbackApplication(request *restful.Request, response *restful.Response) {
+	appName := request.PathParameter("appName")
+	revision := request.PathParameter("revision")
+	if err := c.ApplicationService.RollbackApplication(request.Request.Context(), appName, revision); err != nil {
+		klog.Errorf("rollback application failure %s", err.Error())
+		if apiErrors.IsNotFound(err) {
+			exception.ReturnError(request, response, exception.ErrApplicationRevisionNotFound)
+			return
+		}
+		exception.ReturnError(request, response, err)
+		return
+	}
+	if err := response.WriteEntity(v1dto.GetApplicationsResponse{
+		Data:    nil,
+		Message: "success",
+		Status:  0,
+	}); err != nil {
+		return
+	}
+}
@@ -27,7 +27,7 @@ import (
 
 func (c *BigDataClusterWebService) getApplicationPods(request *restful.Request, response *restful.Response) {
 	appName := request.PathParameter("appName")
-	app, err := c.applicationMetaCacheParse(appName)
+	app, err := c.applicationMetaCacheParse(request.Request.Context(), appName)
 	if err != nil {
 		exception.ReturnError(request, response, exception.ErrApplicationNotFound)
 		return
@@ -51,7 +51,7 @@ func (c *BigDataClusterWebService) getApplicationPods(request *restful.Request,
 
 func (c *BigDataClusterWebService) getApplicationPodsDetail(request *restful.Request, response *restful.Response) {
 	appName := request.PathParameter("appName")
-	app, err := c.applicationMetaCacheParse(appName)
+	app, err := c.applicationMetaCacheParse(request.Request.Context(), appName)
 	if err != nil {
 		exception.ReturnError(request, response, exception.ErrApplicationNotFound)
 		return
@@ -77,7 +77,7 @@ func (c *BigDataClusterWebService) getApplicationPodsDetail(request *restful.Req
 
 func (c *BigDataClusterWebService) getApplicationPodLogs(request *restful.Request, response *restful.Response) {
 	appName := request.PathParameter("appName")
-	app, err := c.applicationMetaCacheParse(appName)
+	app, err := c.applicationMetaCacheParse(request.Request.Context(), appName)
 	if err != nil {
 		exception.ReturnError(request, response, exception.ErrApplicationNotFound)
 		return
@@ -107,7 +107,7 @@ func (c *BigDataClusterWebService) getApplicationPodLogs(request *restful.Reques
 
 func (c *BigDataClusterWebService) getApplicationServiceEndpoints(request *restful.Request, response *restful.Response) {
 	appName := request.PathParameter("appName")
-	app, err := c.applicationMetaCacheParse(appName)
+	app, err := c.applicationMetaCacheParse(request.Request.Context(), appName)
 	if err != nil {
 		exception.ReturnError(request, response, exception.ErrApplicationNotFound)
 		return
@@ -131,7 +131,7 @@ func (c *BigDataClusterWebService) getApplicationServiceEndpoints(request *restf
 
 func (c *BigDataClusterWebService) getApplicationAppliedResources(request *restful.Request, response *restful.Response) {
 	appName := request.PathParameter("appName")
-	app, err := c.applicationMetaCacheParse(appName)
+	app, err := c.applicationMetaCacheParse(request.Request.Context(), appName)
 	if err != nil {
 		exception.ReturnError(request, response, exception.ErrApplicationNotFound)
 		return
@@ -155,7 +155,7 @@ func (c *BigDataClusterWebService) getApplicationAppliedResources(request *restf
 
 func (c *BigDataClusterWebService) getApplicationResourceTopology(request *restful.Request, response *restful.Response) {
 	appName := request.PathParameter("appName")
-	app, err := c.applicationMetaCacheParse(appName)
+	app, err := c.applicationMetaCacheParse(request.Request.Context(), appName)
 	if err != nil {
 		exception.ReturnError(request, response, exception.ErrApplicationNotFound)
 		return
@@ -178,11 +178,29 @@ func (c *BigDataClusterWebService) getApplicationResourceTopology(request *restf
 }
 
 func (c *BigDataClusterWebService) getApplicationResourceDetail(request *restful.Request, response *restful.Response) {
+	appName := request.PathParameter("appName")
+	app, err := c.applicationMetaCacheParse(request.Request.Context(), appName)
+	if err != nil {
+		exception.ReturnError(request, response, exception.ErrApplicationNotFound)
+		return
+	}
+
 	resNs := request.QueryParameter("resNs")
 	resName := request.QueryParameter("resName")
 	resKind := request.QueryParameter("resKind")
 	resAPIVersion := request.QueryParameter("resAPIVersion")
 
+	// only the resources applied by the application are readable, the others may belong to other tenants
+	appAppliedResources, err := c.ApplicationResourcesService.GetApplicationAppliedResources(request.Request.Context(), app.AppRuntimeNs, app.AppRuntimeName)
+	if err != nil {
+		exception.ReturnError(request, response, err)
+		return
+	}
+	if !appliedResource(appAppliedResources["resources"], resNs, resName, resKind, resAPIVersion) {
+		exception.ReturnError(request, response, exception.ErrApplicationResourceNotFound)
+		return
+	}
+
 	appResourcesDetailSpec, err := c.ApplicationResourcesService.GetApplicationResourcesDetail(request.Request.Context(), resNs, resName, resKind, resAPIVersion)
 	if err != nil {
 		exception.ReturnError(request, response, err)
@@ -198,3 +216,24 @@ func (c *BigDataClusterWebService) getApplicationResourceDetail(request *restful
 		return
 	}
 }
+
+// appliedResource returns whether the resource is in the applied resources of the application in the local cluster
+func appliedResource(resources interface{}, resNs, resName, resKind, resAPIVersion string) bool {
+	items, _ := resources.([]interface{})
+	for _, item := range items {
+		res, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		cluster, _ := res["cluster"].(string)
+		namespace, _ := res["namespace"].(string)
+		name, _ := res["name"].(string)
+		kind, _ := res["kind"].(string)
+		apiVersion, _ := res["apiVersion"].(string)
+		if (cluster == "" || cluster == "local") && namespace == resNs && name == resName &&
+			kind == resKind && apiVersion == resAPIVersion {
+			return true
+		}
+	}
+	return false
+}
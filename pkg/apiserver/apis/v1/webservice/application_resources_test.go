@@ -0,0 +1,129 @@
+/*
+Copyright 2024 KDP(Kubernetes Data Platform).
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webservice
+
+import (
+	"context"
+	"kdp-oam-operator/pkg/apiserver/domain/entity"
+	"kdp-oam-operator/pkg/apiserver/domain/service"
+	"kdp-oam-operator/pkg/apiserver/infrastructure/tenancy"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/emicklei/go-restful/v3"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// fakeApplicationService serves the applications of its map, hiding the ones invisible to the caller
+type fakeApplicationService struct {
+	service.ApplicationService
+	apps map[string]*entity.ApplicationEntity
+}
+
+func (f *fakeApplicationService) GetApplication(ctx context.Context, appName string) (*entity.ApplicationEntity, error) {
+	app, ok := f.apps[appName]
+	if !ok || !tenancy.MembershipFrom(ctx).Allows(app.BDC.OrgName, app.BDC.Name) {
+		return nil, apierrors.NewNotFound(schema.GroupResource{Resource: "applications"}, appName)
+	}
+	return app, nil
+}
+
+// fakeApplicationResourcesService serves the applied resources of its map and records the detailed resources
+type fakeApplicationResourcesService struct {
+	service.ApplicationResourcesService
+	applied  map[string][]interface{}
+	detailed []string
+}
+
+func (f *fakeApplicationResourcesService) GetApplicationAppliedResources(_ context.Context, appNs, appName string) (map[string]interface{}, error) {
+	return map[string]interface{}{"resources": f.applied[appNs+"/"+appName]}, nil
+}
+
+func (f *fakeApplicationResourcesService) GetApplicationResourcesDetail(_ context.Context, resNs, resName, _, _ string) (map[string]interface{}, error) {
+	f.detailed = append(f.detailed, resNs+"/"+resName)
+	return map[string]interface{}{"resource": map[string]interface{}{"name": resName}}, nil
+}
+
+func TestGetApplicationResourceDetail(t *testing.T) {
+	newApp := func(org, bdc, name string) *entity.ApplicationEntity {
+		return &entity.ApplicationEntity{
+			Name:           bdc + "-" + name,
+			AppRuntimeName: name,
+			AppRuntimeNs:   bdc,
+			BDC:            &entity.BigDataClusterEntity{Name: bdc, OrgName: org},
+		}
+	}
+	deployment := func(ns, name string) interface{} {
+		return map[string]interface{}{"cluster": "local", "namespace": ns, "name": name, "kind": "Deployment", "apiVersion": "apps/v1"}
+	}
+	resourcesService := &fakeApplicationResourcesService{applied: map[string][]interface{}{
+		"bdc-a/hdfs": {deployment("bdc-a", "hdfs-namenode")},
+		"bdc-b/hdfs": {deployment("bdc-b", "hdfs-namenode")},
+	}}
+	c := &BigDataClusterWebService{
+		ApplicationService: &fakeApplicationService{apps: map[string]*entity.ApplicationEntity{
+			"bdc-a-hdfs": newApp("org-a", "bdc-a", "hdfs"),
+			"bdc-b-hdfs": newApp("org-b", "bdc-b", "hdfs"),
+		}},
+		ApplicationResourcesService: resourcesService,
+	}
+	m := tenancy.NewMembership()
+	m.Orgs.Insert("org-a")
+	ctx := tenancy.WithMembership(context.Background(), m)
+
+	cases := map[string]struct {
+		appName  string
+		resNs    string
+		resName  string
+		wantCode int
+	}{
+		"applied resource": {
+			appName: "bdc-a-hdfs", resNs: "bdc-a", resName: "hdfs-namenode", wantCode: http.StatusOK,
+		},
+		"application of another tenant": {
+			appName: "bdc-b-hdfs", resNs: "bdc-b", resName: "hdfs-namenode", wantCode: http.StatusNotFound,
+		},
+		"resource of another tenant through a visible application": {
+			appName: "bdc-a-hdfs", resNs: "bdc-b", resName: "hdfs-namenode", wantCode: http.StatusNotFound,
+		},
+		"resource not applied by the application": {
+			appName: "bdc-a-hdfs", resNs: "kube-system", resName: "coredns", wantCode: http.StatusNotFound,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			resourcesService.detailed = nil
+			httpReq := httptest.NewRequest(http.MethodGet, "/api/v1/applications/"+tc.appName+"/resources/detail?resNs="+tc.resNs+
+				"&resName="+tc.resName+"&resKind=Deployment&resAPIVersion=apps/v1", nil).WithContext(ctx)
+			req := restful.NewRequest(httpReq)
+			req.PathParameters()["appName"] = tc.appName
+			recorder := httptest.NewRecorder()
+			res := restful.NewResponse(recorder)
+			res.SetRequestAccepts(restful.MIME_JSON)
+
+			c.getApplicationResourceDetail(req, res)
+			if recorder.Code != tc.wantCode {
+				t.Errorf("status code = %d, want %d: %s", recorder.Code, tc.wantCode, recorder.Body.String())
+			}
+			if tc.wantCode != http.StatusOK && len(resourcesService.detailed) != 0 {
+				t.Errorf("detailed resources = %v, want none", resourcesService.detailed)
+			}
+		})
+	}
+}
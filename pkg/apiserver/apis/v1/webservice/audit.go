@@ -0,0 +1,120 @@
+/*
+Copyright 2024 KDP(Kubernetes Data Platform).
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webservice
+
+import (
+	bdcv1alpha1 "kdp-oam-operator/api/bdc/v1alpha1"
+	baseTypes "kdp-oam-operator/pkg/apiserver/apis/base/types"
+	v1dto "kdp-oam-operator/pkg/apiserver/apis/v1/dto"
+	"kdp-oam-operator/pkg/apiserver/domain/service"
+	"kdp-oam-operator/pkg/apiserver/exception"
+	"kdp-oam-operator/pkg/apiserver/infrastructure/audit"
+	"kdp-oam-operator/pkg/apiserver/infrastructure/auth"
+	"kdp-oam-operator/pkg/utils/log"
+	"strconv"
+	"time"
+
+	restfulspec "github.com/emicklei/go-restful-openapi/v2"
+	"github.com/emicklei/go-restful/v3"
+)
+
+// defaultAuditLimit is the number of the audit events returned if the limit isn't specified
+const defaultAuditLimit = 100
+
+type AuditWebService struct {
+	AuditService service.AuditService
+}
+
+func NewAuditWebService(auditService service.AuditService) WebService {
+	return &AuditWebService{AuditService: auditService}
+}
+
+func (a *AuditWebService) GetWebService() *restful.WebService {
+	ws := new(restful.WebService)
+	ws.Path(versionPrefix+"/auditevents").
+		Consumes(restful.MIME_JSON, restful.MIME_JSON).
+		Produces(restful.MIME_JSON, restful.MIME_JSON).
+		Doc("api for the audit log of the mutating requests")
+
+	auditTags := []string{"audit"}
+
+	ws.Route(ws.GET("/").To(a.listAuditEvents).
+		Doc("list the audit events from the latest, only for the callers not restricted by the tenancy").
+		Metadata(restfulspec.KeyOpenAPITags, auditTags).
+		// the audit log is read locally, so the access is authorized even if the kube clients impersonate the caller
+		Metadata(auth.KeyResource, auth.Resource{Group: bdcv1alpha1.GroupVersion.Group, Resource: "auditevents", Verb: "list", Local: true}).
+		Param(ws.QueryParameter("bdcName", "name of the bigdata cluster of the target objects").DataType("string").Required(false)).
+		Param(ws.QueryParameter("appName", "name of the target application").DataType("string").Required(false)).
+		Param(ws.QueryParameter("since", "RFC3339 time, the events at or after it are returned").DataType("string").Required(false)).
+		Param(ws.QueryParameter("until", "RFC3339 time, the events before it are returned").DataType("string").Required(false)).
+		Param(ws.QueryParameter("limit", "max number of the events returned, defaults to 100").DataType("integer").Required(false)).
+		Writes(v1dto.ListAuditEventsResponse{}).
+		Returns(200, "OK", v1dto.ListAuditEventsResponse{}).
+		Returns(400, "Bad request", baseTypes.BadRequestResponse{}).
+		Returns(404, "Not found", baseTypes.NotFoundResponse{}))
+
+	return ws
+}
+
+func (a *AuditWebService) listAuditEvents(request *restful.Request, response *restful.Response) {
+	query, err := auditQuery(request)
+	if err != nil {
+		exception.ReturnError(request, response, err)
+		return
+	}
+	events, err := a.AuditService.ListAuditEvents(request.Request.Context(), query)
+	if err != nil {
+		exception.ReturnError(request, response, err)
+		return
+	}
+	if events == nil {
+		events = make([]*audit.Event, 0)
+	}
+	if err := response.WriteEntity(v1dto.ListAuditEventsResponse{
+		Data:    events,
+		Message: "success",
+		Status:  0,
+	}); err != nil {
+		log.Logger.Errorf("write entity failure %s", err.Error())
+	}
+}
+
+// auditQuery parses the audit query from the query parameters
+func auditQuery(request *restful.Request) (audit.Query, error) {
+	query := audit.Query{
+		BigDataCluster: request.QueryParameter("bdcName"),
+		Application:    request.QueryParameter("appName"),
+		Limit:          defaultAuditLimit,
+	}
+	var err error
+	if since := request.QueryParameter("since"); since != "" {
+		if query.Since, err = time.Parse(time.RFC3339, since); err != nil {
+			return query, exception.ErrInvalidAuditQuery
+		}
+	}
+	if until := request.QueryParameter("until"); until != "" {
+		if query.Until, err = time.Parse(time.RFC3339, until); err != nil {
+			return query, exception.ErrInvalidAuditQuery
+		}
+	}
+	if limit := request.QueryParameter("limit"); limit != "" {
+		if query.Limit, err = strconv.Atoi(limit); err != nil || query.Limit <= 0 {
+			return query, exception.ErrInvalidAuditQuery
+		}
+	}
+	return query, nil
+}
@@ -18,11 +18,13 @@ package webservice
 
 import (
 	"context"
+	bdcv1alpha1 "kdp-oam-operator/api/bdc/v1alpha1"
 	baseTypes "kdp-oam-operator/pkg/apiserver/apis/base/types"
 	"kdp-oam-operator/pkg/apiserver/apis/v1/assembler"
 	v1dto "kdp-oam-operator/pkg/apiserver/apis/v1/dto"
 	"kdp-oam-operator/pkg/apiserver/domain/service"
 	"kdp-oam-operator/pkg/apiserver/exception"
+	"kdp-oam-operator/pkg/apiserver/infrastructure/auth"
 	"kdp-oam-operator/pkg/utils/log"
 	"strings"
 
@@ -72,6 +74,7 @@ func (c *BigDataClusterWebService) GetWebService() *restful.WebService {
 	ws.Route(ws.POST("/terminal").To(c.createGeneralTerminal).
 		Doc("create general terminal").
 		Metadata(restfulspec.KeyOpenAPITags, terminalTags).
+		Metadata(auth.KeyResource, auth.Resource{Group: "cloudshell.cloudtty.io", Resource: "cloudshells", Verb: "create"}).
 		Writes(v1dto.WebTerminalResponse{}).
 		Returns(200, "OK", v1dto.WebTerminalResponse{}).
 		Returns(400, "Bad request", baseTypes.BadRequestResponse{}))
@@ -81,7 +84,8 @@ func (c *BigDataClusterWebService) GetWebService() *restful.WebService {
 	ws.Route(ws.GET("/bigdataclusters/").To(c.listBigDataClusters).
 		Doc("list objects of kind bdc").
 		Metadata(restfulspec.KeyOpenAPITags, tags).
-		Param(ws.QueryParameter("labelSelector", "A selector to restrict the list of returned objects by their labels. Defaults to everything.").DataType("string").Required(false)).
+		Metadata(auth.KeyResource, accesses("list", "bigdataclusters", "")).
+		Do(listParams(ws, "status")).
 		//Param(ws.QueryParameter("fieldSelector", "A selector to restrict the list of returned objects by their fields. Defaults to everything.").DataType("string").Required(false)).
 		//Param(ws.QueryParameter("pretty", "If 'true', then the output is pretty printed.").DataType("string").Required(false)).
 		//Param(ws.QueryParameter("timeoutSeconds", "Timeout for the list/watch call. This limits the duration of the call, regardless of any activity or inactivity.").DataType("integer").Required(false)).
@@ -93,6 +97,7 @@ func (c *BigDataClusterWebService) GetWebService() *restful.WebService {
 	ws.Route(ws.GET("/bigdataclusters/{bdcName}").To(c.getBigDataCluster).
 		Doc("get the specified bdc").
 		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Metadata(auth.KeyResource, accesses("get", "bigdataclusters", "bdcName")).
 		Param(ws.PathParameter("bdcName", "name of the bigdata cluster").DataType("string").Required(true)).
 		Writes(v1dto.GetBigDataClusterResponse{}).
 		Returns(200, "OK", v1dto.GetBigDataClusterResponse{}).
@@ -104,8 +109,9 @@ func (c *BigDataClusterWebService) GetWebService() *restful.WebService {
 	ws.Route(ws.GET("/applications").To(c.listApplications).
 		Doc("list objects of kind bdc application").
 		Metadata(restfulspec.KeyOpenAPITags, applicationTags).
+		Metadata(auth.KeyResource, accesses("list", "applications", "")).
 		Param(ws.QueryParameter("bdcName", "name of the bigdata cluster").DataType("string").Required(false)).
-		Param(ws.QueryParameter("labelSelector", "A selector to restrict the list of returned objects by their labels. Defaults to everything.").DataType("string").Required(false)).
+		Do(listParams(ws, "type", "status")).
 		Writes(v1dto.ListApplicationsResponse{}).
 		Returns(200, "OK", v1dto.ListApplicationsResponse{}).
 		Returns(400, "Bad request", baseTypes.BadRequestResponse{}).
@@ -114,6 +120,7 @@ func (c *BigDataClusterWebService) GetWebService() *restful.WebService {
 	ws.Route(ws.GET("/applications/{appName}").To(c.getApplication).
 		Doc("read the specified bdc application").
 		Metadata(restfulspec.KeyOpenAPITags, applicationTags).
+		Metadata(auth.KeyResource, accesses("get", "applications", "appName")).
 		Param(ws.PathParameter("appName", "name of the bdc application").DataType("string").Required(true)).
 		Writes(v1dto.GetApplicationsResponse{}).
 		Returns(200, "OK", v1dto.GetApplicationsResponse{}).
@@ -123,6 +130,7 @@ func (c *BigDataClusterWebService) GetWebService() *restful.WebService {
 	ws.Route(ws.GET("/bigdataclusters/{bdcName}/applications/definitions/{defType}/schema").To(c.getApplicationDefinitionSchema).
 		Doc("read bdc application definition schema").
 		Metadata(restfulspec.KeyOpenAPITags, applicationTags).
+		Metadata(auth.KeyResource, accesses("get", "xdefinitions", "")).
 		Param(ws.PathParameter("bdcName", "name of the bigdata cluster").DataType("string").Required(true)).
 		Param(ws.PathParameter("defType", "name of the bdc application definition type").DataType("string").Required(true)).
 		Writes(v1dto.GetXDefinitionResponse{}).
@@ -133,6 +141,7 @@ func (c *BigDataClusterWebService) GetWebService() *restful.WebService {
 	ws.Route(ws.POST("/bigdataclusters/{bdcName}/applications").To(c.createApplication).
 		Doc("create bdc application").
 		Metadata(restfulspec.KeyOpenAPITags, applicationTags).
+		Metadata(auth.KeyResource, accesses("create", "applications", "")).
 		Param(ws.PathParameter("bdcName", "name of the bigdata cluster").DataType("string").Required(true)).
 		Reads(v1dto.CreateApplicationRequestModel{}).
 		Writes(v1dto.GetApplicationsResponse{}).
@@ -142,6 +151,7 @@ func (c *BigDataClusterWebService) GetWebService() *restful.WebService {
 	ws.Route(ws.PUT("/applications/{appName}").To(c.updateApplication).
 		Doc("update bdc application").
 		Metadata(restfulspec.KeyOpenAPITags, applicationTags).
+		Metadata(auth.KeyResource, accesses("update", "applications", "appName")).
 		Param(ws.PathParameter("appName", "name of the bdc application").DataType("string").Required(true)).
 		Reads(v1dto.UpdateApplicationRequestModel{}).
 		Writes(v1dto.ApplicationBase{}).
@@ -151,15 +161,48 @@ func (c *BigDataClusterWebService) GetWebService() *restful.WebService {
 	ws.Route(ws.DELETE("/applications/{appName}").To(c.deleteApplication).
 		Doc("delete the specified bdc application").
 		Metadata(restfulspec.KeyOpenAPITags, applicationTags).
+		Metadata(auth.KeyResource, accesses("delete", "applications", "appName")).
 		Param(ws.PathParameter("appName", "name of the bdc application").DataType("string").Required(true)).
 		Returns(200, "OK", baseTypes.HTTPResponse{}).
 		Returns(400, "Bad request", baseTypes.BadRequestResponse{}))
 
+	ws.Route(ws.GET("/applications/{appName}/revisions").To(c.listApplicationRevisions).
+		Doc("list revisions of the specified bdc application, from the latest to the oldest").
+		Metadata(restfulspec.KeyOpenAPITags, applicationTags).
+		Metadata(auth.KeyResource, accesses("list", "applicationrevisions", "")).
+		Param(ws.PathParameter("appName", "name of the bdc application").DataType("string").Required(true)).
+		Writes(v1dto.ListApplicationRevisionsResponse{}).
+		Returns(200, "OK", v1dto.ListApplicationRevisionsResponse{}).
+		Returns(400, "Bad request", baseTypes.BadRequestResponse{}).
+		Returns(404, "Not found", baseTypes.NotFoundResponse{}))
+
+	ws.Route(ws.GET("/applications/{appName}/revisions/{revision}").To(c.getApplicationRevision).
+		Doc("read the specified revision of bdc application").
+		Metadata(restfulspec.KeyOpenAPITags, applicationTags).
+		Metadata(auth.KeyResource, accesses("get", "applicationrevisions", "")).
+		Param(ws.PathParameter("appName", "name of the bdc application").DataType("string").Required(true)).
+		Param(ws.PathParameter("revision", "name or number of the application revision").DataType("string").Required(true)).
+		Writes(v1dto.GetApplicationRevisionResponse{}).
+		Returns(200, "OK", v1dto.GetApplicationRevisionResponse{}).
+		Returns(400, "Bad request", baseTypes.BadRequestResponse{}).
+		Returns(404, "Not found", baseTypes.NotFoundResponse{}))
+
+	ws.Route(ws.POST("/applications/{appName}/revisions/{revision}/rollback").To(c.rollbackApplication).
+		Doc("roll back bdc application to the properties of the specified revision").
+		Metadata(restfulspec.KeyOpenAPITags, applicationTags).
+		Metadata(auth.KeyResource, accesses("update", "applications", "appName")).
+		Param(ws.PathParameter("appName", "name of the bdc application").DataType("string").Required(true)).
+		Param(ws.PathParameter("revision", "name or number of the application revision").DataType("string").Required(true)).
+		Returns(200, "OK", baseTypes.HTTPResponse{}).
+		Returns(400, "Bad request", baseTypes.BadRequestResponse{}).
+		Returns(404, "Not found", baseTypes.NotFoundResponse{}))
+
 	applicationResourcesTags := []string{"application_resources"}
 
 	ws.Route(ws.GET("/applications/{appName}/pods").To(c.getApplicationPods).
 		Doc("query application applied pods").
 		Metadata(restfulspec.KeyOpenAPITags, applicationResourcesTags).
+		Metadata(auth.KeyResource, accesses("get", "applications/pods", "appName")).
 		Param(ws.PathParameter("appName", "name of the bdc application").DataType("string").Required(true)).
 		Writes(v1dto.ApplicationResourcesListResponse{}).
 		Returns(200, "OK", v1dto.ApplicationResourcesListResponse{}).
@@ -169,6 +212,7 @@ func (c *BigDataClusterWebService) GetWebService() *restful.WebService {
 	ws.Route(ws.GET("/applications/{appName}/pods/{podName}").To(c.getApplicationPodsDetail).
 		Doc("query application pods detail info").
 		Metadata(restfulspec.KeyOpenAPITags, applicationResourcesTags).
+		Metadata(auth.KeyResource, accesses("get", "applications/pods", "appName")).
 		Param(ws.PathParameter("appName", "name of the bdc application").DataType("string").Required(true)).
 		Param(ws.PathParameter("podName", "name of the bdc application pod").DataType("string").Required(true)).
 		//Param(ws.QueryParameter("ql", "query statement").DataType("string")).
@@ -180,6 +224,7 @@ func (c *BigDataClusterWebService) GetWebService() *restful.WebService {
 	ws.Route(ws.DELETE("/applications/{appName}/pods/{podName}").To(c.deleteApplicationPod).
 		Doc("delete the specified bdc application pod").
 		Metadata(restfulspec.KeyOpenAPITags, applicationResourcesTags).
+		Metadata(auth.KeyResource, accesses("delete", "applications/pods", "appName")).
 		Param(ws.PathParameter("appName", "name of the bdc application").DataType("string").Required(true)).
 		Param(ws.PathParameter("podName", "name of the bdc application pod").DataType("string").Required(true)).
 		//Param(ws.QueryParameter("ql", "query statement").DataType("string")).
@@ -191,6 +236,7 @@ func (c *BigDataClusterWebService) GetWebService() *restful.WebService {
 	ws.Route(ws.GET("/applications/{appName}/pods/{podName}/containers/{containerName}/logs").To(c.getApplicationPodLogs).
 		Doc("query application applied pods container logs").
 		Metadata(restfulspec.KeyOpenAPITags, applicationResourcesTags).
+		Metadata(auth.KeyResource, accesses("get", "applications/logs", "appName")).
 		Param(ws.PathParameter("appName", "name of the bdc application").DataType("string").Required(true)).
 		Param(ws.PathParameter("podName", "name of the bdc application pod").DataType("string").Required(true)).
 		Param(ws.PathParameter("containerName", "name of the bdc application pod container").DataType("string").Required(true)).
@@ -203,6 +249,7 @@ func (c *BigDataClusterWebService) GetWebService() *restful.WebService {
 	ws.Route(ws.POST("/applications/{appName}/pods/{podName}/containers/{containerName}/terminal").To(c.createPodTerminal).
 		Doc("open application applied pods container exec").
 		Metadata(restfulspec.KeyOpenAPITags, applicationResourcesTags).
+		Metadata(auth.KeyResource, accesses("create", "applications/terminal", "appName")).
 		Param(ws.PathParameter("appName", "name of the bdc application").DataType("string").Required(true)).
 		Param(ws.PathParameter("podName", "name of the bdc application pod").DataType("string").Required(true)).
 		Param(ws.PathParameter("containerName", "name of the bdc application pod container").DataType("string").Required(true)).
@@ -213,6 +260,7 @@ func (c *BigDataClusterWebService) GetWebService() *restful.WebService {
 	ws.Route(ws.GET("/applications/{appName}/serviceEndpoints").To(c.getApplicationServiceEndpoints).
 		Doc("query application service endpoints").
 		Metadata(restfulspec.KeyOpenAPITags, applicationResourcesTags).
+		Metadata(auth.KeyResource, accesses("get", "applications/endpoints", "appName")).
 		Param(ws.PathParameter("appName", "name of the bdc application").DataType("string").Required(true)).
 		Writes(v1dto.ApplicationResourcesListResponse{}).
 		Returns(200, "OK", v1dto.ApplicationResourcesListResponse{}).
@@ -222,6 +270,7 @@ func (c *BigDataClusterWebService) GetWebService() *restful.WebService {
 	ws.Route(ws.GET("/applications/{appName}/resources/topology").To(c.getApplicationResourceTopology).
 		Doc("query applications resource overview").
 		Metadata(restfulspec.KeyOpenAPITags, applicationResourcesTags).
+		Metadata(auth.KeyResource, accesses("get", "applications/resources", "appName")).
 		Param(ws.PathParameter("appName", "name of the bdc application").DataType("string").Required(true)).
 		Writes(v1dto.ApplicationResourceResponse{}).
 		Returns(200, "OK", v1dto.ApplicationResourceResponse{}).
@@ -231,6 +280,7 @@ func (c *BigDataClusterWebService) GetWebService() *restful.WebService {
 	ws.Route(ws.GET("/applications/{appName}/resources/detail").To(c.getApplicationResourceDetail).
 		Doc("query application resource detail spec").
 		Metadata(restfulspec.KeyOpenAPITags, applicationResourcesTags).
+		Metadata(auth.KeyResource, accesses("get", "applications/resources", "appName")).
 		Param(ws.PathParameter("appName", "name of the bdc application").DataType("string").Required(true)).
 		Param(ws.QueryParameter("resNs", "namespace of the kubernetes resource").DataType("string").Required(false)).
 		Param(ws.QueryParameter("resName", "name of the kubernetes resource").DataType("string").Required(true)).
@@ -244,6 +294,7 @@ func (c *BigDataClusterWebService) GetWebService() *restful.WebService {
 	ws.Route(ws.GET("/applications/{appName}/detail").To(c.detailApplication).
 		Doc("read the specified bdc application detail specification").
 		Metadata(restfulspec.KeyOpenAPITags, applicationResourcesTags).
+		Metadata(auth.KeyResource, accesses("get", "applications", "appName")).
 		Param(ws.PathParameter("appName", "name of the bdc application").DataType("string").Required(true)).
 		Writes(v1dto.ApplicationResourceResponse{}).
 		Returns(200, "OK", v1dto.ApplicationResourceResponse{}).
@@ -255,8 +306,9 @@ func (c *BigDataClusterWebService) GetWebService() *restful.WebService {
 	ws.Route(ws.GET("/contextsecrets/").To(c.listContextSecrets).
 		Doc("list objects of kind bdc context secret").
 		Metadata(restfulspec.KeyOpenAPITags, ctxSecretTags).
+		Metadata(auth.KeyResource, accesses("list", "contextsecrets", "")).
 		Param(ws.QueryParameter("bdcName", "name of the bigdata cluster").DataType("string").Required(false)).
-		Param(ws.QueryParameter("labelSelector", "A selector to restrict the list of returned objects by their labels. Defaults to everything.").DataType("string").Required(false)).
+		Do(listParams(ws, "type", "status")).
 		Writes(v1dto.ListContextSecretsResponse{}).
 		Returns(200, "OK", v1dto.ListContextSecretsResponse{}).
 		Returns(400, "Bad request", baseTypes.BadRequestResponse{}).
@@ -265,6 +317,7 @@ func (c *BigDataClusterWebService) GetWebService() *restful.WebService {
 	ws.Route(ws.GET("/contextsecrets/{name}").To(c.getContextSecret).
 		Doc("read the specified bdc context secret").
 		Metadata(restfulspec.KeyOpenAPITags, ctxSecretTags).
+		Metadata(auth.KeyResource, accesses("get", "contextsecrets", "name")).
 		Param(ws.PathParameter("name", "name of the bdc context secret").DataType("string").Required(true)).
 		Writes(v1dto.GetContextSecretResponse{}).
 		Returns(200, "OK", v1dto.GetContextSecretResponse{}).
@@ -274,6 +327,7 @@ func (c *BigDataClusterWebService) GetWebService() *restful.WebService {
 	ws.Route(ws.GET("/bigdataclusters/{bdcName}/contextsecrets/definitions/{defType}/schema").To(c.getContextSecretDefinitionSchema).
 		Doc("read bdc context secret definition schema").
 		Metadata(restfulspec.KeyOpenAPITags, ctxSecretTags).
+		Metadata(auth.KeyResource, accesses("get", "xdefinitions", "")).
 		Param(ws.PathParameter("bdcName", "name of the bigdata cluster").DataType("string").Required(true)).
 		Param(ws.PathParameter("defType", "name of the bdc context secret definition type").DataType("string").Required(true)).
 		Writes(v1dto.GetContextSecretDefSchemaResponse{}).
@@ -286,8 +340,9 @@ func (c *BigDataClusterWebService) GetWebService() *restful.WebService {
 	ws.Route(ws.GET("/contextsettings/").To(c.listContextSettings).
 		Doc("list objects of kind bdc context setting").
 		Metadata(restfulspec.KeyOpenAPITags, ctxSettingTags).
+		Metadata(auth.KeyResource, accesses("list", "contextsettings", "")).
 		Param(ws.QueryParameter("bdcName", "name of the bigdata cluster").DataType("string").Required(false)).
-		Param(ws.QueryParameter("labelSelector", "A selector to restrict the list of returned objects by their labels. Defaults to everything.").DataType("string").Required(false)).
+		Do(listParams(ws, "type", "status")).
 		Writes(v1dto.ListContextSettingsResponse{}).
 		Returns(200, "OK", v1dto.ListContextSettingsResponse{}).
 		Returns(400, "Bad request", baseTypes.BadRequestResponse{}).
@@ -296,6 +351,7 @@ func (c *BigDataClusterWebService) GetWebService() *restful.WebService {
 	ws.Route(ws.GET("/contextsettings/{name}").To(c.getContextSetting).
 		Doc("read the specified bdc context setting").
 		Metadata(restfulspec.KeyOpenAPITags, ctxSettingTags).
+		Metadata(auth.KeyResource, accesses("get", "contextsettings", "name")).
 		Param(ws.PathParameter("name", "name of the bdc context setting").DataType("string").Required(true)).
 		Writes(v1dto.GetContextSettingResponse{}).
 		Returns(200, "OK", v1dto.GetContextSettingResponse{}).
@@ -305,6 +361,7 @@ func (c *BigDataClusterWebService) GetWebService() *restful.WebService {
 	ws.Route(ws.GET("/bigdataclusters/{bdcName}/contextsettings/definitions/{defType}/schema").To(c.getContextSettingDefinitionSchema).
 		Doc("read bdc context setting definition schema").
 		Metadata(restfulspec.KeyOpenAPITags, ctxSettingTags).
+		Metadata(auth.KeyResource, accesses("get", "xdefinitions", "")).
 		Param(ws.PathParameter("bdcName", "name of the bigdata cluster").DataType("string").Required(true)).
 		Param(ws.PathParameter("defType", "name of the bdc context setting definition type").DataType("string").Required(true)).
 		Writes(v1dto.GetContextSettingDefSchemaResponse{}).
@@ -314,18 +371,26 @@ func (c *BigDataClusterWebService) GetWebService() *restful.WebService {
 	return ws
 }
 
+// accesses returns the Resource of the bdc.kdp.io group accessed by the route, the applications have the virtual
+// subresources such as applications/pods for the resources of the application
+func accesses(verb, resource, nameParameter string) auth.Resource {
+	res, subresource, _ := strings.Cut(resource, "/")
+	return auth.Resource{
+		Group:         bdcv1alpha1.GroupVersion.Group,
+		Resource:      res,
+		Subresource:   subresource,
+		Verb:          verb,
+		NameParameter: nameParameter,
+	}
+}
+
 func (c *BigDataClusterWebService) listBigDataClusters(request *restful.Request, response *restful.Response) {
-	labels := map[string]string{}
-	if request.QueryParameter("labelSelector") != "" {
-		allLabels := strings.Split(request.QueryParameter("labelSelector"), ",")
-		for _, label := range allLabels {
-			kv := strings.Split(label, "=")
-			if len(kv) == 2 {
-				labels[kv[0]] = kv[1]
-			}
-		}
+	options, err := parseListOptions(request)
+	if err != nil {
+		exception.ReturnError(request, response, err)
+		return
 	}
-	bdcs, err := c.BigDataClusterService.ListBigDataClusters(request.Request.Context(), v1dto.ListOptions{Labels: labels})
+	bdcs, listMeta, err := c.BigDataClusterService.ListBigDataClusters(request.Request.Context(), options)
 	if err != nil {
 		exception.ReturnError(request, response, err)
 		return
@@ -340,9 +405,11 @@ func (c *BigDataClusterWebService) listBigDataClusters(request *restful.Request,
 		listRtn = append(listRtn, bdcBase)
 	}
 	if err = response.WriteEntity(v1dto.ListBigDataClustersResponse{
-		Data:    listRtn,
-		Message: "success",
-		Status:  0,
+		Data:     listRtn,
+		Message:  "success",
+		Status:   0,
+		Total:    listMeta.Total,
+		Continue: listMeta.Continue,
 	}); err != nil {
 		// bcode.ReturnError(req, res, err)
 		return
@@ -371,8 +438,8 @@ func (c *BigDataClusterWebService) getBigDataCluster(request *restful.Request, r
 	}
 }
 
-func (c *BigDataClusterWebService) bigDataClusterMetaCacheParse(bdcName string) (*v1dto.BigDataClusterBase, error) {
-	bdc, err := c.BigDataClusterService.GetBigDataCluster(context.Background(), bdcName)
+func (c *BigDataClusterWebService) bigDataClusterMetaCacheParse(ctx context.Context, bdcName string) (*v1dto.BigDataClusterBase, error) {
+	bdc, err := c.BigDataClusterService.GetBigDataCluster(ctx, bdcName)
 	if err != nil {
 		log.Logger.Errorw("get bigdata cluster failure", "error", err)
 		return nil, err
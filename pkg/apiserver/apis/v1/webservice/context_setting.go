@@ -22,32 +22,26 @@ import (
 	"kdp-oam-operator/pkg/apiserver/exception"
 	"kdp-oam-operator/pkg/controllers/bdc/constants"
 	"kdp-oam-operator/pkg/utils/log"
-	"strings"
 
 	"github.com/emicklei/go-restful/v3"
 )
 
 func (c *BigDataClusterWebService) listContextSettings(request *restful.Request, response *restful.Response) {
 	bdcName := request.QueryParameter("bdcName")
-	labels := map[string]string{}
-	if request.QueryParameter("labelSelector") != "" {
-		allLabels := strings.Split(request.QueryParameter("labelSelector"), ",")
-		for _, label := range allLabels {
-			kv := strings.Split(label, "=")
-			if len(kv) == 2 {
-				labels[kv[0]] = kv[1]
-			}
-		}
+	options, err := parseListOptions(request)
+	if err != nil {
+		exception.ReturnError(request, response, err)
+		return
 	}
-	bdc, err := c.bigDataClusterMetaCacheParse(bdcName)
+	bdc, err := c.bigDataClusterMetaCacheParse(request.Request.Context(), bdcName)
 	if err != nil {
 		exception.ReturnError(request, response, exception.ErrBigDataClusterNotFound)
 		return
 	}
 	if bdc.Name != "" {
-		labels[constants.AnnotationBDCName] = bdc.Name
+		options.Labels[constants.AnnotationBDCName] = bdc.Name
 	}
-	ctxSettings, err := c.ContextSettingService.ListContextSettings(request.Request.Context(), v1dto.ListOptions{Labels: labels})
+	ctxSettings, listMeta, err := c.ContextSettingService.ListContextSettings(request.Request.Context(), options)
 	if err != nil {
 		exception.ReturnError(request, response, err)
 		return
@@ -62,9 +56,11 @@ func (c *BigDataClusterWebService) listContextSettings(request *restful.Request,
 		listRtn = append(listRtn, ctxSettingBase)
 	}
 	if err := response.WriteEntity(v1dto.ListContextSettingsResponse{
-		Data:    listRtn,
-		Message: "success",
-		Status:  0,
+		Data:     listRtn,
+		Message:  "success",
+		Status:   0,
+		Total:    listMeta.Total,
+		Continue: listMeta.Continue,
 	}); err != nil {
 		// bcode.ReturnError(req, res, err)
 		return
@@ -0,0 +1,103 @@
+/*
+Copyright 2024 KDP(Kubernetes Data Platform).
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webservice
+
+import (
+	v1dto "kdp-oam-operator/pkg/apiserver/apis/v1/dto"
+	"kdp-oam-operator/pkg/apiserver/domain/service"
+	"kdp-oam-operator/pkg/apiserver/exception"
+	"strconv"
+	"strings"
+
+	"github.com/emicklei/go-restful/v3"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+var (
+	sortFields = sets.NewString(service.SortByName, service.SortByCreateTime, service.SortByUpdateTime, service.SortByStatus)
+	sortOrders = sets.NewString(service.OrderAsc, service.OrderDesc)
+)
+
+// filterParams declares the query parameters of the label selector and the field filters supported by the route
+func filterParams(ws *restful.WebService, filters ...string) func(*restful.RouteBuilder) {
+	return func(b *restful.RouteBuilder) {
+		b.Param(ws.QueryParameter("labelSelector", "A selector to restrict the list of returned objects by their labels, supports the set-based requirements such as 'env in (dev,test),!deprecated'. Defaults to everything.").DataType("string").Required(false))
+		for _, filter := range filters {
+			switch filter {
+			case "type":
+				b.Param(ws.QueryParameter("type", "comma separated types to restrict the list of returned objects").DataType("string").Required(false))
+			case "status":
+				b.Param(ws.QueryParameter("status", "comma separated status phases to restrict the list of returned objects").DataType("string").Required(false))
+			}
+		}
+	}
+}
+
+// listParams declares the query parameters of the list options, filters are the field filters supported by the route
+func listParams(ws *restful.WebService, filters ...string) func(*restful.RouteBuilder) {
+	return func(b *restful.RouteBuilder) {
+		filterParams(ws, filters...)(b)
+		b.Param(ws.QueryParameter("sortBy", "sort the returned objects by name, createTime, updateTime or status").DataType("string").DefaultValue(service.SortByName).Required(false))
+		b.Param(ws.QueryParameter("order", "asc or desc").DataType("string").DefaultValue(service.OrderAsc).Required(false))
+		b.Param(ws.QueryParameter("limit", "max number of the returned objects, returns all of them if not set").DataType("integer").Required(false))
+		b.Param(ws.QueryParameter("continue", "the continue token of the previous list to get the next page, the other parameters must be the same").DataType("string").Required(false))
+	}
+}
+
+// parseListOptions parses the list options from the query parameters
+func parseListOptions(request *restful.Request) (v1dto.ListOptions, error) {
+	options := v1dto.ListOptions{
+		Labels:   map[string]string{},
+		Types:    splitQueryParameter(request, "type"),
+		Statuses: splitQueryParameter(request, "status"),
+		SortBy:   request.QueryParameter("sortBy"),
+		Order:    request.QueryParameter("order"),
+		Continue: request.QueryParameter("continue"),
+	}
+	if labelSelector := request.QueryParameter("labelSelector"); labelSelector != "" {
+		selector, err := labels.Parse(labelSelector)
+		if err != nil {
+			return options, exception.ErrInvalidListOptions
+		}
+		options.LabelSelector = selector
+	}
+	if options.SortBy != "" && !sortFields.Has(options.SortBy) {
+		return options, exception.ErrInvalidListOptions
+	}
+	if options.Order != "" && !sortOrders.Has(options.Order) {
+		return options, exception.ErrInvalidListOptions
+	}
+	if limit := request.QueryParameter("limit"); limit != "" {
+		value, err := strconv.ParseInt(limit, 10, 64)
+		if err != nil || value < 0 {
+			return options, exception.ErrInvalidListOptions
+		}
+		options.Limit = value
+	}
+	return options, nil
+}
+
+func splitQueryParameter(request *restful.Request, name string) sets.String {
+	values := sets.NewString()
+	for _, value := range strings.Split(request.QueryParameter(name), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values.Insert(value)
+		}
+	}
+	return values
+}
@@ -0,0 +1,261 @@
+/*
+Copyright 2024 KDP(Kubernetes Data Platform).
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webservice
+
+import (
+	"encoding/json"
+	"fmt"
+	baseTypes "kdp-oam-operator/pkg/apiserver/apis/base/types"
+	"kdp-oam-operator/pkg/apiserver/apis/v1/assembler"
+	v1dto "kdp-oam-operator/pkg/apiserver/apis/v1/dto"
+	"kdp-oam-operator/pkg/apiserver/domain/entity"
+	"kdp-oam-operator/pkg/apiserver/domain/service"
+	"kdp-oam-operator/pkg/apiserver/exception"
+	"kdp-oam-operator/pkg/apiserver/infrastructure/auth"
+	"kdp-oam-operator/pkg/apiserver/infrastructure/watch"
+	"kdp-oam-operator/pkg/controllers/bdc/constants"
+	"kdp-oam-operator/pkg/utils/log"
+	"net/http"
+	"time"
+
+	restfulspec "github.com/emicklei/go-restful-openapi/v2"
+	"github.com/emicklei/go-restful/v3"
+	"github.com/pkg/errors"
+)
+
+const (
+	// mimeEventStream is the content type of the server-sent events
+	mimeEventStream = "text/event-stream"
+	// heartbeatInterval is the interval of the comments sent to keep the idle watches alive through the proxies
+	heartbeatInterval = 30 * time.Second
+)
+
+type WatchWebService struct {
+	BigDataClusterService service.BigDataClusterService
+	WatchService          service.WatchService
+}
+
+func NewWatchWebService(bigDataClusterService service.BigDataClusterService, watchService service.WatchService) WebService {
+	return &WatchWebService{BigDataClusterService: bigDataClusterService, WatchService: watchService}
+}
+
+func (w *WatchWebService) GetWebService() *restful.WebService {
+	ws := new(restful.WebService)
+	ws.Path(versionPrefix+"/watch").
+		Consumes(restful.MIME_JSON, restful.MIME_JSON).
+		// the errors returned before the stream starts are written in json
+		Produces(mimeEventStream, restful.MIME_JSON).
+		Doc("api for watching the changes of the objects as server-sent events")
+
+	watchTags := []string{"watch"}
+	resourceVersionParam := ws.QueryParameter("resourceVersion", "resume the watch after the resource version, which is the id of the last event received, the Last-Event-ID header is used if not set. All the objects are added first if neither is set").DataType("string").Required(false)
+
+	ws.Route(ws.GET("/bigdataclusters").To(w.watchBigDataClusters).
+		Doc("watch objects of kind bdc").
+		Metadata(restfulspec.KeyOpenAPITags, watchTags).
+		Metadata(auth.KeyResource, accesses("watch", "bigdataclusters", "")).
+		Do(filterParams(ws, "status")).
+		Param(resourceVersionParam).
+		Writes(v1dto.WatchEvent{}).
+		Returns(200, "OK", v1dto.WatchEvent{}).
+		Returns(400, "Bad request", baseTypes.BadRequestResponse{}).
+		Returns(410, "Gone", baseTypes.BadRequestResponse{}))
+
+	ws.Route(ws.GET("/applications").To(w.watchApplications).
+		Doc("watch objects of kind bdc application").
+		Metadata(restfulspec.KeyOpenAPITags, watchTags).
+		Metadata(auth.KeyResource, accesses("watch", "applications", "")).
+		Param(ws.QueryParameter("bdcName", "name of the bigdata cluster").DataType("string").Required(false)).
+		Do(filterParams(ws, "type", "status")).
+		Param(resourceVersionParam).
+		Writes(v1dto.WatchEvent{}).
+		Returns(200, "OK", v1dto.WatchEvent{}).
+		Returns(400, "Bad request", baseTypes.BadRequestResponse{}).
+		Returns(404, "Not found", baseTypes.NotFoundResponse{}).
+		Returns(410, "Gone", baseTypes.BadRequestResponse{}))
+
+	ws.Route(ws.GET("/contextsecrets").To(w.watchContextSecrets).
+		Doc("watch objects of kind bdc context secret").
+		Metadata(restfulspec.KeyOpenAPITags, watchTags).
+		Metadata(auth.KeyResource, accesses("watch", "contextsecrets", "")).
+		Param(ws.QueryParameter("bdcName", "name of the bigdata cluster").DataType("string").Required(false)).
+		Do(filterParams(ws, "type", "status")).
+		Param(resourceVersionParam).
+		Writes(v1dto.WatchEvent{}).
+		Returns(200, "OK", v1dto.WatchEvent{}).
+		Returns(400, "Bad request", baseTypes.BadRequestResponse{}).
+		Returns(404, "Not found", baseTypes.NotFoundResponse{}).
+		Returns(410, "Gone", baseTypes.BadRequestResponse{}))
+
+	ws.Route(ws.GET("/contextsettings").To(w.watchContextSettings).
+		Doc("watch objects of kind bdc context setting").
+		Metadata(restfulspec.KeyOpenAPITags, watchTags).
+		Metadata(auth.KeyResource, accesses("watch", "contextsettings", "")).
+		Param(ws.QueryParameter("bdcName", "name of the bigdata cluster").DataType("string").Required(false)).
+		Do(filterParams(ws, "type", "status")).
+		Param(resourceVersionParam).
+		Writes(v1dto.WatchEvent{}).
+		Returns(200, "OK", v1dto.WatchEvent{}).
+		Returns(400, "Bad request", baseTypes.BadRequestResponse{}).
+		Returns(404, "Not found", baseTypes.NotFoundResponse{}).
+		Returns(410, "Gone", baseTypes.BadRequestResponse{}))
+
+	return ws
+}
+
+func (w *WatchWebService) watchBigDataClusters(request *restful.Request, response *restful.Response) {
+	options, err := parseListOptions(request)
+	if err != nil {
+		exception.ReturnError(request, response, err)
+		return
+	}
+	watcher, err := w.WatchService.WatchBigDataClusters(request.Request.Context(), options, watchResourceVersion(request))
+	if err != nil {
+		exception.ReturnError(request, response, err)
+		return
+	}
+	serveWatch(request, response, watcher, func(obj interface{}) (interface{}, error) {
+		return assembler.ConvertBigDataClusterEntityToDTO(obj.(*entity.BigDataClusterEntity))
+	})
+}
+
+func (w *WatchWebService) watchApplications(request *restful.Request, response *restful.Response) {
+	options, ok := w.bigDataClusterListOptions(request, response)
+	if !ok {
+		return
+	}
+	watcher, err := w.WatchService.WatchApplications(request.Request.Context(), options, watchResourceVersion(request))
+	if err != nil {
+		exception.ReturnError(request, response, err)
+		return
+	}
+	serveWatch(request, response, watcher, func(obj interface{}) (interface{}, error) {
+		return assembler.ConvertApplicationEntityToDTO(obj.(*entity.ApplicationEntity))
+	})
+}
+
+func (w *WatchWebService) watchContextSecrets(request *restful.Request, response *restful.Response) {
+	options, ok := w.bigDataClusterListOptions(request, response)
+	if !ok {
+		return
+	}
+	watcher, err := w.WatchService.WatchContextSecrets(request.Request.Context(), options, watchResourceVersion(request))
+	if err != nil {
+		exception.ReturnError(request, response, err)
+		return
+	}
+	serveWatch(request, response, watcher, func(obj interface{}) (interface{}, error) {
+		return assembler.ConvertContextSecretEntityToDTO(obj.(*entity.ContextSecretEntity))
+	})
+}
+
+func (w *WatchWebService) watchContextSettings(request *restful.Request, response *restful.Response) {
+	options, ok := w.bigDataClusterListOptions(request, response)
+	if !ok {
+		return
+	}
+	watcher, err := w.WatchService.WatchContextSettings(request.Request.Context(), options, watchResourceVersion(request))
+	if err != nil {
+		exception.ReturnError(request, response, err)
+		return
+	}
+	serveWatch(request, response, watcher, func(obj interface{}) (interface{}, error) {
+		return assembler.ConvertContextSettingEntityToDTO(obj.(*entity.ContextSettingEntity))
+	})
+}
+
+// bigDataClusterListOptions parses the list options restricted to the bdc of the bdcName parameter, the error is
+// returned to the client if false
+func (w *WatchWebService) bigDataClusterListOptions(request *restful.Request, response *restful.Response) (v1dto.ListOptions, bool) {
+	options, err := parseListOptions(request)
+	if err != nil {
+		exception.ReturnError(request, response, err)
+		return options, false
+	}
+	if bdcName := request.QueryParameter("bdcName"); bdcName != "" {
+		// If specify bdcName, then we need to check the bdc first
+		bdc, err := w.BigDataClusterService.GetBigDataCluster(request.Request.Context(), bdcName)
+		if err != nil {
+			exception.ReturnError(request, response, exception.ErrBigDataClusterNotFound)
+			return options, false
+		}
+		options.Labels[constants.AnnotationBDCName] = bdc.Name
+	}
+	return options, true
+}
+
+// watchResourceVersion returns the resource version to resume the watch from, the EventSource of the browsers sends
+// the id of the last event received in the Last-Event-ID header on reconnection
+func watchResourceVersion(request *restful.Request) string {
+	if resourceVersion := request.QueryParameter("resourceVersion"); resourceVersion != "" {
+		return resourceVersion
+	}
+	return request.HeaderParameter("Last-Event-ID")
+}
+
+// serveWatch streams the events of the watcher as the server-sent events until the client disconnects or the
+// watcher is stopped, the objects of the events are converted to the dto by convert
+func serveWatch(request *restful.Request, response *restful.Response, watcher *watch.Watcher, convert func(obj interface{}) (interface{}, error)) {
+	defer watcher.Stop()
+	response.Header().Set("Content-Type", mimeEventStream)
+	response.Header().Set("Cache-Control", "no-cache")
+	response.Header().Set("Connection", "keep-alive")
+	// disable the response buffering of nginx
+	response.Header().Set("X-Accel-Buffering", "no")
+	response.WriteHeader(http.StatusOK)
+	response.Flush()
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-request.Request.Context().Done():
+			return
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(response, ": heartbeat\n\n"); err != nil {
+				return
+			}
+			response.Flush()
+		case <-watcher.Ready():
+			events, ok := watcher.Pop()
+			for _, event := range events {
+				if err := writeWatchEvent(response, event, convert); err != nil {
+					log.Logger.Errorf("write watch event failure %s", err.Error())
+					return
+				}
+			}
+			response.Flush()
+			if !ok {
+				// the client reconnects and resumes from the last event, or watches again if it's expired
+				return
+			}
+		}
+	}
+}
+
+func writeWatchEvent(response *restful.Response, event watch.Event, convert func(obj interface{}) (interface{}, error)) error {
+	object, err := convert(event.Object)
+	if err != nil {
+		return errors.Wrap(err, "convert watch event object failure")
+	}
+	data, err := json.Marshal(v1dto.WatchEvent{Type: string(event.Type), Object: object})
+	if err != nil {
+		return errors.Wrap(err, "marshal watch event failure")
+	}
+	_, err = fmt.Fprintf(response, "id: %s\nevent: %s\ndata: %s\n\n", event.ResourceVersion, event.Type, data)
+	return err
+}
@@ -33,7 +33,7 @@ func (c *BigDataClusterWebService) createPodTerminal(request *restful.Request, r
 	TerminalName := podName + "-" + containerName + "-exec"
 
 	appName := request.PathParameter("appName")
-	app, err := c.applicationMetaCacheParse(appName)
+	app, err := c.applicationMetaCacheParse(request.Request.Context(), appName)
 	if err != nil {
 		exception.ReturnError(request, response, exception.ErrApplicationNotFound)
 		return
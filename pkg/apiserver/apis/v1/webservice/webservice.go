@@ -55,9 +55,13 @@ func Init() {
 	contextSettingService := service.NewContextSettingService()
 	xDefinitionService := service.NewXDefinitionService()
 	webTerminalService := service.NewWebTerminalService()
+	auditService := service.NewAuditService()
+	watchService := service.NewWatchService()
 
 	// register webservice
 	RegisterWebService(NewBigDataClusterWebService(bigDataClusterService, applicationService,
 		applicationResourcesService, contextSecretService, contextSettingService, xDefinitionService, webTerminalService))
+	RegisterWebService(NewAuditWebService(auditService))
+	RegisterWebService(NewWatchWebService(bigDataClusterService, watchService))
 	RegisterWebService(NewProbeService())
 }
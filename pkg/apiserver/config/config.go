@@ -39,6 +39,23 @@ type APIServerConfig struct {
 	LeaderConfig leaderConfig
 	// DefaultSystemNS
 	DefaultSystemNS string
+	// Authentication of the callers by their bearer tokens
+	Authentication authenticationConfig
+	// AuthorizationMode is one of AlwaysAllow, SubjectAccessReview and Impersonation
+	AuthorizationMode string
+	// Tenancy scopes the objects visible to the callers by their orgs and BigDataClusters
+	Tenancy TenancyConfig
+	// Audit records the mutating requests
+	Audit AuditConfig
+	// WatchCacheSize is the number of the recent changes of each type buffered for the watches to resume from
+	WatchCacheSize int
+}
+
+type authenticationConfig struct {
+	// TokenAuthFile is the static token file in the format of the kube-apiserver --token-auth-file
+	TokenAuthFile string
+	// TokenReview enables the authentication of the tokens by TokenReview
+	TokenReview bool
 }
 
 type leaderConfig struct {
@@ -46,3 +63,29 @@ type leaderConfig struct {
 	LockName string
 	Duration time.Duration
 }
+
+// TenancyConfig of the tenancy of the apiserver
+type TenancyConfig struct {
+	// MembershipSource is one of Groups and ConfigMap, the tenancy is disabled if it's empty
+	MembershipSource string
+	// OrgGroupPrefix is the prefix of the groups naming the orgs of the user
+	OrgGroupPrefix string
+	// BDCGroupPrefix is the prefix of the groups naming the BigDataClusters of the user
+	BDCGroupPrefix string
+	// MembershipConfigMap is the [namespace/]name of the membership ConfigMap
+	MembershipConfigMap string
+	// AdminGroups are the groups of the users seeing everything
+	AdminGroups []string
+}
+
+// AuditConfig of the audit log of the apiserver
+type AuditConfig struct {
+	// LogPath is the path of the audit log file, the audit is disabled if it's empty
+	LogPath string
+	// MaxSize is the size in megabytes of the audit log file to be rotated
+	MaxSize int
+	// MaxBackups is the max number of the rotated audit log files retained
+	MaxBackups int
+	// MaxAge is the max days of the rotated audit log files retained
+	MaxAge int
+}
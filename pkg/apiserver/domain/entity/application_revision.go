@@ -0,0 +1,51 @@
+/*
+Copyright 2024 KDP(Kubernetes Data Platform).
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package entity
+
+import (
+	bdcv1alpha1 "kdp-oam-operator/api/bdc/v1alpha1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// ApplicationRevisionEntity application revision model
+type ApplicationRevisionEntity struct {
+	Name                  string                `json:"name"`
+	AppName               string                `json:"appName"`
+	Revision              int64                 `json:"revision"`
+	Properties            *runtime.RawExtension `json:"properties"`
+	XDefinitionName       string                `json:"xDefinitionName"`
+	XDefinitionGeneration int64                 `json:"xDefinitionGeneration"`
+	ManifestHash          string                `json:"manifestHash"`
+	Author                string                `json:"author"`
+	CreateTime            metav1.Time           `json:"createTime"`
+}
+
+func Object2ApplicationRevisionEntity(revision *bdcv1alpha1.ApplicationRevision) *ApplicationRevisionEntity {
+	return &ApplicationRevisionEntity{
+		Name:                  revision.Name,
+		AppName:               revision.Spec.ApplicationName,
+		Revision:              revision.Spec.Revision,
+		Properties:            revision.Spec.Properties,
+		XDefinitionName:       revision.Spec.XDefinitionName,
+		XDefinitionGeneration: revision.Spec.XDefinitionGeneration,
+		ManifestHash:          revision.Spec.ManifestHash,
+		Author:                revision.Spec.Author,
+		CreateTime:            revision.CreationTimestamp,
+	}
+}
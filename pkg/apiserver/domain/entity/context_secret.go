@@ -39,6 +39,7 @@ type ContextSecretEntity struct {
 	UpdateTime  metav1.Time           `json:"updateTime"`
 	Labels      map[string]string     `json:"labels,omitempty"`
 	Annotations map[string]string     `json:"annotations,omitempty"`
+	Status      string                `json:"status"`
 }
 
 func Object2ContextSecretEntity(ctxSecret *bdcv1alpha1.ContextSecret) *ContextSecretEntity {
@@ -65,6 +66,7 @@ func Object2ContextSecretEntity(ctxSecret *bdcv1alpha1.ContextSecret) *ContextSe
 		CreateTime: ctxSecret.CreationTimestamp,
 		UpdateTime: metav1.NewTime(updateTime),
 		Labels:     ctxSecret.Labels,
+		Status:     ctxSecret.Status.Status,
 	}
 	return appEntity
 }
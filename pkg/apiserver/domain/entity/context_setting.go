@@ -39,6 +39,7 @@ type ContextSettingEntity struct {
 	UpdateTime  metav1.Time           `json:"updateTime"`
 	Labels      map[string]string     `json:"labels,omitempty"`
 	Annotations map[string]string     `json:"annotations,omitempty"`
+	Status      string                `json:"status"`
 }
 
 func Object2ContextSettingEntity(ctxSetting *bdcv1alpha1.ContextSetting) *ContextSettingEntity {
@@ -65,6 +66,7 @@ func Object2ContextSettingEntity(ctxSetting *bdcv1alpha1.ContextSetting) *Contex
 		CreateTime: ctxSetting.CreationTimestamp,
 		UpdateTime: metav1.NewTime(updateTime),
 		Labels:     ctxSetting.Labels,
+		Status:     ctxSetting.Status.Status,
 	}
 	return appEntity
 }
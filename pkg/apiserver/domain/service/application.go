@@ -22,27 +22,35 @@ import (
 	bdcv1alpha1 "kdp-oam-operator/api/bdc/v1alpha1"
 	v1types "kdp-oam-operator/pkg/apiserver/apis/v1/dto"
 	"kdp-oam-operator/pkg/apiserver/domain/entity"
+	"kdp-oam-operator/pkg/apiserver/infrastructure/audit"
 	"kdp-oam-operator/pkg/apiserver/infrastructure/clients"
 	"kdp-oam-operator/pkg/controllers/bdc/constants"
+	"kdp-oam-operator/pkg/controllers/utils/apprevision"
 	"kdp-oam-operator/pkg/utils/log"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
 	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 // ApplicationService application service
 type ApplicationService interface {
-	ListApplications(ctx context.Context, listOptions v1types.ListOptions) ([]*entity.ApplicationEntity, error)
+	ListApplications(ctx context.Context, listOptions v1types.ListOptions) ([]*entity.ApplicationEntity, *v1types.ListMeta, error)
 	GetApplication(ctx context.Context, appName string) (*entity.ApplicationEntity, error)
 	DetailApplication(ctx context.Context, appName string) (*bdcv1alpha1.Application, error)
 	CreateApplication(context.Context, v1types.CreateApplicationRequest) (*v1types.ApplicationBase, error)
 	UpdateApplication(context.Context, v1types.UpdateApplicationRequest) (*v1types.ApplicationBase, error)
 	DeleteApplication(ctx context.Context, appName string) error
 	DeleteApplicationPod(ctx context.Context, podNamespace, podName string) error
+	ListApplicationRevisions(ctx context.Context, appName string) ([]*entity.ApplicationRevisionEntity, error)
+	GetApplicationRevision(ctx context.Context, appName, revision string) (*entity.ApplicationRevisionEntity, error)
+	RollbackApplication(ctx context.Context, appName, revision string) error
 }
 
 // NewApplicationService new application service
@@ -66,38 +74,54 @@ type applicationServiceImpl struct {
 	KubeConfig *rest.Config
 }
 
-func (a applicationServiceImpl) ListApplications(ctx context.Context, options v1types.ListOptions) ([]*entity.ApplicationEntity, error) {
-	list := new(bdcv1alpha1.ApplicationList)
-	matchLabels := metav1.LabelSelector{MatchLabels: options.Labels}
-	selector, err := metav1.LabelSelectorAsSelector(&matchLabels)
-	if err != nil {
-		return nil, err
+func (a applicationServiceImpl) ListApplications(ctx context.Context, options v1types.ListOptions) ([]*entity.ApplicationEntity, *v1types.ListMeta, error) {
+	return listObjects(ctx, a.KubeClient, new(bdcv1alpha1.ApplicationList), options, func(obj runtime.Object) (*entity.ApplicationEntity, listItem, bool) {
+		return applicationListItem(ctx, obj)
+	})
+}
+
+// applicationListItem converts the listed application, false if it's invisible to the caller
+func applicationListItem(ctx context.Context, obj runtime.Object) (*entity.ApplicationEntity, listItem, bool) {
+	app := entity.Object2ApplicationEntity(obj.(*bdcv1alpha1.Application))
+	if app == nil || !visible(ctx, app.BDC) {
+		return nil, listItem{}, false
 	}
-	if err := a.KubeClient.List(ctx, list, &client.ListOptions{
-		LabelSelector: selector,
-	}); err != nil {
+	return app, listItem{
+		name:       app.Name,
+		typ:        app.AppTemplateType,
+		status:     app.Status.Status,
+		createTime: app.CreateTime.Time,
+		updateTime: app.UpdateTime.Time,
+	}, true
+}
+
+// getApplication gets the application visible to the caller of ctx
+func (a applicationServiceImpl) getApplication(ctx context.Context, appName string) (*bdcv1alpha1.Application, error) {
+	app := new(bdcv1alpha1.Application)
+	if err := a.KubeClient.Get(ctx, client.ObjectKey{Name: appName}, app); err != nil {
 		return nil, err
 	}
-
-	var apps []*entity.ApplicationEntity
-	for _, item := range list.Items {
-		apps = append(apps, entity.Object2ApplicationEntity(&item))
+	bdc := entity.Object2ApplicationEntity(app).BDC
+	if !visible(ctx, bdc) {
+		return nil, invisible("applications", appName)
 	}
-	return apps, nil
+	if bdc != nil {
+		audit.SetTarget(ctx, bdc.Name, appName)
+	}
+	return app, nil
 }
 
 func (a applicationServiceImpl) GetApplication(ctx context.Context, appName string) (*entity.ApplicationEntity, error) {
-	app := new(bdcv1alpha1.Application)
-
-	if err := a.KubeClient.Get(ctx, client.ObjectKey{Name: appName}, app); err != nil {
+	app, err := a.getApplication(ctx, appName)
+	if err != nil {
 		return nil, err
 	}
 	return entity.Object2ApplicationEntity(app), nil
 }
 
 func (a applicationServiceImpl) DetailApplication(ctx context.Context, appName string) (*bdcv1alpha1.Application, error) {
-	app := new(bdcv1alpha1.Application)
-	if err := a.KubeClient.Get(ctx, client.ObjectKey{Name: appName}, app); err != nil {
+	app, err := a.getApplication(ctx, appName)
+	if err != nil {
 		return nil, err
 	}
 	app.APIVersion = bigDataClusterAPIVersion
@@ -134,6 +158,9 @@ func (a applicationServiceImpl) CreateApplication(ctx context.Context, request v
 			Properties: request.Properties,
 		},
 	}
+	setUpdatedBy(ctx, &app)
+	audit.SetTarget(ctx, request.BDC.Name, app.Name)
+	audit.SetPropertiesDiff(ctx, nil, request.Properties)
 	if err := a.KubeClient.Create(ctx, &app); err != nil {
 		return nil, err
 	}
@@ -141,12 +168,13 @@ func (a applicationServiceImpl) CreateApplication(ctx context.Context, request v
 }
 
 func (a applicationServiceImpl) UpdateApplication(ctx context.Context, request v1types.UpdateApplicationRequest) (*v1types.ApplicationBase, error) {
-	app := new(bdcv1alpha1.Application)
-
-	if err := a.KubeClient.Get(ctx, client.ObjectKey{Name: request.AppName}, app); err != nil {
+	app, err := a.getApplication(ctx, request.AppName)
+	if err != nil {
 		return nil, err
 	}
+	audit.SetPropertiesDiff(ctx, app.Spec.Properties, request.Properties)
 	app.Spec.Properties = request.Properties
+	setUpdatedBy(ctx, app)
 	app.Annotations[constants.AnnotationBDCUpdatedTime] = metav1.Now().Format(time.RFC3339)
 	if err := a.KubeClient.Update(ctx, app); err != nil {
 		return nil, err
@@ -155,11 +183,11 @@ func (a applicationServiceImpl) UpdateApplication(ctx context.Context, request v
 }
 
 func (a applicationServiceImpl) DeleteApplication(ctx context.Context, appName string) error {
-	app := new(bdcv1alpha1.Application)
-
-	if err := a.KubeClient.Get(ctx, client.ObjectKey{Name: appName}, app); err != nil {
+	app, err := a.getApplication(ctx, appName)
+	if err != nil {
 		return err
 	}
+	audit.SetPropertiesDiff(ctx, app.Spec.Properties, nil)
 	if err := a.KubeClient.Delete(ctx, app); err != nil {
 		return err
 	}
@@ -177,3 +205,65 @@ func (a applicationServiceImpl) DeleteApplicationPod(ctx context.Context, podNam
 	}
 	return nil
 }
+
+// ListApplicationRevisions lists the revisions of the application from the latest to the oldest
+func (a applicationServiceImpl) ListApplicationRevisions(ctx context.Context, appName string) ([]*entity.ApplicationRevisionEntity, error) {
+	if _, err := a.getApplication(ctx, appName); err != nil {
+		return nil, err
+	}
+	revisions, err := apprevision.List(ctx, a.KubeClient, appName)
+	if err != nil {
+		return nil, err
+	}
+	var revs []*entity.ApplicationRevisionEntity
+	for i := len(revisions) - 1; i >= 0; i-- {
+		revs = append(revs, entity.Object2ApplicationRevisionEntity(&revisions[i]))
+	}
+	return revs, nil
+}
+
+// GetApplicationRevision gets the revision of the application by the name or the number of the revision
+func (a applicationServiceImpl) GetApplicationRevision(ctx context.Context, appName, revision string) (*entity.ApplicationRevisionEntity, error) {
+	if _, err := a.getApplication(ctx, appName); err != nil {
+		return nil, err
+	}
+	revisions, err := apprevision.List(ctx, a.KubeClient, appName)
+	if err != nil {
+		return nil, err
+	}
+	rev := apprevision.Find(revisions, revision)
+	if rev == nil {
+		return nil, apierrors.NewNotFound(bdcv1alpha1.GroupVersion.WithResource("applicationrevisions").GroupResource(), revision)
+	}
+	return entity.Object2ApplicationRevisionEntity(rev), nil
+}
+
+// RollbackApplication rolls back the application to the revision, which is done by the controller asynchronously
+func (a applicationServiceImpl) RollbackApplication(ctx context.Context, appName, revision string) error {
+	rev, err := a.GetApplicationRevision(ctx, appName, revision)
+	if err != nil {
+		return err
+	}
+	app, err := a.getApplication(ctx, appName)
+	if err != nil {
+		return err
+	}
+	// the requester is kept by the controller when rolling back, so the new revision is credited to the requester
+	setUpdatedBy(ctx, app)
+	app.Annotations[constants.AnnotationRollbackRevision] = rev.Name
+	audit.SetPropertiesDiff(ctx, app.Spec.Properties, rev.Properties)
+	return a.KubeClient.Update(ctx, app)
+}
+
+// setUpdatedBy records the caller of ctx as the author of the change of app, which the controller records in the
+// next ApplicationRevision. The annotation is removed for an anonymous caller, so the former author isn't credited.
+func setUpdatedBy(ctx context.Context, app *bdcv1alpha1.Application) {
+	if app.Annotations == nil {
+		app.Annotations = map[string]string{}
+	}
+	if u, ok := genericapirequest.UserFrom(ctx); ok && u.GetName() != "" {
+		app.Annotations[constants.AnnotationUpdatedBy] = u.GetName()
+		return
+	}
+	delete(app.Annotations, constants.AnnotationUpdatedBy)
+}
@@ -76,7 +76,12 @@ func (a applicationResourcesServiceImpl) queryView(ctx context.Context, ql strin
 		}
 	}
 
-	queryValue, err := velaql.NewViewHandler(a.KubeClient, a.KubeConfig, velaPD).QueryView(utils.ContextWithUserInfo(ctx), query)
+	// the logs of pods are read by the clientset created from the config
+	kubeConfig, err := clients.ImpersonatingConfig(ctx, a.KubeConfig)
+	if err != nil {
+		return nil, err
+	}
+	queryValue, err := velaql.NewViewHandler(a.KubeClient, kubeConfig, velaPD).QueryView(utils.ContextWithUserInfo(ctx), query)
 	if err != nil {
 		log.Logger.Errorf("fail to query the view %s", err.Error())
 		return nil, bcode.ErrViewQuery
@@ -18,12 +18,21 @@ package service
 
 import (
 	"context"
+	bdcv1alpha1 "kdp-oam-operator/api/bdc/v1alpha1"
 	v1dto "kdp-oam-operator/pkg/apiserver/apis/v1/dto"
 	"kdp-oam-operator/pkg/controllers/bdc/constants"
 	"kdp-oam-operator/pkg/utils"
+	"testing"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/authentication/user"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 var _ = Describe("Test application service function", func() {
@@ -65,13 +74,13 @@ var _ = Describe("Test application service function", func() {
 
 	It("Test ListApplications function", func() {
 		options := v1dto.ListOptions{}
-		_, err := appService.ListApplications(context.TODO(), options)
+		_, _, err := appService.ListApplications(context.TODO(), options)
 		Expect(err).Should(BeNil())
 	})
 
 	It("Test ListApplications function  by their labels as a selector to restrict the list of returned objects", func() {
 		options := v1dto.ListOptions{Labels: map[string]string{constants.LabelBDCName: testBDCName}}
-		_, err := appService.ListApplications(context.TODO(), options)
+		_, _, err := appService.ListApplications(context.TODO(), options)
 		Expect(err).Should(BeNil())
 	})
 
@@ -112,3 +121,52 @@ var _ = Describe("Test application service function", func() {
 
 	})
 })
+
+func TestApplicationUpdatedBy(t *testing.T) {
+	s := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(s)
+	_ = bdcv1alpha1.AddToScheme(s)
+	kubeClient := fake.NewClientBuilder().WithScheme(s).WithObjects(
+		&bdcv1alpha1.Application{ObjectMeta: metav1.ObjectMeta{Name: "bdc-a-app"}},
+		&bdcv1alpha1.ApplicationRevision{
+			ObjectMeta: metav1.ObjectMeta{Name: "bdc-a-app-v1", Labels: map[string]string{constants.LabelAppRevisionOf: "bdc-a-app"}},
+			Spec:       bdcv1alpha1.ApplicationRevisionSpec{Revision: 1},
+		},
+	).Build()
+	applicationService := &applicationServiceImpl{KubeClient: kubeClient}
+	updatedBy := func() string {
+		var app bdcv1alpha1.Application
+		if err := kubeClient.Get(context.Background(), client.ObjectKey{Name: "bdc-a-app"}, &app); err != nil {
+			t.Fatal(err)
+		}
+		return app.Annotations[constants.AnnotationUpdatedBy]
+	}
+	asUser := func(name string) context.Context {
+		return genericapirequest.WithUser(context.Background(), &user.DefaultInfo{Name: name})
+	}
+
+	req := v1dto.UpdateApplicationRequest{AppName: "bdc-a-app"}
+	req.Properties = utils.Object2RawExtension(map[string]interface{}{"replicas": 2})
+	if _, err := applicationService.UpdateApplication(asUser("alice"), req); err != nil {
+		t.Fatalf("UpdateApplication() error = %v", err)
+	}
+	if got := updatedBy(); got != "alice" {
+		t.Errorf("updated-by after update = %q, want alice", got)
+	}
+
+	// the requester of the rollback is carried by the controller into the new revision
+	if err := applicationService.RollbackApplication(asUser("bob"), "bdc-a-app", "1"); err != nil {
+		t.Fatalf("RollbackApplication() error = %v", err)
+	}
+	if got := updatedBy(); got != "bob" {
+		t.Errorf("updated-by after rollback = %q, want bob", got)
+	}
+
+	// the anonymous change isn't credited to the former author
+	if _, err := applicationService.UpdateApplication(context.Background(), req); err != nil {
+		t.Fatalf("UpdateApplication() error = %v", err)
+	}
+	if got := updatedBy(); got != "" {
+		t.Errorf("updated-by after anonymous update = %q, want empty", got)
+	}
+}
@@ -0,0 +1,49 @@
+/*
+Copyright 2024 KDP(Kubernetes Data Platform).
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"kdp-oam-operator/pkg/apiserver/exception"
+	"kdp-oam-operator/pkg/apiserver/infrastructure/audit"
+	"kdp-oam-operator/pkg/apiserver/infrastructure/tenancy"
+)
+
+// AuditService queries the audit log of the mutating requests
+type AuditService interface {
+	ListAuditEvents(ctx context.Context, query audit.Query) ([]*audit.Event, error)
+}
+
+type auditServiceImpl struct {
+	Logger *audit.Logger
+}
+
+// NewAuditService new audit service
+func NewAuditService() AuditService {
+	return &auditServiceImpl{Logger: audit.GetLogger()}
+}
+
+// ListAuditEvents returns the audit events matching the query, which are only visible to the unrestricted callers
+func (a *auditServiceImpl) ListAuditEvents(ctx context.Context, query audit.Query) ([]*audit.Event, error) {
+	if a.Logger == nil {
+		return nil, exception.ErrAuditDisabled
+	}
+	if tenancy.MembershipFrom(ctx) != nil {
+		return nil, exception.ErrForbidden
+	}
+	return a.Logger.Query(query)
+}
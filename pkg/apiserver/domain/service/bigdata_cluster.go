@@ -18,7 +18,7 @@ package service
 
 import (
 	"context"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/rest"
 	bdcv1alpha1 "kdp-oam-operator/api/bdc/v1alpha1"
 	v1types "kdp-oam-operator/pkg/apiserver/apis/v1/dto"
@@ -31,7 +31,7 @@ import (
 
 // BigDataClusterService bigdata cluster service
 type BigDataClusterService interface {
-	ListBigDataClusters(ctx context.Context, listOptions v1types.ListOptions) ([]*entity.BigDataClusterEntity, error)
+	ListBigDataClusters(ctx context.Context, listOptions v1types.ListOptions) ([]*entity.BigDataClusterEntity, *v1types.ListMeta, error)
 	GetBigDataCluster(ctx context.Context, bdcName string) (*entity.BigDataClusterEntity, error)
 }
 
@@ -56,31 +56,33 @@ type bigDataClusterServiceImpl struct {
 	KubeConfig *rest.Config
 }
 
-func (b bigDataClusterServiceImpl) ListBigDataClusters(ctx context.Context, options v1types.ListOptions) ([]*entity.BigDataClusterEntity, error) {
-	bdcList := new(bdcv1alpha1.BigDataClusterList)
-	matchLabels := metav1.LabelSelector{MatchLabels: options.Labels}
-	selector, err := metav1.LabelSelectorAsSelector(&matchLabels)
-	if err != nil {
-		return nil, err
-	}
-	if err := b.KubeClient.List(ctx, bdcList, &client.ListOptions{
-		LabelSelector: selector,
-	}); err != nil {
-		return nil, err
-	}
-	var bdcs []*entity.BigDataClusterEntity
-	for _, item := range bdcList.Items {
-		for _, ns := range item.Spec.Namespaces {
-			if ns.IsDefault {
-				if _, ok := item.GetLabels()[constants.AnnotationBDCDefaultNamespace]; !ok {
-					item.SetLabels(map[string]string{constants.AnnotationBDCDefaultNamespace: ns.Name})
-					break
-				}
+func (b bigDataClusterServiceImpl) ListBigDataClusters(ctx context.Context, options v1types.ListOptions) ([]*entity.BigDataClusterEntity, *v1types.ListMeta, error) {
+	return listObjects(ctx, b.KubeClient, new(bdcv1alpha1.BigDataClusterList), options, func(obj runtime.Object) (*entity.BigDataClusterEntity, listItem, bool) {
+		return bigDataClusterListItem(ctx, obj)
+	})
+}
+
+// bigDataClusterListItem converts the listed bigdata cluster, false if it's invisible to the caller
+func bigDataClusterListItem(ctx context.Context, obj runtime.Object) (*entity.BigDataClusterEntity, listItem, bool) {
+	item := obj.(*bdcv1alpha1.BigDataCluster)
+	for _, ns := range item.Spec.Namespaces {
+		if ns.IsDefault {
+			if _, ok := item.GetLabels()[constants.AnnotationBDCDefaultNamespace]; !ok {
+				item.SetLabels(map[string]string{constants.AnnotationBDCDefaultNamespace: ns.Name})
+				break
 			}
 		}
-		bdcs = append(bdcs, entity.Object2BigDataClusterEntity(&item))
 	}
-	return bdcs, nil
+	bdc := entity.Object2BigDataClusterEntity(item)
+	if !visible(ctx, bdc) {
+		return nil, listItem{}, false
+	}
+	return bdc, listItem{
+		name:       item.Name,
+		status:     bdc.Status,
+		createTime: bdc.CreateTime.Time,
+		updateTime: bdc.UpdateTime.Time,
+	}, true
 }
 
 func (b bigDataClusterServiceImpl) GetBigDataCluster(ctx context.Context, bdcName string) (*entity.BigDataClusterEntity, error) {
@@ -97,5 +99,9 @@ func (b bigDataClusterServiceImpl) GetBigDataCluster(ctx context.Context, bdcNam
 			}
 		}
 	}
-	return entity.Object2BigDataClusterEntity(bdc), nil
+	bdcEntity := entity.Object2BigDataClusterEntity(bdc)
+	if !visible(ctx, bdcEntity) {
+		return nil, invisible("bigdataclusters", bdcName)
+	}
+	return bdcEntity, nil
 }
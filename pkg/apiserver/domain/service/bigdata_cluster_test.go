@@ -70,13 +70,13 @@ var _ = Describe("Test bigdata cluster service function", func() {
 
 	It("Test ListBigDataClusters function", func() {
 		options := v1dto.ListOptions{}
-		_, err := bigDataClusterService.ListBigDataClusters(context.TODO(), options)
+		_, _, err := bigDataClusterService.ListBigDataClusters(context.TODO(), options)
 		Expect(err).Should(BeNil())
 	})
 
 	It("Test ListBigDataClusters function  by their labels as a selector to restrict the list of returned objects", func() {
 		options := v1dto.ListOptions{Labels: map[string]string{constants.AnnotationOrgName: testBigDataClusterOrg}}
-		_, err := bigDataClusterService.ListBigDataClusters(context.TODO(), options)
+		_, _, err := bigDataClusterService.ListBigDataClusters(context.TODO(), options)
 		Expect(err).Should(BeNil())
 	})
 
@@ -24,14 +24,14 @@ import (
 	"kdp-oam-operator/pkg/apiserver/infrastructure/clients"
 	"kdp-oam-operator/pkg/utils/log"
 
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 // ContextSecretService context secret service
 type ContextSecretService interface {
-	ListContextSecrets(ctx context.Context, listOptions v1types.ListOptions) ([]*entity.ContextSecretEntity, error)
+	ListContextSecrets(ctx context.Context, listOptions v1types.ListOptions) ([]*entity.ContextSecretEntity, *v1types.ListMeta, error)
 	GetContextSecret(ctx context.Context, name string) (*entity.ContextSecretEntity, error)
 }
 
@@ -56,24 +56,25 @@ type contextSecretServiceImpl struct {
 	KubeConfig *rest.Config
 }
 
-func (c contextSecretServiceImpl) ListContextSecrets(ctx context.Context, options v1types.ListOptions) ([]*entity.ContextSecretEntity, error) {
-	list := new(bdcv1alpha1.ContextSecretList)
-	matchLabels := metav1.LabelSelector{MatchLabels: options.Labels}
-	selector, err := metav1.LabelSelectorAsSelector(&matchLabels)
-	if err != nil {
-		return nil, err
-	}
-	if err := c.KubeClient.List(ctx, list, &client.ListOptions{
-		LabelSelector: selector,
-	}); err != nil {
-		return nil, err
-	}
+func (c contextSecretServiceImpl) ListContextSecrets(ctx context.Context, options v1types.ListOptions) ([]*entity.ContextSecretEntity, *v1types.ListMeta, error) {
+	return listObjects(ctx, c.KubeClient, new(bdcv1alpha1.ContextSecretList), options, func(obj runtime.Object) (*entity.ContextSecretEntity, listItem, bool) {
+		return contextSecretListItem(ctx, obj)
+	})
+}
 
-	var ctxSecrets []*entity.ContextSecretEntity
-	for _, item := range list.Items {
-		ctxSecrets = append(ctxSecrets, entity.Object2ContextSecretEntity(&item))
+// contextSecretListItem converts the listed context secret, false if it's invisible to the caller
+func contextSecretListItem(ctx context.Context, obj runtime.Object) (*entity.ContextSecretEntity, listItem, bool) {
+	e := entity.Object2ContextSecretEntity(obj.(*bdcv1alpha1.ContextSecret))
+	if e == nil || !visible(ctx, e.BDC) {
+		return nil, listItem{}, false
 	}
-	return ctxSecrets, nil
+	return e, listItem{
+		name:       e.MetaName,
+		typ:        e.Type,
+		status:     e.Status,
+		createTime: e.CreateTime.Time,
+		updateTime: e.UpdateTime.Time,
+	}, true
 }
 
 func (c contextSecretServiceImpl) GetContextSecret(ctx context.Context, name string) (*entity.ContextSecretEntity, error) {
@@ -81,5 +82,9 @@ func (c contextSecretServiceImpl) GetContextSecret(ctx context.Context, name str
 	if err := c.KubeClient.Get(ctx, client.ObjectKey{Name: name}, ctxSecret); err != nil {
 		return nil, err
 	}
-	return entity.Object2ContextSecretEntity(ctxSecret), nil
+	e := entity.Object2ContextSecretEntity(ctxSecret)
+	if !visible(ctx, e.BDC) {
+		return nil, invisible("contextsecrets", name)
+	}
+	return e, nil
 }
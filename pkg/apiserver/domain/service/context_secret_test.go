@@ -68,13 +68,13 @@ var _ = Describe("Test context secret service function", func() {
 
 	It("Test ListContextSecrets function", func() {
 		options := v1dto.ListOptions{}
-		_, err := contextSecretService.ListContextSecrets(context.TODO(), options)
+		_, _, err := contextSecretService.ListContextSecrets(context.TODO(), options)
 		Expect(err).Should(BeNil())
 	})
 
 	It("Test ListContextSecrets function  by their labels as a selector to restrict the list of returned objects", func() {
 		options := v1dto.ListOptions{Labels: map[string]string{constants.AnnotationOrgName: testBigDataClusterOrg}}
-		_, err := contextSecretService.ListContextSecrets(context.TODO(), options)
+		_, _, err := contextSecretService.ListContextSecrets(context.TODO(), options)
 		Expect(err).Should(BeNil())
 	})
 
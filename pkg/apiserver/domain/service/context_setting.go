@@ -24,14 +24,14 @@ import (
 	"kdp-oam-operator/pkg/apiserver/infrastructure/clients"
 	"kdp-oam-operator/pkg/utils/log"
 
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 // ContextSettingService context setting service
 type ContextSettingService interface {
-	ListContextSettings(ctx context.Context, listOptions v1types.ListOptions) ([]*entity.ContextSettingEntity, error)
+	ListContextSettings(ctx context.Context, listOptions v1types.ListOptions) ([]*entity.ContextSettingEntity, *v1types.ListMeta, error)
 	GetContextSetting(ctx context.Context, mame string) (*entity.ContextSettingEntity, error)
 }
 
@@ -56,24 +56,25 @@ type contextSettingServiceImpl struct {
 	KubeConfig *rest.Config
 }
 
-func (c contextSettingServiceImpl) ListContextSettings(ctx context.Context, options v1types.ListOptions) ([]*entity.ContextSettingEntity, error) {
-	list := new(bdcv1alpha1.ContextSettingList)
-	matchLabels := metav1.LabelSelector{MatchLabels: options.Labels}
-	selector, err := metav1.LabelSelectorAsSelector(&matchLabels)
-	if err != nil {
-		return nil, err
-	}
-	if err := c.KubeClient.List(ctx, list, &client.ListOptions{
-		LabelSelector: selector,
-	}); err != nil {
-		return nil, err
-	}
+func (c contextSettingServiceImpl) ListContextSettings(ctx context.Context, options v1types.ListOptions) ([]*entity.ContextSettingEntity, *v1types.ListMeta, error) {
+	return listObjects(ctx, c.KubeClient, new(bdcv1alpha1.ContextSettingList), options, func(obj runtime.Object) (*entity.ContextSettingEntity, listItem, bool) {
+		return contextSettingListItem(ctx, obj)
+	})
+}
 
-	var ctxSettings []*entity.ContextSettingEntity
-	for _, item := range list.Items {
-		ctxSettings = append(ctxSettings, entity.Object2ContextSettingEntity(&item))
+// contextSettingListItem converts the listed context setting, false if it's invisible to the caller
+func contextSettingListItem(ctx context.Context, obj runtime.Object) (*entity.ContextSettingEntity, listItem, bool) {
+	e := entity.Object2ContextSettingEntity(obj.(*bdcv1alpha1.ContextSetting))
+	if e == nil || !visible(ctx, e.BDC) {
+		return nil, listItem{}, false
 	}
-	return ctxSettings, nil
+	return e, listItem{
+		name:       e.MetaName,
+		typ:        e.Type,
+		status:     e.Status,
+		createTime: e.CreateTime.Time,
+		updateTime: e.UpdateTime.Time,
+	}, true
 }
 
 func (c contextSettingServiceImpl) GetContextSetting(ctx context.Context, name string) (*entity.ContextSettingEntity, error) {
@@ -81,5 +82,9 @@ func (c contextSettingServiceImpl) GetContextSetting(ctx context.Context, name s
 	if err := c.KubeClient.Get(ctx, client.ObjectKey{Name: name}, ctxSetting); err != nil {
 		return nil, err
 	}
-	return entity.Object2ContextSettingEntity(ctxSetting), nil
+	e := entity.Object2ContextSettingEntity(ctxSetting)
+	if !visible(ctx, e.BDC) {
+		return nil, invisible("contextsettings", name)
+	}
+	return e, nil
 }
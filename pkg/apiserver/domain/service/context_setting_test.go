@@ -69,13 +69,13 @@ var _ = Describe("Test context setting service function", func() {
 
 	It("Test ListContextSettings function", func() {
 		options := v1dto.ListOptions{}
-		_, err := contextSettingService.ListContextSettings(context.TODO(), options)
+		_, _, err := contextSettingService.ListContextSettings(context.TODO(), options)
 		Expect(err).Should(BeNil())
 	})
 
 	It("Test ListContextSettings function  by their labels as a selector to restrict the list of returned objects", func() {
 		options := v1dto.ListOptions{Labels: map[string]string{constants.AnnotationOrgName: testBigDataClusterOrg}}
-		_, err := contextSettingService.ListContextSettings(context.TODO(), options)
+		_, _, err := contextSettingService.ListContextSettings(context.TODO(), options)
 		Expect(err).Should(BeNil())
 	})
 
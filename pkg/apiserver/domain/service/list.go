@@ -0,0 +1,273 @@
+/*
+Copyright 2024 KDP(Kubernetes Data Platform).
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	v1types "kdp-oam-operator/pkg/apiserver/apis/v1/dto"
+	"kdp-oam-operator/pkg/apiserver/exception"
+	"sort"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Sorting of the list options
+const (
+	SortByName       = "name"
+	SortByCreateTime = "createTime"
+	SortByUpdateTime = "updateTime"
+	SortByStatus     = "status"
+
+	OrderAsc  = "asc"
+	OrderDesc = "desc"
+)
+
+// listChunkSize is the number of the objects requested from the kube-apiserver at a time when all of them are listed
+const listChunkSize = 500
+
+// listItem is the fields of a listed object which the list options filter and sort by
+type listItem struct {
+	name       string
+	typ        string
+	status     string
+	createTime time.Time
+	updateTime time.Time
+}
+
+// listToken is the decoded continue token. The pages sorted by name ascending, which is the order of the
+// kube-apiserver, resume from the kube-apiserver continue token, skipping the objects of its chunk served already.
+// The pages of the other sortings resume from the offset in the sorted objects.
+type listToken struct {
+	SortBy   string `json:"sortBy,omitempty"`
+	Order    string `json:"order,omitempty"`
+	Continue string `json:"continue,omitempty"`
+	Skip     int    `json:"skip,omitempty"`
+	// Offset is the number of the matched objects before the page
+	Offset int `json:"offset,omitempty"`
+}
+
+func encodeListToken(token listToken) string {
+	data, _ := json.Marshal(token)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeListToken(value string) (listToken, error) {
+	var token listToken
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return token, err
+	}
+	err = json.Unmarshal(data, &token)
+	return token, err
+}
+
+// listSelector returns the label selector of the list options
+func listSelector(options v1types.ListOptions) labels.Selector {
+	selector := labels.SelectorFromSet(options.Labels)
+	if options.LabelSelector != nil {
+		requirements, _ := options.LabelSelector.Requirements()
+		selector = selector.Add(requirements...)
+	}
+	return selector
+}
+
+// matchesFilters returns whether the item passes the type and status filters of the list options
+func matchesFilters(options v1types.ListOptions, item listItem) bool {
+	return (options.Types.Len() == 0 || options.Types.Has(item.typ)) &&
+		(options.Statuses.Len() == 0 || options.Statuses.Has(item.status))
+}
+
+// listObjects lists the objects of the list type matching the options by the kube-apiserver list continuation, the
+// objects are converted by convert, which skips the objects invisible to the caller
+func listObjects[T any](ctx context.Context, kubeClient client.Client, list client.ObjectList, options v1types.ListOptions,
+	convert func(obj runtime.Object) (T, listItem, bool)) ([]T, *v1types.ListMeta, error) {
+	selector := listSelector(options)
+	if options.SortBy == "" {
+		options.SortBy = SortByName
+	}
+	if options.Order == "" {
+		options.Order = OrderAsc
+	}
+	token := listToken{SortBy: options.SortBy, Order: options.Order}
+	if options.Continue != "" {
+		decoded, err := decodeListToken(options.Continue)
+		if err != nil || decoded.SortBy != options.SortBy || decoded.Order != options.Order {
+			return nil, nil, exception.ErrInvalidListOptions
+		}
+		token = decoded
+	}
+
+	// listChunk lists the objects from the continue token sorted by name, the order of the kube-apiserver
+	listChunk := func(continueToken string, limit int64) ([]runtime.Object, error) {
+		err := kubeClient.List(ctx, list, &client.ListOptions{LabelSelector: selector, Limit: limit, Continue: continueToken})
+		if apierrors.IsResourceExpired(err) {
+			return nil, exception.ErrInvalidListOptions
+		}
+		if err != nil {
+			return nil, err
+		}
+		objects, err := meta.ExtractList(list)
+		if err != nil {
+			return nil, err
+		}
+		sort.SliceStable(objects, func(i, j int) bool {
+			return objectName(objects[i]) < objectName(objects[j])
+		})
+		return objects, nil
+	}
+	match := func(obj runtime.Object) (T, listItem, bool) {
+		object, item, ok := convert(obj)
+		return object, item, ok && matchesFilters(options, item)
+	}
+
+	if options.Limit > 0 && options.SortBy == SortByName && options.Order == OrderAsc {
+		page := make([]T, 0, options.Limit)
+		total := token.Offset
+		var next *listToken
+		continueToken, skip := token.Continue, token.Skip
+		for {
+			limit := options.Limit - int64(len(page)) + int64(skip)
+			if next != nil {
+				limit = listChunkSize
+			}
+			objects, err := listChunk(continueToken, limit)
+			if err != nil {
+				return nil, nil, err
+			}
+			for i := skip; i < len(objects); i++ {
+				object, _, ok := match(objects[i])
+				switch {
+				case !ok:
+				case next != nil:
+					// count the matched objects after the page for the total
+					total++
+				case int64(len(page)) == options.Limit:
+					next = &listToken{SortBy: token.SortBy, Order: token.Order, Continue: continueToken, Skip: i}
+					total++
+				default:
+					page = append(page, object)
+				}
+			}
+			continueToken, skip = list.GetContinue(), 0
+			if continueToken == "" {
+				break
+			}
+			if next == nil && int64(len(page)) == options.Limit {
+				next = &listToken{SortBy: token.SortBy, Order: token.Order, Continue: continueToken}
+			}
+		}
+		listMeta := &v1types.ListMeta{Total: total + len(page)}
+		if next != nil {
+			next.Offset = token.Offset + len(page)
+			listMeta.Continue = encodeListToken(*next)
+		}
+		return page, listMeta, nil
+	}
+
+	type matched struct {
+		object T
+		item   listItem
+	}
+	var all []matched
+	for continueToken := ""; ; {
+		objects, err := listChunk(continueToken, listChunkSize)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, obj := range objects {
+			if object, item, ok := match(obj); ok {
+				all = append(all, matched{object: object, item: item})
+			}
+		}
+		if continueToken = list.GetContinue(); continueToken == "" {
+			break
+		}
+	}
+	sort.SliceStable(all, func(i, j int) bool {
+		return lessListItem(all[i].item, all[j].item, options.SortBy, options.Order == OrderDesc)
+	})
+	listMeta := &v1types.ListMeta{Total: len(all)}
+	start, end := token.Offset, len(all)
+	if start > end {
+		start = end
+	}
+	if options.Limit > 0 && start+int(options.Limit) < end {
+		end = start + int(options.Limit)
+		listMeta.Continue = encodeListToken(listToken{SortBy: token.SortBy, Order: token.Order, Offset: end})
+	}
+	page := make([]T, 0, end-start)
+	for _, m := range all[start:end] {
+		page = append(page, m.object)
+	}
+	return page, listMeta, nil
+}
+
+// objectName returns the name of the listed object
+func objectName(obj runtime.Object) string {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return ""
+	}
+	return accessor.GetName()
+}
+
+// lessListItem compares the items by the field, the items of the same field are sorted by name
+func lessListItem(a, b listItem, sortBy string, desc bool) bool {
+	var cmp int
+	switch sortBy {
+	case SortByCreateTime:
+		cmp = compareTime(a.createTime, b.createTime)
+	case SortByUpdateTime:
+		cmp = compareTime(a.updateTime, b.updateTime)
+	case SortByStatus:
+		cmp = compareString(a.status, b.status)
+	}
+	if cmp == 0 {
+		cmp = compareString(a.name, b.name)
+	}
+	if desc {
+		return cmp > 0
+	}
+	return cmp < 0
+}
+
+func compareTime(a, b time.Time) int {
+	switch {
+	case a.Before(b):
+		return -1
+	case a.After(b):
+		return 1
+	}
+	return 0
+}
+
+func compareString(a, b string) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
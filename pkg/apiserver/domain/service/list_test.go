@@ -0,0 +1,226 @@
+/*
+Copyright 2024 KDP(Kubernetes Data Platform).
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"errors"
+	bdcv1alpha1 "kdp-oam-operator/api/bdc/v1alpha1"
+	v1dto "kdp-oam-operator/pkg/apiserver/apis/v1/dto"
+	"kdp-oam-operator/pkg/apiserver/exception"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// chunkingClient serves the lists in chunks as the kube-apiserver does, the continue token is the index of the
+// next object in the objects sorted by name
+type chunkingClient struct {
+	client.Client
+	requests int
+}
+
+func (c *chunkingClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	c.requests++
+	options := (&client.ListOptions{}).ApplyOptions(opts)
+	if err := c.Client.List(ctx, list, &client.ListOptions{LabelSelector: options.LabelSelector}); err != nil {
+		return err
+	}
+	objects, err := meta.ExtractList(list)
+	if err != nil {
+		return err
+	}
+	sort.Slice(objects, func(i, j int) bool {
+		return objectName(objects[i]) < objectName(objects[j])
+	})
+	start := 0
+	if options.Continue != "" {
+		start, _ = strconv.Atoi(options.Continue)
+	}
+	end := len(objects)
+	continueToken := ""
+	if options.Limit > 0 && start+int(options.Limit) < end {
+		end = start + int(options.Limit)
+		continueToken = strconv.Itoa(end)
+	}
+	if err := meta.SetList(list, objects[start:end]); err != nil {
+		return err
+	}
+	list.SetContinue(continueToken)
+	return nil
+}
+
+func newListTestService(t *testing.T) (*applicationServiceImpl, *chunkingClient) {
+	s := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(s)
+	_ = bdcv1alpha1.AddToScheme(s)
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	app := func(name, appType, status, tier string, age int) client.Object {
+		return &bdcv1alpha1.Application{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Labels:            map[string]string{"tier": tier},
+				CreationTimestamp: metav1.NewTime(created.Add(-time.Duration(age) * time.Hour)),
+			},
+			Spec:   bdcv1alpha1.ApplicationSpec{Type: appType},
+			Status: bdcv1alpha1.ApplicationStatus{Status: status},
+		}
+	}
+	kubeClient := &chunkingClient{Client: fake.NewClientBuilder().WithScheme(s).WithObjects(
+		app("app-1", "mysql", "running", "db", 3),
+		app("app-2", "kafka", "running", "mq", 1),
+		app("app-3", "mysql", "failed", "db", 5),
+		app("app-4", "redis", "running", "cache", 2),
+		app("app-5", "mysql", "running", "db", 4),
+	).Build()}
+	return &applicationServiceImpl{KubeClient: kubeClient}, kubeClient
+}
+
+// listAllPages lists the applications page by page and returns the names of the pages
+func listAllPages(t *testing.T, service *applicationServiceImpl, options v1dto.ListOptions) ([][]string, int) {
+	var pages [][]string
+	total := 0
+	for i := 0; i < 10; i++ {
+		apps, listMeta, err := service.ListApplications(context.Background(), options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var names []string
+		for _, app := range apps {
+			names = append(names, app.Name)
+		}
+		pages = append(pages, names)
+		total = listMeta.Total
+		if listMeta.Continue == "" {
+			return pages, total
+		}
+		options.Continue = listMeta.Continue
+	}
+	t.Fatal("too many pages")
+	return nil, 0
+}
+
+func TestListPagination(t *testing.T) {
+	tier, err := labels.Parse("tier in (db,cache)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		options v1dto.ListOptions
+		pages   [][]string
+		total   int
+	}{
+		{
+			name:    "all",
+			options: v1dto.ListOptions{},
+			pages:   [][]string{{"app-1", "app-2", "app-3", "app-4", "app-5"}},
+			total:   5,
+		},
+		{
+			name:    "name pages",
+			options: v1dto.ListOptions{Limit: 2},
+			pages:   [][]string{{"app-1", "app-2"}, {"app-3", "app-4"}, {"app-5"}},
+			total:   5,
+		},
+		{
+			name:    "filtered name pages",
+			options: v1dto.ListOptions{Limit: 1, Types: sets.NewString("mysql"), Statuses: sets.NewString("running")},
+			pages:   [][]string{{"app-1"}, {"app-5"}},
+			total:   2,
+		},
+		{
+			name:    "set based selector",
+			options: v1dto.ListOptions{Limit: 3, LabelSelector: tier},
+			pages:   [][]string{{"app-1", "app-3", "app-4"}, {"app-5"}},
+			total:   4,
+		},
+		{
+			name:    "create time desc pages",
+			options: v1dto.ListOptions{Limit: 2, SortBy: SortByCreateTime, Order: OrderDesc},
+			pages:   [][]string{{"app-2", "app-4"}, {"app-1", "app-5"}, {"app-3"}},
+			total:   5,
+		},
+		{
+			name:    "status",
+			options: v1dto.ListOptions{SortBy: SortByStatus},
+			pages:   [][]string{{"app-3", "app-1", "app-2", "app-4", "app-5"}},
+			total:   5,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, _ := newListTestService(t)
+			pages, total := listAllPages(t, service, tt.options)
+			if total != tt.total {
+				t.Errorf("total = %d, want %d", total, tt.total)
+			}
+			if len(pages) != len(tt.pages) {
+				t.Fatalf("pages = %v, want %v", pages, tt.pages)
+			}
+			for i := range pages {
+				if len(pages[i]) != len(tt.pages[i]) {
+					t.Fatalf("pages = %v, want %v", pages, tt.pages)
+				}
+				for j := range pages[i] {
+					if pages[i][j] != tt.pages[i][j] {
+						t.Fatalf("pages = %v, want %v", pages, tt.pages)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestListResumesFromContinueToken(t *testing.T) {
+	service, kubeClient := newListTestService(t)
+	_, listMeta, err := service.ListApplications(context.Background(), v1dto.ListOptions{Limit: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	kubeClient.requests = 0
+	apps, _, err := service.ListApplications(context.Background(), v1dto.ListOptions{Limit: 2, Continue: listMeta.Continue})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(apps) != 2 || apps[0].Name != "app-3" {
+		t.Fatalf("apps = %v, want app-3 and app-4", apps)
+	}
+	// a request for the page and another counting the rest objects
+	if kubeClient.requests != 2 {
+		t.Errorf("requests = %d, want 2", kubeClient.requests)
+	}
+
+	_, _, err = service.ListApplications(context.Background(), v1dto.ListOptions{Limit: 2, SortBy: SortByStatus, Continue: listMeta.Continue})
+	if !errors.Is(err, exception.ErrInvalidListOptions) {
+		t.Errorf("err = %v, want ErrInvalidListOptions for the token of another sorting", err)
+	}
+	_, _, err = service.ListApplications(context.Background(), v1dto.ListOptions{Limit: 2, Continue: "invalid"})
+	if !errors.Is(err, exception.ErrInvalidListOptions) {
+		t.Errorf("err = %v, want ErrInvalidListOptions for the invalid token", err)
+	}
+}
@@ -0,0 +1,41 @@
+/*
+Copyright 2024 KDP(Kubernetes Data Platform).
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	bdcv1alpha1 "kdp-oam-operator/api/bdc/v1alpha1"
+	"kdp-oam-operator/pkg/apiserver/domain/entity"
+	"kdp-oam-operator/pkg/apiserver/infrastructure/tenancy"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// visible returns whether the object of the BigDataCluster is visible to the caller of ctx by the orgs and
+// BigDataClusters the caller belongs to
+func visible(ctx context.Context, bdc *entity.BigDataClusterEntity) bool {
+	m := tenancy.MembershipFrom(ctx)
+	if bdc == nil {
+		return m == nil
+	}
+	return m.Allows(bdc.OrgName, bdc.Name)
+}
+
+// invisible returns the NotFound error of the object invisible to the caller, so that its existence isn't revealed
+func invisible(resource, name string) error {
+	return apierrors.NewNotFound(bdcv1alpha1.GroupVersion.WithResource(resource).GroupResource(), name)
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2024 KDP(Kubernetes Data Platform).
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	bdcv1alpha1 "kdp-oam-operator/api/bdc/v1alpha1"
+	v1dto "kdp-oam-operator/pkg/apiserver/apis/v1/dto"
+	"kdp-oam-operator/pkg/apiserver/infrastructure/tenancy"
+	"kdp-oam-operator/pkg/controllers/bdc/constants"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestTenancyScopesObjects(t *testing.T) {
+	s := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(s)
+	_ = bdcv1alpha1.AddToScheme(s)
+	tenantLabels := func(org, bdc string) map[string]string {
+		return map[string]string{constants.LabelBDCOrgName: org, constants.LabelBDCName: bdc}
+	}
+	kubeClient := fake.NewClientBuilder().WithScheme(s).WithObjects(
+		&bdcv1alpha1.BigDataCluster{ObjectMeta: metav1.ObjectMeta{Name: "bdc-a", Annotations: map[string]string{constants.AnnotationOrgName: "org-a"}}},
+		&bdcv1alpha1.BigDataCluster{ObjectMeta: metav1.ObjectMeta{Name: "bdc-b", Annotations: map[string]string{constants.AnnotationOrgName: "org-b"}}},
+		&bdcv1alpha1.Application{ObjectMeta: metav1.ObjectMeta{Name: "bdc-a-app", Labels: tenantLabels("org-a", "bdc-a")}},
+		&bdcv1alpha1.Application{ObjectMeta: metav1.ObjectMeta{Name: "bdc-b-app", Labels: tenantLabels("org-b", "bdc-b")}},
+		&bdcv1alpha1.ContextSetting{ObjectMeta: metav1.ObjectMeta{Name: "bdc-a-setting", Labels: tenantLabels("org-a", "bdc-a")}},
+		&bdcv1alpha1.ContextSetting{ObjectMeta: metav1.ObjectMeta{Name: "bdc-b-setting", Labels: tenantLabels("org-b", "bdc-b")}},
+		&bdcv1alpha1.ContextSecret{ObjectMeta: metav1.ObjectMeta{Name: "bdc-a-secret", Labels: tenantLabels("org-a", "bdc-a")}},
+		&bdcv1alpha1.ContextSecret{ObjectMeta: metav1.ObjectMeta{Name: "bdc-b-secret", Labels: tenantLabels("org-b", "bdc-b")}},
+	).Build()
+	bdcService := &bigDataClusterServiceImpl{KubeClient: kubeClient}
+	applicationService := &applicationServiceImpl{KubeClient: kubeClient}
+	settingService := &contextSettingServiceImpl{KubeClient: kubeClient}
+	secretService := &contextSecretServiceImpl{KubeClient: kubeClient}
+
+	m := tenancy.NewMembership()
+	m.Orgs.Insert("org-a")
+	ctx := tenancy.WithMembership(context.Background(), m)
+
+	bdcs, _, err := bdcService.ListBigDataClusters(ctx, v1dto.ListOptions{})
+	if err != nil || len(bdcs) != 1 || bdcs[0].Name != "bdc-a" {
+		t.Errorf("ListBigDataClusters() = %v, %v, want bdc-a only", bdcs, err)
+	}
+	if _, err := bdcService.GetBigDataCluster(ctx, "bdc-b"); !apierrors.IsNotFound(err) {
+		t.Errorf("GetBigDataCluster(bdc-b) error = %v, want NotFound", err)
+	}
+
+	apps, _, err := applicationService.ListApplications(ctx, v1dto.ListOptions{})
+	if err != nil || len(apps) != 1 || apps[0].Name != "bdc-a-app" {
+		t.Errorf("ListApplications() = %v, %v, want bdc-a-app only", apps, err)
+	}
+	if _, err := applicationService.GetApplication(ctx, "bdc-b-app"); !apierrors.IsNotFound(err) {
+		t.Errorf("GetApplication(bdc-b-app) error = %v, want NotFound", err)
+	}
+	if err := applicationService.DeleteApplication(ctx, "bdc-b-app"); !apierrors.IsNotFound(err) {
+		t.Errorf("DeleteApplication(bdc-b-app) error = %v, want NotFound", err)
+	}
+
+	settings, _, err := settingService.ListContextSettings(ctx, v1dto.ListOptions{})
+	if err != nil || len(settings) != 1 || settings[0].BDC.Name != "bdc-a" {
+		t.Errorf("ListContextSettings() = %v, %v, want the one of bdc-a only", settings, err)
+	}
+	if _, err := settingService.GetContextSetting(ctx, "bdc-b-setting"); !apierrors.IsNotFound(err) {
+		t.Errorf("GetContextSetting(bdc-b-setting) error = %v, want NotFound", err)
+	}
+
+	secrets, _, err := secretService.ListContextSecrets(ctx, v1dto.ListOptions{})
+	if err != nil || len(secrets) != 1 || secrets[0].BDC.Name != "bdc-a" {
+		t.Errorf("ListContextSecrets() = %v, %v, want the one of bdc-a only", secrets, err)
+	}
+	if _, err := secretService.GetContextSecret(ctx, "bdc-b-secret"); !apierrors.IsNotFound(err) {
+		t.Errorf("GetContextSecret(bdc-b-secret) error = %v, want NotFound", err)
+	}
+
+	// the callers without membership are unrestricted
+	apps, _, err = applicationService.ListApplications(context.Background(), v1dto.ListOptions{})
+	if err != nil || len(apps) != 2 {
+		t.Errorf("ListApplications() without membership = %v, %v, want all", apps, err)
+	}
+}
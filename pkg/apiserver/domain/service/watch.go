@@ -0,0 +1,106 @@
+/*
+Copyright 2024 KDP(Kubernetes Data Platform).
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	bdcv1alpha1 "kdp-oam-operator/api/bdc/v1alpha1"
+	v1types "kdp-oam-operator/pkg/apiserver/apis/v1/dto"
+	"kdp-oam-operator/pkg/apiserver/exception"
+	"kdp-oam-operator/pkg/apiserver/infrastructure/clients"
+	"kdp-oam-operator/pkg/apiserver/infrastructure/watch"
+	"kdp-oam-operator/pkg/utils/log"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// WatchService watches the changes of the objects visible to the caller, the events carry the entities
+type WatchService interface {
+	WatchBigDataClusters(ctx context.Context, options v1types.ListOptions, resourceVersion string) (*watch.Watcher, error)
+	WatchApplications(ctx context.Context, options v1types.ListOptions, resourceVersion string) (*watch.Watcher, error)
+	WatchContextSettings(ctx context.Context, options v1types.ListOptions, resourceVersion string) (*watch.Watcher, error)
+	WatchContextSecrets(ctx context.Context, options v1types.ListOptions, resourceVersion string) (*watch.Watcher, error)
+}
+
+type watchServiceImpl struct {
+	KubeClient client.Client
+	Hub        *watch.Hub
+}
+
+// NewWatchService new watch service
+func NewWatchService() WatchService {
+	kubeClient, err := clients.GetKubeClient()
+	if err != nil {
+		log.Logger.Fatalf("get kube client failure %s", err.Error())
+	}
+	return &watchServiceImpl{
+		KubeClient: kubeClient,
+		Hub:        watch.GetHub(),
+	}
+}
+
+func (w watchServiceImpl) WatchBigDataClusters(ctx context.Context, options v1types.ListOptions, resourceVersion string) (*watch.Watcher, error) {
+	return watchObjects(ctx, w.KubeClient, w.Hub, &bdcv1alpha1.BigDataCluster{}, new(bdcv1alpha1.BigDataClusterList), options, resourceVersion, bigDataClusterListItem)
+}
+
+func (w watchServiceImpl) WatchApplications(ctx context.Context, options v1types.ListOptions, resourceVersion string) (*watch.Watcher, error) {
+	return watchObjects(ctx, w.KubeClient, w.Hub, &bdcv1alpha1.Application{}, new(bdcv1alpha1.ApplicationList), options, resourceVersion, applicationListItem)
+}
+
+func (w watchServiceImpl) WatchContextSettings(ctx context.Context, options v1types.ListOptions, resourceVersion string) (*watch.Watcher, error) {
+	return watchObjects(ctx, w.KubeClient, w.Hub, &bdcv1alpha1.ContextSetting{}, new(bdcv1alpha1.ContextSettingList), options, resourceVersion, contextSettingListItem)
+}
+
+func (w watchServiceImpl) WatchContextSecrets(ctx context.Context, options v1types.ListOptions, resourceVersion string) (*watch.Watcher, error) {
+	return watchObjects(ctx, w.KubeClient, w.Hub, &bdcv1alpha1.ContextSecret{}, new(bdcv1alpha1.ContextSecretList), options, resourceVersion, contextSecretListItem)
+}
+
+// watchObjects watches the objects of the type of obj matching the label selector and the filters of the options,
+// the objects are converted by convert, which skips the objects invisible to the caller
+func watchObjects[T any](ctx context.Context, kubeClient client.Client, hub *watch.Hub, obj client.Object, list client.ObjectList,
+	options v1types.ListOptions, resourceVersion string, convert func(ctx context.Context, obj runtime.Object) (T, listItem, bool)) (*watch.Watcher, error) {
+	if hub == nil {
+		return nil, exception.ErrWatchUnavailable
+	}
+	// the informers list the objects as the apiserver, the caller must be able to list them as well, which is
+	// checked by the kube-apiserver if the requests are impersonated
+	if err := kubeClient.List(ctx, list, client.Limit(1)); err != nil {
+		return nil, err
+	}
+	selector := listSelector(options)
+	w, err := hub.Watch(ctx, obj, resourceVersion, func(o client.Object) (interface{}, bool) {
+		if !selector.Matches(labels.Set(o.GetLabels())) {
+			return nil, false
+		}
+		// the objects are shared by the informer
+		converted, item, ok := convert(ctx, o.DeepCopyObject())
+		if !ok || !matchesFilters(options, item) {
+			return nil, false
+		}
+		return converted, true
+	})
+	switch {
+	case errors.Is(err, watch.ErrExpired):
+		return nil, exception.ErrWatchExpired
+	case errors.Is(err, watch.ErrInvalidResourceVersion):
+		return nil, exception.ErrInvalidListOptions
+	}
+	return w, err
+}
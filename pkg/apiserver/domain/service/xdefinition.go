@@ -132,6 +132,9 @@ func (a xDefinitionServiceImpl) renderXDefinitionDynamicParameter(ctx context.Co
 					return nil, err
 				}
 				for _, item := range list.Items {
+					if !visible(ctx, entity.Object2ContextSecretEntity(&item).BDC) {
+						continue
+					}
 					if dynamicResRefType == item.Spec.Type {
 						var dynamicResRefValue interface{}
 						if dynamicResRefKey == "" {
@@ -159,6 +162,9 @@ func (a xDefinitionServiceImpl) renderXDefinitionDynamicParameter(ctx context.Co
 					return nil, err
 				}
 				for _, item := range list.Items {
+					if !visible(ctx, entity.Object2ContextSettingEntity(&item).BDC) {
+						continue
+					}
 					if dynamicResRefType == item.Spec.Type {
 						var dynamicResRefValue interface{}
 						if dynamicResRefKey == "" {
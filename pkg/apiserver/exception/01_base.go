@@ -53,6 +53,42 @@ var (
 		AppName: "",
 	})
 
+	// ErrInvalidListOptions the list query parameters are invalid or the continue token expires
+	ErrInvalidListOptions = NewExceptCode(400, ErrDetail{
+		ExceptionLevel: "error",
+		ErrInfo: ErrInfo{
+			Code:        100400,
+			Description: "Invalid list options, please check the label selector, the sorting and the limit, or restart the list if the continue token expires.",
+			Solution:    "",
+			ManualURL:   "",
+		},
+		AppName: "",
+	})
+
+	// ErrWatchExpired the resource version to resume the watch from is too old
+	ErrWatchExpired = NewExceptCode(410, ErrDetail{
+		ExceptionLevel: "error",
+		ErrInfo: ErrInfo{
+			Code:        100410,
+			Description: "The resource version is too old to resume the watch, please watch again without it.",
+			Solution:    "",
+			ManualURL:   "",
+		},
+		AppName: "",
+	})
+
+	// ErrWatchUnavailable the informers of the watch aren't set up
+	ErrWatchUnavailable = NewExceptCode(503, ErrDetail{
+		ExceptionLevel: "error",
+		ErrInfo: ErrInfo{
+			Code:        100503,
+			Description: "The watch is unavailable.",
+			Solution:    "",
+			ManualURL:   "",
+		},
+		AppName: "",
+	})
+
 	ErrInitKubeClient = NewExceptCode(500, ErrDetail{
 		ExceptionLevel: "error",
 		ErrInfo: ErrInfo{
@@ -27,4 +27,24 @@ var (
 		},
 		AppName: "",
 	})
+	ErrApplicationRevisionNotFound = NewExceptCode(404, ErrDetail{
+		ExceptionLevel: "error",
+		ErrInfo: ErrInfo{
+			Code:        301404,
+			Description: "specified application revision not found",
+			Solution:    "",
+			ManualURL:   "",
+		},
+		AppName: "",
+	})
+	ErrApplicationResourceNotFound = NewExceptCode(404, ErrDetail{
+		ExceptionLevel: "error",
+		ErrInfo: ErrInfo{
+			Code:        302404,
+			Description: "specified resource not applied by the application",
+			Solution:    "",
+			ManualURL:   "",
+		},
+		AppName: "",
+	})
 )
@@ -0,0 +1,43 @@
+/*
+Copyright 2024 KDP(Kubernetes Data Platform).
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exception
+
+var (
+	// ErrAuditDisabled the audit log isn't enabled
+	ErrAuditDisabled = NewExceptCode(404, ErrDetail{
+		ExceptionLevel: "error",
+		ErrInfo: ErrInfo{
+			Code:        800404,
+			Description: "audit log is disabled, please set --audit-log-path of the apiserver",
+			Solution:    "",
+			ManualURL:   "",
+		},
+		AppName: "",
+	})
+
+	// ErrInvalidAuditQuery the audit query parameters are invalid
+	ErrInvalidAuditQuery = NewExceptCode(400, ErrDetail{
+		ExceptionLevel: "error",
+		ErrInfo: ErrInfo{
+			Code:        800400,
+			Description: "invalid audit query, since and until must be RFC3339 times and limit a positive integer",
+			Solution:    "",
+			ManualURL:   "",
+		},
+		AppName: "",
+	})
+)
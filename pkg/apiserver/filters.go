@@ -0,0 +1,210 @@
+/*
+Copyright 2024 KDP(Kubernetes Data Platform).
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"bytes"
+	"io"
+	"kdp-oam-operator/pkg/apiserver/exception"
+	"kdp-oam-operator/pkg/apiserver/infrastructure/audit"
+	"kdp-oam-operator/pkg/apiserver/infrastructure/auth"
+	"kdp-oam-operator/pkg/apiserver/infrastructure/tenancy"
+	"kdp-oam-operator/pkg/apiserver/utils"
+	"kdp-oam-operator/pkg/utils/log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/emicklei/go-restful/v3"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apiserver/pkg/endpoints/request"
+)
+
+// publicPaths are served without authentication
+var publicPaths = sets.NewString("/healthz", "/readyz", "/apidocs.json")
+
+// auditedMethods are the methods of the mutating requests recorded into the audit log
+var auditedMethods = sets.NewString(http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete)
+
+// isPublic returns whether the request is served without authentication
+func isPublic(req *restful.Request) bool {
+	return req.Request.Method == http.MethodOptions || req.SelectedRoute() == nil || publicPaths.Has(strings.TrimSuffix(req.SelectedRoutePath(), "/"))
+}
+
+// bearerToken returns the bearer token of the Authorization header
+func bearerToken(req *http.Request) string {
+	parts := strings.SplitN(strings.TrimSpace(req.Header.Get("Authorization")), " ", 2)
+	if len(parts) != 2 || !strings.EqualFold(parts[0], "bearer") {
+		return ""
+	}
+	return strings.TrimSpace(parts[1])
+}
+
+// redactedHeader returns the copy of the header without the credentials
+func redactedHeader(header http.Header) http.Header {
+	if header.Get("Authorization") == "" {
+		return header
+	}
+	redacted := header.Clone()
+	redacted.Set("Authorization", "<redacted>")
+	return redacted
+}
+
+// authenticate sets the user of the bearer token into the request context
+func (s *RestServer) authenticate(req *restful.Request, resp *restful.Response, chain *restful.FilterChain) {
+	if s.authenticator == nil || isPublic(req) {
+		chain.ProcessFilter(req, resp)
+		return
+	}
+	token := bearerToken(req.Request)
+	if token == "" {
+		exception.ReturnError(req, resp, exception.ErrUnauthorized)
+		return
+	}
+	res, ok, err := s.authenticator.AuthenticateToken(req.Request.Context(), token)
+	if err != nil {
+		log.Logger.Errorw("authenticate the token failure", "path", utils.Sanitize(req.Request.URL.Path), "error", err)
+	}
+	if err != nil || !ok {
+		exception.ReturnError(req, resp, exception.ErrUnauthorized)
+		return
+	}
+	req.Request = req.Request.WithContext(request.WithUser(req.Request.Context(), res.User))
+	chain.ProcessFilter(req, resp)
+}
+
+// authorize checks the access of the user to the Resource of the route by the authorizer, the routes without
+// the Resource are forbidden. With localOnly, only the Local routes are checked.
+func (s *RestServer) authorize(req *restful.Request, resp *restful.Response, chain *restful.FilterChain) {
+	if s.authorizer == nil || isPublic(req) {
+		chain.ProcessFilter(req, resp)
+		return
+	}
+	resource, hasResource := req.SelectedRoute().Metadata()[auth.KeyResource].(auth.Resource)
+	if s.localOnly && !resource.Local {
+		chain.ProcessFilter(req, resp)
+		return
+	}
+	u, ok := request.UserFrom(req.Request.Context())
+	if !ok {
+		exception.ReturnError(req, resp, exception.ErrUnauthorized)
+		return
+	}
+	if !hasResource {
+		log.Logger.Warnw("forbid the route without resource", "path", utils.Sanitize(req.SelectedRoutePath()))
+		exception.ReturnError(req, resp, exception.ErrForbidden)
+		return
+	}
+	attributes := &authorizationv1.ResourceAttributes{
+		Group:       resource.Group,
+		Resource:    resource.Resource,
+		Subresource: resource.Subresource,
+		Verb:        resource.Verb,
+	}
+	if resource.NameParameter != "" {
+		attributes.Name = req.PathParameter(resource.NameParameter)
+	}
+	allowed, reason, err := s.authorizer.Authorize(req.Request.Context(), u, attributes)
+	if err != nil {
+		log.Logger.Errorw("authorize the request failure", "user", u.GetName(), "path", utils.Sanitize(req.Request.URL.Path), "error", err)
+	}
+	if !allowed {
+		log.Logger.Infow("forbid the request", "user", u.GetName(), "path", utils.Sanitize(req.Request.URL.Path), "reason", reason)
+		exception.ReturnError(req, resp, exception.ErrForbidden)
+		return
+	}
+	chain.ProcessFilter(req, resp)
+}
+
+// scope sets the membership of the user into the request context, which scopes the objects visible to the user
+func (s *RestServer) scope(req *restful.Request, resp *restful.Response, chain *restful.FilterChain) {
+	if s.membership == nil || isPublic(req) {
+		chain.ProcessFilter(req, resp)
+		return
+	}
+	u, ok := request.UserFrom(req.Request.Context())
+	if !ok {
+		exception.ReturnError(req, resp, exception.ErrUnauthorized)
+		return
+	}
+	m, err := s.membership.MembershipOf(req.Request.Context(), u)
+	if err != nil {
+		log.Logger.Errorw("resolve the membership failure", "user", u.GetName(), "error", err)
+		exception.ReturnError(req, resp, exception.ErrServer)
+		return
+	}
+	req.Request = req.Request.WithContext(tenancy.WithMembership(req.Request.Context(), m))
+	chain.ProcessFilter(req, resp)
+}
+
+// recordAudit records the mutating request into the audit log after it's served, the services complete the target
+// and the properties diff of the event by the request context
+func (s *RestServer) recordAudit(req *restful.Request, resp *restful.Response, chain *restful.FilterChain) {
+	logger := audit.GetLogger()
+	if logger == nil || req.SelectedRoute() == nil || !auditedMethods.Has(req.Request.Method) {
+		chain.ProcessFilter(req, resp)
+		return
+	}
+	e := &audit.Event{
+		Time:     time.Now(),
+		ClientIP: utils.ClientIP(req.Request),
+		Method:   req.Request.Method,
+		Route:    req.SelectedRoutePath(),
+		Path:     req.Request.URL.Path,
+		Target:   auditTarget(req),
+	}
+	if req.Request.Body != nil {
+		body, err := io.ReadAll(req.Request.Body)
+		_ = req.Request.Body.Close()
+		if err == nil {
+			e.RequestBody = audit.Redact(body)
+		}
+		req.Request.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	req.Request = req.Request.WithContext(audit.WithEvent(req.Request.Context(), e))
+	chain.ProcessFilter(req, resp)
+
+	if u, ok := request.UserFrom(req.Request.Context()); ok {
+		e.User = u.GetName()
+		e.Groups = u.GetGroups()
+	}
+	e.Code = resp.StatusCode()
+	if err := logger.Write(e); err != nil {
+		log.Logger.Errorw("record the audit event failure", "path", utils.Sanitize(req.Request.URL.Path), "error", err)
+	}
+}
+
+// auditTarget returns the target of the request by the Resource and the path parameters of the route
+func auditTarget(req *restful.Request) audit.Target {
+	params := req.PathParameters()
+	target := audit.Target{
+		BigDataCluster: params["bdcName"],
+		Application:    params["appName"],
+		Parameters:     params,
+	}
+	if resource, ok := req.SelectedRoute().Metadata()[auth.KeyResource].(auth.Resource); ok {
+		target.Resource = resource.Resource
+		if resource.Subresource != "" {
+			target.Resource += "/" + resource.Subresource
+		}
+		if resource.NameParameter != "" {
+			target.Name = params[resource.NameParameter]
+		}
+	}
+	return target
+}
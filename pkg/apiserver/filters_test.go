@@ -0,0 +1,221 @@
+/*
+Copyright 2024 KDP(Kubernetes Data Platform).
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"context"
+	"io"
+	"kdp-oam-operator/pkg/apiserver/infrastructure/audit"
+	"kdp-oam-operator/pkg/apiserver/infrastructure/auth"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/emicklei/go-restful/v3"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+)
+
+type fakeAuthorizer struct{}
+
+// Authorize allows alice to get the application app-1 and list the audit events only
+func (fakeAuthorizer) Authorize(_ context.Context, u user.Info, attributes *authorizationv1.ResourceAttributes) (bool, string, error) {
+	if u.GetName() != "alice" {
+		return false, "", nil
+	}
+	return attributes.Verb == "get" && attributes.Resource == "applications" && attributes.Name == "app-1" ||
+		attributes.Verb == "list" && attributes.Resource == "auditevents", "", nil
+}
+
+func newAuthTestContainer(localOnly bool) *restful.Container {
+	s := &RestServer{
+		webContainer: restful.NewContainer(),
+		authenticator: authenticator.TokenFunc(func(ctx context.Context, token string) (*authenticator.Response, bool, error) {
+			switch token {
+			case "alice-token":
+				return &authenticator.Response{User: &user.DefaultInfo{Name: "alice"}}, true, nil
+			case "bob-token":
+				return &authenticator.Response{User: &user.DefaultInfo{Name: "bob"}}, true, nil
+			}
+			return nil, false, nil
+		}),
+		authorizer: fakeAuthorizer{},
+		localOnly:  localOnly,
+	}
+	s.webContainer.Filter(s.authenticate)
+	s.webContainer.Filter(s.authorize)
+
+	echoUser := func(req *restful.Request, resp *restful.Response) {
+		name := "anonymous"
+		if u, ok := request.UserFrom(req.Request.Context()); ok {
+			name = u.GetName()
+		}
+		_, _ = resp.Write([]byte(name))
+	}
+	ws := new(restful.WebService)
+	ws.Path("/").Produces(restful.MIME_JSON)
+	ws.Route(ws.GET("/healthz").To(echoUser))
+	ws.Route(ws.GET("/applications/{appName}").To(echoUser).
+		Metadata(auth.KeyResource, auth.Resource{Group: "bdc.kdp.io", Resource: "applications", Verb: "get", NameParameter: "appName"}))
+	ws.Route(ws.GET("/auditevents").To(echoUser).
+		Metadata(auth.KeyResource, auth.Resource{Group: "bdc.kdp.io", Resource: "auditevents", Verb: "list", Local: true}))
+	ws.Route(ws.GET("/unprotected").To(echoUser))
+	s.webContainer.Add(ws)
+	return s.webContainer
+}
+
+func TestAuthFilters(t *testing.T) {
+	tests := []struct {
+		name      string
+		localOnly bool
+		path      string
+		token     string
+		code      int
+		body      string
+	}{
+		{name: "public path", path: "/healthz", code: http.StatusOK, body: "anonymous"},
+		{name: "no token", path: "/applications/app-1", code: http.StatusUnauthorized},
+		{name: "invalid token", path: "/applications/app-1", token: "invalid", code: http.StatusUnauthorized},
+		{name: "allowed", path: "/applications/app-1", token: "alice-token", code: http.StatusOK, body: "alice"},
+		{name: "forbidden name", path: "/applications/app-2", token: "alice-token", code: http.StatusForbidden},
+		{name: "forbidden user", path: "/applications/app-1", token: "bob-token", code: http.StatusForbidden},
+		{name: "route without resource", path: "/unprotected", token: "alice-token", code: http.StatusForbidden},
+		{name: "no route", path: "/missing", token: "alice-token", code: http.StatusNotFound},
+		{name: "local route allowed", path: "/auditevents", token: "alice-token", code: http.StatusOK, body: "alice"},
+		{name: "local route forbidden", path: "/auditevents", token: "bob-token", code: http.StatusForbidden},
+		// the kube-apiserver authorizes the impersonated caller except for the local routes
+		{name: "impersonation remote route", localOnly: true, path: "/applications/app-1", token: "bob-token", code: http.StatusOK, body: "bob"},
+		{name: "impersonation route without resource", localOnly: true, path: "/unprotected", token: "bob-token", code: http.StatusOK, body: "bob"},
+		{name: "impersonation no token", localOnly: true, path: "/auditevents", code: http.StatusUnauthorized},
+		{name: "impersonation local route allowed", localOnly: true, path: "/auditevents", token: "alice-token", code: http.StatusOK, body: "alice"},
+		{name: "impersonation local route forbidden", localOnly: true, path: "/auditevents", token: "bob-token", code: http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.Header.Set("Accept", restful.MIME_JSON)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			rec := httptest.NewRecorder()
+			newAuthTestContainer(tt.localOnly).ServeHTTP(rec, req)
+			if rec.Code != tt.code {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.code)
+			}
+			if tt.body != "" && rec.Body.String() != tt.body {
+				t.Errorf("body = %q, want %q", rec.Body.String(), tt.body)
+			}
+		})
+	}
+}
+
+func TestRedactedHeader(t *testing.T) {
+	header := http.Header{}
+	header.Set("Authorization", "Bearer secret")
+	header.Set("Accept", "application/json")
+	redacted := redactedHeader(header)
+	if redacted.Get("Authorization") == "Bearer secret" {
+		t.Error("the Authorization header is not redacted")
+	}
+	if header.Get("Authorization") != "Bearer secret" {
+		t.Error("the Authorization header of the request is changed")
+	}
+	if redacted.Get("Accept") != "application/json" {
+		t.Errorf("Accept = %q, want application/json", redacted.Get("Accept"))
+	}
+}
+
+func TestRecordAudit(t *testing.T) {
+	logger := audit.NewLogger(filepath.Join(t.TempDir(), "audit.log"), 1, 1, 1)
+	audit.SetLogger(logger)
+	defer func() {
+		audit.SetLogger(nil)
+		_ = logger.Close()
+	}()
+
+	s := &RestServer{
+		webContainer: restful.NewContainer(),
+		authenticator: authenticator.TokenFunc(func(ctx context.Context, token string) (*authenticator.Response, bool, error) {
+			return &authenticator.Response{User: &user.DefaultInfo{Name: "alice", Groups: []string{"dev"}}}, token == "alice-token", nil
+		}),
+	}
+	s.webContainer.Filter(s.recordAudit)
+	s.webContainer.Filter(s.authenticate)
+
+	var served string
+	update := func(req *restful.Request, resp *restful.Response) {
+		body, _ := io.ReadAll(req.Request.Body)
+		served = string(body)
+		audit.SetTarget(req.Request.Context(), "bdc-a", "")
+		audit.SetPropertiesDiff(req.Request.Context(), &runtime.RawExtension{Raw: []byte(`{"replicas":1}`)}, &runtime.RawExtension{Raw: []byte(`{"replicas":2}`)})
+		_, _ = resp.Write([]byte("ok"))
+	}
+	ws := new(restful.WebService)
+	ws.Path("/").Produces(restful.MIME_JSON)
+	ws.Route(ws.PUT("/applications/{appName}").To(update).
+		Metadata(auth.KeyResource, auth.Resource{Group: "bdc.kdp.io", Resource: "applications", Verb: "update", NameParameter: "appName"}))
+	ws.Route(ws.GET("/applications/{appName}").To(update))
+	s.webContainer.Add(ws)
+
+	requests := []struct {
+		method string
+		token  string
+	}{
+		{method: http.MethodPut, token: "alice-token"},
+		{method: http.MethodGet, token: "alice-token"},
+		{method: http.MethodPut, token: "invalid"},
+	}
+	body := `{"properties":{"replicas":2,"password":"p"}}`
+	for _, r := range requests {
+		req := httptest.NewRequest(r.method, "/applications/app-1", strings.NewReader(body))
+		req.Header.Set("Accept", restful.MIME_JSON)
+		req.Header.Set("Authorization", "Bearer "+r.token)
+		s.webContainer.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	if served != body {
+		t.Errorf("served body = %q, want %q", served, body)
+	}
+
+	events, err := logger.Query(audit.Query{Application: "app-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("got %d events, want the 2 PUT requests", len(events))
+	}
+	denied, updated := events[0], events[1]
+	if denied.Code != http.StatusUnauthorized || denied.User != "" {
+		t.Errorf("denied event = %+v, want the unauthorized code without user", denied)
+	}
+	if updated.Code != http.StatusOK || updated.User != "alice" || updated.Route != "/applications/{appName}" {
+		t.Errorf("updated event = %+v", updated)
+	}
+	if updated.Target.Resource != "applications" || updated.Target.Name != "app-1" || updated.Target.BigDataCluster != "bdc-a" {
+		t.Errorf("target = %+v", updated.Target)
+	}
+	if strings.Contains(string(updated.RequestBody), `"p"`) {
+		t.Errorf("request body %s is not redacted", updated.RequestBody)
+	}
+	if len(updated.PropertiesDiff) != 1 || updated.PropertiesDiff[0].Path != "replicas" {
+		t.Errorf("properties diff = %+v", updated.PropertiesDiff)
+	}
+}
@@ -0,0 +1,202 @@
+/*
+Copyright 2024 KDP(Kubernetes Data Platform).
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package audit records the mutating requests of the apiserver into the rotating JSON lines file, and queries them
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// maxLineSize is the max size of a record read by the queries
+const maxLineSize = 4 * 1024 * 1024
+
+// Event is the audit record of a mutating request
+type Event struct {
+	Time           time.Time        `json:"time"`
+	User           string           `json:"user,omitempty"`
+	Groups         []string         `json:"groups,omitempty"`
+	ClientIP       string           `json:"clientIP"`
+	Method         string           `json:"method"`
+	Route          string           `json:"route"`
+	Path           string           `json:"path"`
+	Target         Target           `json:"target"`
+	RequestBody    json.RawMessage  `json:"requestBody,omitempty"`
+	Code           int              `json:"code"`
+	PropertiesDiff []PropertyChange `json:"propertiesDiff,omitempty"`
+}
+
+// Target is the object the request changes
+type Target struct {
+	// Resource is the resource of the route, such as applications or applications/pods
+	Resource       string `json:"resource,omitempty"`
+	Name           string `json:"name,omitempty"`
+	BigDataCluster string `json:"bigDataCluster,omitempty"`
+	Application    string `json:"application,omitempty"`
+	// Parameters are the path parameters of the request
+	Parameters map[string]string `json:"parameters,omitempty"`
+}
+
+// Query filters the audit records, the zero fields match everything
+type Query struct {
+	BigDataCluster string
+	Application    string
+	Since          time.Time
+	Until          time.Time
+	// Limit is the max number of the records returned, from the latest
+	Limit int
+}
+
+// matches returns whether the event matches the query
+func (q Query) matches(e *Event) bool {
+	if q.BigDataCluster != "" && e.Target.BigDataCluster != q.BigDataCluster {
+		return false
+	}
+	if q.Application != "" && e.Target.Application != q.Application {
+		return false
+	}
+	if !q.Since.IsZero() && e.Time.Before(q.Since) {
+		return false
+	}
+	if !q.Until.IsZero() && !e.Time.Before(q.Until) {
+		return false
+	}
+	return true
+}
+
+// Logger writes the audit records to the rotating JSON lines file
+type Logger struct {
+	lock   sync.Mutex
+	writer *lumberjack.Logger
+}
+
+// NewLogger returns the Logger writing to path, the file is rotated once it reaches maxSize megabytes, and the
+// rotated files are removed once there are more than maxBackups of them or they are older than maxAge days
+func NewLogger(path string, maxSize, maxBackups, maxAge int) *Logger {
+	return &Logger{writer: &lumberjack.Logger{
+		Filename:   path,
+		MaxSize:    maxSize,
+		MaxBackups: maxBackups,
+		MaxAge:     maxAge,
+	}}
+}
+
+// Write appends the event as a JSON line
+func (l *Logger) Write(e *Event) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal the audit event")
+	}
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if _, err := l.writer.Write(append(data, '\n')); err != nil {
+		return errors.Wrap(err, "failed to write the audit event")
+	}
+	return nil
+}
+
+// Close closes the current file
+func (l *Logger) Close() error {
+	return l.writer.Close()
+}
+
+// Query returns the events matching the query from the latest to the oldest, the rotated files are read as well
+func (l *Logger) Query(q Query) ([]*Event, error) {
+	files, err := l.files()
+	if err != nil {
+		return nil, err
+	}
+	var events []*Event
+	for _, file := range files {
+		matched, err := readEvents(file, q)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, matched...)
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Time.After(events[j].Time)
+	})
+	if q.Limit > 0 && len(events) > q.Limit {
+		events = events[:q.Limit]
+	}
+	return events, nil
+}
+
+// files returns the rotated files from the oldest to the latest, and the current file
+func (l *Logger) files() ([]string, error) {
+	filename := l.writer.Filename
+	ext := filepath.Ext(filename)
+	prefix := strings.TrimSuffix(filename, ext)
+	backups, err := filepath.Glob(prefix + "-*" + ext)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list the rotated audit files")
+	}
+	sort.Strings(backups)
+	return append(backups, filename), nil
+}
+
+// readEvents returns the events in the file matching the query, the malformed lines are skipped
+func readEvents(file string, q Query) ([]*Event, error) {
+	f, err := os.Open(filepath.Clean(file))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open the audit file %s", file)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	var events []*Event
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	for scanner.Scan() {
+		e := new(Event)
+		if err := json.Unmarshal(scanner.Bytes(), e); err != nil {
+			continue
+		}
+		if q.matches(e) {
+			events = append(events, e)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrapf(err, "failed to read the audit file %s", file)
+	}
+	return events, nil
+}
+
+var logger *Logger
+
+// SetLogger sets the Logger of the apiserver, nil disables the audit
+func SetLogger(l *Logger) {
+	logger = l
+}
+
+// GetLogger returns the Logger of the apiserver, nil if the audit is disabled
+func GetLogger() *Logger {
+	return logger
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2024 KDP(Kubernetes Data Platform).
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestLoggerQuery(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "audit.log")
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// a rotated file named as lumberjack does
+	rotated, err := json.Marshal(&Event{Time: start, Method: "POST", Target: Target{BigDataCluster: "bdc-a", Application: "app-1"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "audit-2024-01-01T00-00-00.000.log"), append(rotated, '\n'), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	l := NewLogger(path, 1, 1, 1)
+	defer func() {
+		_ = l.Close()
+	}()
+	events := []*Event{
+		{Time: start.Add(time.Hour), Method: "PUT", Target: Target{BigDataCluster: "bdc-a", Application: "app-1"}},
+		{Time: start.Add(2 * time.Hour), Method: "PUT", Target: Target{BigDataCluster: "bdc-b", Application: "app-2"}},
+		{Time: start.Add(3 * time.Hour), Method: "DELETE", Target: Target{BigDataCluster: "bdc-a", Application: "app-1"}},
+	}
+	for _, e := range events {
+		if err := l.Write(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		query   Query
+		methods []string
+	}{
+		{name: "all", query: Query{}, methods: []string{"DELETE", "PUT", "PUT", "POST"}},
+		{name: "bdc", query: Query{BigDataCluster: "bdc-a"}, methods: []string{"DELETE", "PUT", "POST"}},
+		{name: "app", query: Query{Application: "app-2"}, methods: []string{"PUT"}},
+		{name: "time range", query: Query{Since: start.Add(time.Hour), Until: start.Add(3 * time.Hour)}, methods: []string{"PUT", "PUT"}},
+		{name: "limit", query: Query{BigDataCluster: "bdc-a", Limit: 2}, methods: []string{"DELETE", "PUT"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := l.Query(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(tt.methods) {
+				t.Fatalf("got %d events, want %d", len(got), len(tt.methods))
+			}
+			for i, e := range got {
+				if e.Method != tt.methods[i] {
+					t.Errorf("events[%d].Method = %s, want %s", i, e.Method, tt.methods[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRedact(t *testing.T) {
+	body := Redact([]byte(`{"name":"app","properties":{"dbPassword":"p","items":[{"accessKey":"k","size":1}]}}`))
+	var got, want interface{}
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(`{"name":"app","properties":{"dbPassword":"<redacted>","items":[{"accessKey":"<redacted>","size":1}]}}`), &want); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Redact() = %s, want %v", body, want)
+	}
+	if Redact([]byte("not json")) != nil {
+		t.Error("Redact() of the non JSON body should be nil")
+	}
+}
+
+func TestDiff(t *testing.T) {
+	before := &runtime.RawExtension{Raw: []byte(`{"replicas":1,"image":"a","auth":{"token":"t1"},"removed":true}`)}
+	after := &runtime.RawExtension{Raw: []byte(`{"replicas":2,"image":"a","auth":{"token":"t2"},"added":"x"}`)}
+	want := []PropertyChange{
+		{Path: "added", After: "x"},
+		{Path: "auth.token", Before: redacted, After: redacted},
+		{Path: "removed", Before: true},
+		{Path: "replicas", Before: float64(1), After: float64(2)},
+	}
+	if got := Diff(before, after); !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff() = %v, want %v", got, want)
+	}
+
+	// the secrets nested in the lists are redacted, their changes are still detected
+	before = &runtime.RawExtension{Raw: []byte(`{"users":[{"name":"a","password":"x"}]}`)}
+	after = &runtime.RawExtension{Raw: []byte(`{"users":[{"name":"a","password":"y"},{"name":"b","password":"z"}]}`)}
+	want = []PropertyChange{{
+		Path:   "users",
+		Before: []interface{}{map[string]interface{}{"name": "a", "password": redacted}},
+		After: []interface{}{
+			map[string]interface{}{"name": "a", "password": redacted},
+			map[string]interface{}{"name": "b", "password": redacted},
+		},
+	}}
+	if got := Diff(before, after); !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff() of the lists = %v, want %v", got, want)
+	}
+	if changes := Diff(nil, nil); len(changes) != 0 {
+		t.Errorf("Diff(nil, nil) = %v, want empty", changes)
+	}
+}
+
+func TestSetTarget(t *testing.T) {
+	e := &Event{Target: Target{Application: "app-1"}}
+	ctx := WithEvent(context.Background(), e)
+	SetTarget(ctx, "bdc-a", "app-2")
+	if e.Target.BigDataCluster != "bdc-a" || e.Target.Application != "app-1" {
+		t.Errorf("target = %+v, want the bdc set and the application of the route kept", e.Target)
+	}
+	// no event in the context
+	SetTarget(context.Background(), "bdc-b", "app-3")
+}
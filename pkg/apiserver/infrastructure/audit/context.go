@@ -0,0 +1,67 @@
+/*
+Copyright 2024 KDP(Kubernetes Data Platform).
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"context"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+type eventKey struct{}
+
+// recorder is the event of the request being served, the services complete it by the request context
+type recorder struct {
+	lock  sync.Mutex
+	event *Event
+}
+
+// WithEvent returns the copy of ctx carrying the event of the request, which is completed by SetTarget and
+// SetPropertiesDiff
+func WithEvent(ctx context.Context, e *Event) context.Context {
+	return context.WithValue(ctx, eventKey{}, &recorder{event: e})
+}
+
+// SetTarget sets the BigDataCluster and the application of the target object if they are not set by the route
+func SetTarget(ctx context.Context, bdcName, appName string) {
+	update(ctx, func(e *Event) {
+		if e.Target.BigDataCluster == "" {
+			e.Target.BigDataCluster = bdcName
+		}
+		if e.Target.Application == "" {
+			e.Target.Application = appName
+		}
+	})
+}
+
+// SetPropertiesDiff records the change of the Spec.Properties of the target object, the sensitive values are redacted
+func SetPropertiesDiff(ctx context.Context, before, after *runtime.RawExtension) {
+	update(ctx, func(e *Event) {
+		e.PropertiesDiff = Diff(before, after)
+	})
+}
+
+func update(ctx context.Context, fn func(e *Event)) {
+	r, ok := ctx.Value(eventKey{}).(*recorder)
+	if !ok {
+		return
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	fn(r.event)
+}
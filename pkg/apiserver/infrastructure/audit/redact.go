@@ -0,0 +1,149 @@
+/*
+Copyright 2024 KDP(Kubernetes Data Platform).
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// redacted replaces the sensitive values
+const redacted = "<redacted>"
+
+// sensitiveKeys are the substrings of the keys whose values are redacted, compared in lower case
+var sensitiveKeys = []string{"password", "passwd", "secret", "token", "credential", "privatekey", "private_key", "accesskey", "access_key"}
+
+// PropertyChange is the change of a leaf of Spec.Properties, the path is joined by dots
+type PropertyChange struct {
+	Path   string      `json:"path"`
+	Before interface{} `json:"before,omitempty"`
+	After  interface{} `json:"after,omitempty"`
+}
+
+// sensitive returns whether the value of the key is sensitive
+func sensitive(key string) bool {
+	key = strings.ToLower(key)
+	for _, s := range sensitiveKeys {
+		if strings.Contains(key, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// Redact returns the JSON body with the sensitive values redacted, nil if the body isn't JSON
+func Redact(body []byte) json.RawMessage {
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return nil
+	}
+	data, err := json.Marshal(redact(value))
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+func redact(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if sensitive(key) {
+				v[key] = redacted
+				continue
+			}
+			v[key] = redact(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redact(item)
+		}
+	}
+	return value
+}
+
+// leaf is a leaf value of the properties, the values under the sensitive keys are compared but never recorded
+type leaf struct {
+	value     interface{}
+	sensitive bool
+}
+
+// recorded returns the value recorded in the PropertyChange, the sensitive values in the lists of the leaf are
+// redacted as well
+func (l leaf) recorded() interface{} {
+	if l.sensitive && l.value != nil {
+		return redacted
+	}
+	return redact(runtime.DeepCopyJSONValue(l.value))
+}
+
+// Diff returns the changes of the leaves from before to after sorted by the paths, the sensitive values are redacted
+func Diff(before, after *runtime.RawExtension) []PropertyChange {
+	beforeLeaves := map[string]leaf{}
+	afterLeaves := map[string]leaf{}
+	flatten("", decode(before), false, beforeLeaves)
+	flatten("", decode(after), false, afterLeaves)
+
+	var changes []PropertyChange
+	for path, l := range beforeLeaves {
+		if afterLeaf, ok := afterLeaves[path]; !ok || !reflect.DeepEqual(l.value, afterLeaf.value) {
+			changes = append(changes, PropertyChange{Path: path, Before: l.recorded(), After: afterLeaf.recorded()})
+		}
+	}
+	for path, l := range afterLeaves {
+		if _, ok := beforeLeaves[path]; !ok {
+			changes = append(changes, PropertyChange{Path: path, After: l.recorded()})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	return changes
+}
+
+func decode(raw *runtime.RawExtension) interface{} {
+	if raw == nil || len(raw.Raw) == 0 {
+		return nil
+	}
+	var value interface{}
+	if err := json.Unmarshal(raw.Raw, &value); err != nil {
+		return nil
+	}
+	return value
+}
+
+// flatten collects the leaves of value by their paths, the maps are walked into and the others are leaves
+func flatten(path string, value interface{}, isSensitive bool, leaves map[string]leaf) {
+	m, ok := value.(map[string]interface{})
+	if !ok {
+		if path != "" && value != nil {
+			leaves[path] = leaf{value: value, sensitive: isSensitive}
+		}
+		return
+	}
+	for key, item := range m {
+		itemPath := key
+		if path != "" {
+			itemPath = path + "." + key
+		}
+		flatten(itemPath, item, isSensitive || sensitive(key), leaves)
+	}
+}
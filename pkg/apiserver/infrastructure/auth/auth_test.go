@@ -0,0 +1,132 @@
+/*
+Copyright 2024 KDP(Kubernetes Data Platform).
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestTokenAuthenticator(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "tokens.csv")
+	if err := os.WriteFile(tokenFile, []byte("static-token,alice,1001,\"dev,ops\"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	kubeClient := fake.NewSimpleClientset()
+	kubeClient.PrependReactor("create", "tokenreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authenticationv1.TokenReview)
+		if review.Spec.Token == "sa-token" {
+			review.Status = authenticationv1.TokenReviewStatus{
+				Authenticated: true,
+				User: authenticationv1.UserInfo{
+					Username: "system:serviceaccount:default:bob",
+					Groups:   []string{"system:serviceaccounts"},
+				},
+			}
+		}
+		return true, review, nil
+	})
+
+	authn, err := NewTokenAuthenticator(tokenFile, kubeClient.AuthenticationV1().TokenReviews())
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		token  string
+		ok     bool
+		user   string
+		groups []string
+	}{
+		{token: "static-token", ok: true, user: "alice", groups: []string{"dev", "ops"}},
+		{token: "sa-token", ok: true, user: "system:serviceaccount:default:bob", groups: []string{"system:serviceaccounts"}},
+		{token: "unknown-token", ok: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.token, func(t *testing.T) {
+			res, ok, err := authn.AuthenticateToken(context.Background(), tt.token)
+			if tt.ok != ok {
+				t.Fatalf("AuthenticateToken(%q) = %v, %v, want %v", tt.token, ok, err, tt.ok)
+			}
+			if !ok {
+				return
+			}
+			if res.User.GetName() != tt.user {
+				t.Errorf("user = %q, want %q", res.User.GetName(), tt.user)
+			}
+			if len(res.User.GetGroups()) != len(tt.groups) {
+				t.Errorf("groups = %v, want %v", res.User.GetGroups(), tt.groups)
+			}
+		})
+	}
+}
+
+func TestTokenAuthenticatorNotConfigured(t *testing.T) {
+	authn, err := NewTokenAuthenticator("", nil)
+	if err != nil || authn != nil {
+		t.Errorf("NewTokenAuthenticator() = %v, %v, want nil", authn, err)
+	}
+	if _, err := NewTokenAuthenticator(filepath.Join(t.TempDir(), "missing.csv"), nil); err == nil {
+		t.Error("NewTokenAuthenticator() with the missing token file should fail")
+	}
+}
+
+func TestSubjectAccessReviewAuthorizer(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset()
+	kubeClient.PrependReactor("create", "subjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+		attributes := review.Spec.ResourceAttributes
+		review.Status.Allowed = review.Spec.User == "alice" && attributes.Verb == "get" && attributes.Name == "app-1"
+		return true, review, nil
+	})
+	authz := NewSubjectAccessReviewAuthorizer(kubeClient.AuthorizationV1().SubjectAccessReviews())
+
+	tests := []struct {
+		name    string
+		user    string
+		verb    string
+		allowed bool
+	}{
+		{name: "allowed", user: "alice", verb: "get", allowed: true},
+		{name: "denied verb", user: "alice", verb: "delete", allowed: false},
+		{name: "denied user", user: "bob", verb: "get", allowed: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allowed, _, err := authz.Authorize(context.Background(), &user.DefaultInfo{Name: tt.user}, &authorizationv1.ResourceAttributes{
+				Group:    "bdc.kdp.io",
+				Resource: "applications",
+				Verb:     tt.verb,
+				Name:     "app-1",
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if allowed != tt.allowed {
+				t.Errorf("Authorize() = %v, want %v", allowed, tt.allowed)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2024 KDP(Kubernetes Data Platform).
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package auth authenticates the callers of the apiserver by their bearer tokens, and authorizes them by
+// the Kubernetes RBAC
+package auth
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	"k8s.io/apiserver/pkg/authentication/token/cache"
+	"k8s.io/apiserver/pkg/authentication/token/tokenfile"
+	"k8s.io/apiserver/pkg/authentication/token/union"
+	"k8s.io/apiserver/pkg/authentication/user"
+	authenticationv1client "k8s.io/client-go/kubernetes/typed/authentication/v1"
+)
+
+const (
+	// tokenReviewSuccessTTL is how long the user of a token authenticated by TokenReview is cached
+	tokenReviewSuccessTTL = 10 * time.Second
+	// tokenReviewFailureTTL is how long a token rejected by TokenReview is cached
+	tokenReviewFailureTTL = 10 * time.Second
+)
+
+// NewTokenAuthenticator returns the authenticator of the bearer tokens, the tokens in the static token file are
+// looked up first and the others are reviewed by TokenReview if enabled. Nil is returned if neither is configured.
+func NewTokenAuthenticator(tokenAuthFile string, tokenReview authenticationv1client.TokenReviewInterface) (authenticator.Token, error) {
+	var authenticators []authenticator.Token
+	if tokenAuthFile != "" {
+		tokens, err := tokenfile.NewCSV(tokenAuthFile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to load the token file %s", tokenAuthFile)
+		}
+		authenticators = append(authenticators, tokens)
+	}
+	if tokenReview != nil {
+		authenticators = append(authenticators, cache.New(&tokenReviewAuthenticator{client: tokenReview}, false, tokenReviewSuccessTTL, tokenReviewFailureTTL))
+	}
+	if len(authenticators) == 0 {
+		return nil, nil
+	}
+	return union.New(authenticators...), nil
+}
+
+// tokenReviewAuthenticator authenticates the tokens by creating TokenReviews
+type tokenReviewAuthenticator struct {
+	client authenticationv1client.TokenReviewInterface
+}
+
+// AuthenticateToken returns the user of the token reviewed by the kube-apiserver
+func (a *tokenReviewAuthenticator) AuthenticateToken(ctx context.Context, token string) (*authenticator.Response, bool, error) {
+	review, err := a.client.Create(ctx, &authenticationv1.TokenReview{
+		Spec: authenticationv1.TokenReviewSpec{Token: token},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return nil, false, errors.Wrap(err, "failed to review the token")
+	}
+	if !review.Status.Authenticated {
+		if review.Status.Error != "" {
+			return nil, false, errors.New(review.Status.Error)
+		}
+		return nil, false, nil
+	}
+	var extra map[string][]string
+	if len(review.Status.User.Extra) > 0 {
+		extra = make(map[string][]string, len(review.Status.User.Extra))
+		for k, v := range review.Status.User.Extra {
+			extra[k] = v
+		}
+	}
+	return &authenticator.Response{
+		User: &user.DefaultInfo{
+			Name:   review.Status.User.Username,
+			UID:    review.Status.User.UID,
+			Groups: review.Status.User.Groups,
+			Extra:  extra,
+		},
+	}, true, nil
+}
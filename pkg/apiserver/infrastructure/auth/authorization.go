@@ -0,0 +1,98 @@
+/*
+Copyright 2024 KDP(Kubernetes Data Platform).
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/authentication/user"
+	authorizationv1client "k8s.io/client-go/kubernetes/typed/authorization/v1"
+)
+
+// Authorization modes of the apiserver
+const (
+	// AuthorizationModeAlwaysAllow allows every request, the kube clients run as the apiserver itself
+	AuthorizationModeAlwaysAllow = "AlwaysAllow"
+	// AuthorizationModeSubjectAccessReview authorizes every request by a SubjectAccessReview of the Resource
+	// of its route, the kube clients run as the apiserver itself
+	AuthorizationModeSubjectAccessReview = "SubjectAccessReview"
+	// AuthorizationModeImpersonation makes the kube clients impersonate the caller, so that every call to
+	// the kube-apiserver is authorized by the Kubernetes RBAC of the caller, the Local routes are still
+	// authorized by SubjectAccessReviews
+	AuthorizationModeImpersonation = "Impersonation"
+)
+
+// KeyResource is the route metadata key of the Resource accessed by the route
+const KeyResource = "kdp.auth.resource"
+
+// Resource is the Kubernetes resource accessed by a route, the requests of the route are authorized by the access to it
+type Resource struct {
+	Group       string
+	Resource    string
+	Subresource string
+	Verb        string
+	// NameParameter is the path parameter of the resource name, empty if the route accesses all the resources
+	NameParameter string
+	// Local is whether the route is served by the apiserver without calling the kube-apiserver, so that its requests
+	// are authorized by the access to the resource even in the Impersonation mode
+	Local bool
+}
+
+// Authorizer authorizes the user to access the resource
+type Authorizer interface {
+	// Authorize returns whether the user is allowed to access the resource, and the reason of the decision
+	Authorize(ctx context.Context, u user.Info, attributes *authorizationv1.ResourceAttributes) (bool, string, error)
+}
+
+// NewSubjectAccessReviewAuthorizer returns the Authorizer creating SubjectAccessReviews
+func NewSubjectAccessReviewAuthorizer(client authorizationv1client.SubjectAccessReviewInterface) Authorizer {
+	return &subjectAccessReviewAuthorizer{client: client}
+}
+
+type subjectAccessReviewAuthorizer struct {
+	client authorizationv1client.SubjectAccessReviewInterface
+}
+
+// Authorize returns whether the user is allowed by the kube-apiserver to access the resource
+func (a *subjectAccessReviewAuthorizer) Authorize(ctx context.Context, u user.Info, attributes *authorizationv1.ResourceAttributes) (bool, string, error) {
+	var extra map[string]authorizationv1.ExtraValue
+	if len(u.GetExtra()) > 0 {
+		extra = make(map[string]authorizationv1.ExtraValue, len(u.GetExtra()))
+		for k, v := range u.GetExtra() {
+			extra[k] = v
+		}
+	}
+	review, err := a.client.Create(ctx, &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			ResourceAttributes: attributes,
+			User:               u.GetName(),
+			UID:                u.GetUID(),
+			Groups:             u.GetGroups(),
+			Extra:              extra,
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return false, "", errors.Wrap(err, "failed to review the access")
+	}
+	if review.Status.EvaluationError != "" && !review.Status.Allowed {
+		return false, review.Status.Reason, errors.New(review.Status.EvaluationError)
+	}
+	return review.Status.Allowed && !review.Status.Denied, review.Status.Reason, nil
+}
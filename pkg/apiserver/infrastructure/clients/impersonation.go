@@ -0,0 +1,221 @@
+/*
+Copyright 2024 KDP(Kubernetes Data Platform).
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// maxImpersonatingClients is the number of the cached clients of users, the cache is reset once it's full
+const maxImpersonatingClients = 256
+
+var errNoUser = errors.New("no user to impersonate in the request context")
+
+var impersonation bool
+
+// EnableImpersonation makes the kube clients impersonate the user of the request context in every call, so that
+// the Kubernetes RBAC of the user applies. It must be called before the kube client is created.
+func EnableImpersonation() {
+	impersonation = true
+}
+
+// ImpersonatingConfig returns the copy of conf impersonating the user of ctx if the impersonation is enabled,
+// otherwise conf itself
+func ImpersonatingConfig(ctx context.Context, conf *rest.Config) (*rest.Config, error) {
+	if !impersonation {
+		return conf, nil
+	}
+	u, ok := request.UserFrom(ctx)
+	if !ok {
+		return nil, errNoUser
+	}
+	return impersonatingConfig(conf, u), nil
+}
+
+func impersonatingConfig(conf *rest.Config, u user.Info) *rest.Config {
+	impersonating := rest.CopyConfig(conf)
+	impersonating.Impersonate = rest.ImpersonationConfig{
+		UserName: u.GetName(),
+		UID:      u.GetUID(),
+		Groups:   u.GetGroups(),
+		Extra:    u.GetExtra(),
+	}
+	return impersonating
+}
+
+// userKey returns the key of the cached client of the user
+func userKey(u user.Info) string {
+	groups := append([]string{}, u.GetGroups()...)
+	sort.Strings(groups)
+	extras := make([]string, 0, len(u.GetExtra()))
+	for k, v := range u.GetExtra() {
+		extras = append(extras, k+"="+strings.Join(v, ","))
+	}
+	sort.Strings(extras)
+	return strings.Join([]string{u.GetName(), u.GetUID(), strings.Join(groups, ","), strings.Join(extras, ";")}, "\x00")
+}
+
+// impersonatingClient delegates every call to the client impersonating the user of the request context,
+// the embedded client of the apiserver itself only provides the scheme and the RESTMapper
+type impersonatingClient struct {
+	client.Client
+	config *rest.Config
+
+	lock    sync.Mutex
+	clients map[string]client.Client
+}
+
+func newImpersonatingClient(conf *rest.Config, c client.Client) *impersonatingClient {
+	return &impersonatingClient{
+		Client:  c,
+		config:  conf,
+		clients: map[string]client.Client{},
+	}
+}
+
+func (c *impersonatingClient) clientFor(ctx context.Context) (client.Client, error) {
+	u, ok := request.UserFrom(ctx)
+	if !ok {
+		return nil, errNoUser
+	}
+	key := userKey(u)
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if cli, ok := c.clients[key]; ok {
+		return cli, nil
+	}
+	cli, err := client.New(impersonatingConfig(c.config, u), client.Options{Scheme: c.Scheme(), Mapper: c.RESTMapper()})
+	if err != nil {
+		return nil, err
+	}
+	if len(c.clients) >= maxImpersonatingClients {
+		c.clients = map[string]client.Client{}
+	}
+	c.clients[key] = cli
+	return cli, nil
+}
+
+func (c *impersonatingClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	cli, err := c.clientFor(ctx)
+	if err != nil {
+		return err
+	}
+	return cli.Get(ctx, key, obj, opts...)
+}
+
+func (c *impersonatingClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	cli, err := c.clientFor(ctx)
+	if err != nil {
+		return err
+	}
+	return cli.List(ctx, list, opts...)
+}
+
+func (c *impersonatingClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	cli, err := c.clientFor(ctx)
+	if err != nil {
+		return err
+	}
+	return cli.Create(ctx, obj, opts...)
+}
+
+func (c *impersonatingClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	cli, err := c.clientFor(ctx)
+	if err != nil {
+		return err
+	}
+	return cli.Delete(ctx, obj, opts...)
+}
+
+func (c *impersonatingClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	cli, err := c.clientFor(ctx)
+	if err != nil {
+		return err
+	}
+	return cli.Update(ctx, obj, opts...)
+}
+
+func (c *impersonatingClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	cli, err := c.clientFor(ctx)
+	if err != nil {
+		return err
+	}
+	return cli.Patch(ctx, obj, patch, opts...)
+}
+
+func (c *impersonatingClient) DeleteAllOf(ctx context.Context, obj client.Object, opts ...client.DeleteAllOfOption) error {
+	cli, err := c.clientFor(ctx)
+	if err != nil {
+		return err
+	}
+	return cli.DeleteAllOf(ctx, obj, opts...)
+}
+
+func (c *impersonatingClient) Status() client.SubResourceWriter {
+	return c.SubResource("status")
+}
+
+func (c *impersonatingClient) SubResource(subResource string) client.SubResourceClient {
+	return &impersonatingSubResourceClient{client: c, subResource: subResource}
+}
+
+// impersonatingSubResourceClient delegates every call to the subresource client impersonating the user
+type impersonatingSubResourceClient struct {
+	client      *impersonatingClient
+	subResource string
+}
+
+func (c *impersonatingSubResourceClient) Get(ctx context.Context, obj client.Object, subResource client.Object, opts ...client.SubResourceGetOption) error {
+	cli, err := c.client.clientFor(ctx)
+	if err != nil {
+		return err
+	}
+	return cli.SubResource(c.subResource).Get(ctx, obj, subResource, opts...)
+}
+
+func (c *impersonatingSubResourceClient) Create(ctx context.Context, obj client.Object, subResource client.Object, opts ...client.SubResourceCreateOption) error {
+	cli, err := c.client.clientFor(ctx)
+	if err != nil {
+		return err
+	}
+	return cli.SubResource(c.subResource).Create(ctx, obj, subResource, opts...)
+}
+
+func (c *impersonatingSubResourceClient) Update(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+	cli, err := c.client.clientFor(ctx)
+	if err != nil {
+		return err
+	}
+	return cli.SubResource(c.subResource).Update(ctx, obj, opts...)
+}
+
+func (c *impersonatingSubResourceClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+	cli, err := c.client.clientFor(ctx)
+	if err != nil {
+		return err
+	}
+	return cli.SubResource(c.subResource).Patch(ctx, obj, patch, opts...)
+}
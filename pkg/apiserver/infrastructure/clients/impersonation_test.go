@@ -0,0 +1,92 @@
+/*
+Copyright 2024 KDP(Kubernetes Data Platform).
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestImpersonatingClient(t *testing.T) {
+	var lock sync.Mutex
+	var impersonated []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		impersonated = append(impersonated, r.Header.Get("Impersonate-User"))
+		lock.Unlock()
+		cm := corev1.ConfigMap{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+			ObjectMeta: metav1.ObjectMeta{Name: "cm", Namespace: "default"},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(cm)
+	}))
+	defer server.Close()
+
+	conf := &rest.Config{Host: server.URL}
+	s := k8sruntime.NewScheme()
+	if err := scheme.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("ConfigMap"), meta.RESTScopeNamespace)
+	base, err := client.New(conf, client.Options{Scheme: s, Mapper: mapper})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := newImpersonatingClient(conf, base)
+
+	key := client.ObjectKey{Namespace: "default", Name: "cm"}
+	if err := c.Get(context.Background(), key, &corev1.ConfigMap{}); err != errNoUser {
+		t.Fatalf("Get() without user = %v, want %v", err, errNoUser)
+	}
+	for _, name := range []string{"alice", "bob", "alice"} {
+		ctx := request.WithUser(context.Background(), &user.DefaultInfo{Name: name, Groups: []string{"dev"}})
+		if err := c.Get(ctx, key, &corev1.ConfigMap{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+	want := []string{"alice", "bob", "alice"}
+	if len(impersonated) != len(want) {
+		t.Fatalf("impersonated = %v, want %v", impersonated, want)
+	}
+	for i := range want {
+		if impersonated[i] != want[i] {
+			t.Errorf("impersonated = %v, want %v", impersonated, want)
+		}
+	}
+	if len(c.clients) != 2 {
+		t.Errorf("cached clients = %d, want 2", len(c.clients))
+	}
+}
@@ -57,7 +57,8 @@ func SetKubeConfig(c apiserverCfg.APIServerConfig) error {
 	return setKubeConfig(kubeConfig)
 }
 
-// GetKubeClient create and return kube runtime client
+// GetKubeClient create and return kube runtime client, which impersonates the user of the request context
+// if the impersonation is enabled
 func GetKubeClient() (client.Client, error) {
 	if kubeClient != nil {
 		return kubeClient, nil
@@ -71,6 +72,9 @@ func GetKubeClient() (client.Client, error) {
 	if err != nil {
 		return nil, err
 	}
+	if impersonation {
+		kubeClient = newImpersonatingClient(conf, kubeClient)
+	}
 	return kubeClient, nil
 }
 
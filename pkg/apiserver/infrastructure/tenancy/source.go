@@ -0,0 +1,106 @@
+/*
+Copyright 2024 KDP(Kubernetes Data Platform).
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tenancy
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/authentication/user"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// MembershipKey is the key of the membership ConfigMap data
+const MembershipKey = "membership.yaml"
+
+// NewGroupSource returns the Source resolving the membership from the groups of the user, the group orgPrefix+<org>
+// makes the user a member of the org and bdcPrefix+<bdc> of the BigDataCluster
+func NewGroupSource(orgPrefix, bdcPrefix string) Source {
+	return &groupSource{orgPrefix: orgPrefix, bdcPrefix: bdcPrefix}
+}
+
+type groupSource struct {
+	orgPrefix string
+	bdcPrefix string
+}
+
+// MembershipOf returns the orgs and BigDataClusters named by the groups of the user
+func (g *groupSource) MembershipOf(_ context.Context, u user.Info) (*Membership, error) {
+	m := NewMembership()
+	for _, group := range u.GetGroups() {
+		if g.orgPrefix != "" && strings.HasPrefix(group, g.orgPrefix) {
+			m.Orgs.Insert(strings.TrimPrefix(group, g.orgPrefix))
+		}
+		if g.bdcPrefix != "" && strings.HasPrefix(group, g.bdcPrefix) {
+			m.BigDataClusters.Insert(strings.TrimPrefix(group, g.bdcPrefix))
+		}
+	}
+	return m, nil
+}
+
+// membershipConfig is the data of the membership ConfigMap, the membership of a user is the union of the entries
+// of the user and of the groups of the user
+type membershipConfig struct {
+	Users  map[string]membershipEntry `json:"users,omitempty"`
+	Groups map[string]membershipEntry `json:"groups,omitempty"`
+}
+
+type membershipEntry struct {
+	Orgs            []string `json:"orgs,omitempty"`
+	BigDataClusters []string `json:"bigDataClusters,omitempty"`
+}
+
+// NewConfigMapSource returns the Source resolving the membership from the ConfigMap namespace/name
+func NewConfigMapSource(configMaps corev1client.ConfigMapsGetter, namespace, name string) Source {
+	return &configMapSource{configMaps: configMaps, namespace: namespace, name: name}
+}
+
+type configMapSource struct {
+	configMaps corev1client.ConfigMapsGetter
+	namespace  string
+	name       string
+}
+
+// MembershipOf returns the membership of the user in the ConfigMap, the user belongs to nothing if the ConfigMap
+// doesn't exist
+func (c *configMapSource) MembershipOf(ctx context.Context, u user.Info) (*Membership, error) {
+	m := NewMembership()
+	configMap, err := c.configMaps.ConfigMaps(c.namespace).Get(ctx, c.name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the membership configmap %s/%s", c.namespace, c.name)
+	}
+	var config membershipConfig
+	if err := yaml.Unmarshal([]byte(configMap.Data[MembershipKey]), &config); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the membership configmap %s/%s", c.namespace, c.name)
+	}
+	entries := []membershipEntry{config.Users[u.GetName()]}
+	for _, group := range u.GetGroups() {
+		entries = append(entries, config.Groups[group])
+	}
+	for _, entry := range entries {
+		m.Orgs.Insert(entry.Orgs...)
+		m.BigDataClusters.Insert(entry.BigDataClusters...)
+	}
+	return m, nil
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2024 KDP(Kubernetes Data Platform).
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tenancy resolves the orgs and BigDataClusters the callers of the apiserver belong to, which scope
+// the objects visible to them
+package tenancy
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apiserver/pkg/authentication/user"
+)
+
+// Membership sources of the apiserver
+const (
+	// SourceGroups resolves the membership from the group claims of the user
+	SourceGroups = "Groups"
+	// SourceConfigMap resolves the membership from the membership ConfigMap
+	SourceConfigMap = "ConfigMap"
+)
+
+// Membership is the orgs and BigDataClusters a user belongs to
+type Membership struct {
+	Orgs            sets.String
+	BigDataClusters sets.String
+}
+
+// NewMembership returns the empty membership
+func NewMembership() *Membership {
+	return &Membership{Orgs: sets.NewString(), BigDataClusters: sets.NewString()}
+}
+
+// Allows returns whether the object of the org and the BigDataCluster is visible to the member, the object is
+// visible if the member belongs to either of them. A nil Membership is unrestricted.
+func (m *Membership) Allows(orgName, bdcName string) bool {
+	if m == nil {
+		return true
+	}
+	return (orgName != "" && m.Orgs.Has(orgName)) || (bdcName != "" && m.BigDataClusters.Has(bdcName))
+}
+
+// Source resolves the membership of the users
+type Source interface {
+	// MembershipOf returns the membership of the user, nil if the user is unrestricted
+	MembershipOf(ctx context.Context, u user.Info) (*Membership, error)
+}
+
+// WithAdminGroups returns the Source making the users of the admin groups unrestricted
+func WithAdminGroups(source Source, adminGroups []string) Source {
+	return &adminSource{Source: source, adminGroups: sets.NewString(adminGroups...)}
+}
+
+type adminSource struct {
+	Source
+	adminGroups sets.String
+}
+
+// MembershipOf returns nil for the users of the admin groups, otherwise the membership of the Source
+func (a *adminSource) MembershipOf(ctx context.Context, u user.Info) (*Membership, error) {
+	if a.adminGroups.HasAny(u.GetGroups()...) {
+		return nil, nil
+	}
+	return a.Source.MembershipOf(ctx, u)
+}
+
+type membershipKey struct{}
+
+// WithMembership returns the copy of ctx carrying the membership of the caller
+func WithMembership(ctx context.Context, m *Membership) context.Context {
+	return context.WithValue(ctx, membershipKey{}, m)
+}
+
+// MembershipFrom returns the membership of the caller of ctx, nil if the caller is unrestricted
+func MembershipFrom(ctx context.Context) *Membership {
+	m, _ := ctx.Value(membershipKey{}).(*Membership)
+	return m
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2024 KDP(Kubernetes Data Platform).
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tenancy
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestMembershipAllows(t *testing.T) {
+	m := NewMembership()
+	m.Orgs.Insert("org-a")
+	m.BigDataClusters.Insert("bdc-b")
+	tests := []struct {
+		org     string
+		bdc     string
+		allowed bool
+	}{
+		{org: "org-a", bdc: "bdc-x", allowed: true},
+		{org: "org-x", bdc: "bdc-b", allowed: true},
+		{org: "org-x", bdc: "bdc-x", allowed: false},
+		{org: "", bdc: "", allowed: false},
+	}
+	for _, tt := range tests {
+		if got := m.Allows(tt.org, tt.bdc); got != tt.allowed {
+			t.Errorf("Allows(%q, %q) = %v, want %v", tt.org, tt.bdc, got, tt.allowed)
+		}
+	}
+	var unrestricted *Membership
+	if !unrestricted.Allows("", "") {
+		t.Error("nil membership should be unrestricted")
+	}
+}
+
+func TestGroupSource(t *testing.T) {
+	source := WithAdminGroups(NewGroupSource("kdp:org:", "kdp:bdc:"), []string{"system:masters"})
+
+	m, err := source.MembershipOf(context.Background(), &user.DefaultInfo{Name: "alice", Groups: []string{"kdp:org:org-a", "kdp:bdc:bdc-b", "dev"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !m.Orgs.Equal(NewMembership().Orgs.Insert("org-a")) || !m.BigDataClusters.Equal(NewMembership().BigDataClusters.Insert("bdc-b")) {
+		t.Errorf("membership = %v, %v", m.Orgs.List(), m.BigDataClusters.List())
+	}
+
+	m, err = source.MembershipOf(context.Background(), &user.DefaultInfo{Name: "admin", Groups: []string{"system:masters"}})
+	if err != nil || m != nil {
+		t.Errorf("membership of the admin = %v, %v, want nil", m, err)
+	}
+}
+
+func TestConfigMapSource(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset()
+	source := NewConfigMapSource(kubeClient.CoreV1(), "kdp-system", "membership")
+	alice := &user.DefaultInfo{Name: "alice", Groups: []string{"dev"}}
+
+	m, err := source.MembershipOf(context.Background(), alice)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Orgs.Len() != 0 || m.BigDataClusters.Len() != 0 {
+		t.Errorf("membership without the configmap = %v, %v, want empty", m.Orgs.List(), m.BigDataClusters.List())
+	}
+
+	_, err = kubeClient.CoreV1().ConfigMaps("kdp-system").Create(context.Background(), &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "membership", Namespace: "kdp-system"},
+		Data: map[string]string{MembershipKey: `
+users:
+  alice:
+    orgs: [org-a]
+groups:
+  dev:
+    bigDataClusters: [bdc-b]
+  ops:
+    orgs: [org-ops]
+`},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err = source.MembershipOf(context.Background(), alice)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := m.Orgs.List(); len(got) != 1 || got[0] != "org-a" {
+		t.Errorf("orgs = %v, want [org-a]", got)
+	}
+	if got := m.BigDataClusters.List(); len(got) != 1 || got[0] != "bdc-b" {
+		t.Errorf("bigDataClusters = %v, want [bdc-b]", got)
+	}
+}
@@ -0,0 +1,264 @@
+/*
+Copyright 2024 KDP(Kubernetes Data Platform).
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package watch
+
+import (
+	"sort"
+	"strconv"
+	"sync"
+
+	apiwatch "k8s.io/apimachinery/pkg/watch"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// change is a change of an object received by the informer, old is nil for the additions and new is nil for the
+// deletions
+type change struct {
+	resourceVersion uint64
+	old             client.Object
+	new             client.Object
+}
+
+func (c change) object() client.Object {
+	if c.new != nil {
+		return c.new
+	}
+	return c.old
+}
+
+// Broadcaster buffers the recent changes of a type and sends them to the watchers
+type Broadcaster struct {
+	lock     sync.Mutex
+	indexer  toolscache.Indexer
+	capacity int
+	// since is the resource version after which all the changes are buffered
+	since    uint64
+	changes  []change
+	watchers map[*Watcher]struct{}
+}
+
+func newBroadcaster(indexer toolscache.Indexer, capacity int) *Broadcaster {
+	return &Broadcaster{indexer: indexer, capacity: capacity, watchers: map[*Watcher]struct{}{}}
+}
+
+func (b *Broadcaster) setSince(since uint64) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if since > b.since {
+		b.since = since
+	}
+}
+
+// OnAdd records the addition of the object
+func (b *Broadcaster) OnAdd(obj interface{}) {
+	if o, ok := obj.(client.Object); ok {
+		b.record(change{resourceVersion: parseResourceVersion(o.GetResourceVersion()), new: o})
+	}
+}
+
+// OnUpdate records the update of the object
+func (b *Broadcaster) OnUpdate(oldObj, newObj interface{}) {
+	o, ok := oldObj.(client.Object)
+	n, ok2 := newObj.(client.Object)
+	if ok && ok2 {
+		b.record(change{resourceVersion: parseResourceVersion(n.GetResourceVersion()), old: o, new: n})
+	}
+}
+
+// OnDelete records the deletion of the object
+func (b *Broadcaster) OnDelete(obj interface{}) {
+	if tombstone, ok := obj.(toolscache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	if o, ok := obj.(client.Object); ok {
+		b.record(change{resourceVersion: parseResourceVersion(o.GetResourceVersion()), old: o})
+	}
+}
+
+func (b *Broadcaster) record(c change) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.changes = append(b.changes, c)
+	if len(b.changes) > b.capacity {
+		if evicted := b.changes[0].resourceVersion; evicted > b.since {
+			b.since = evicted
+		}
+		b.changes = append(b.changes[:0], b.changes[1:]...)
+	}
+	for w := range b.watchers {
+		if !w.send(c) {
+			delete(b.watchers, w)
+		}
+	}
+}
+
+// Watch returns the watcher of the objects passing the filter from the resource version, all the objects are
+// added first if the resource version is empty
+func (b *Broadcaster) Watch(resourceVersion string, filter Filter) (*Watcher, error) {
+	w := &Watcher{
+		filter:      filter,
+		capacity:    b.capacity,
+		seen:        map[client.ObjectKey]uint64{},
+		ready:       make(chan struct{}, 1),
+		broadcaster: b,
+	}
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if resourceVersion == "" {
+		var objects []client.Object
+		for _, obj := range b.indexer.List() {
+			if o, ok := obj.(client.Object); ok {
+				objects = append(objects, o)
+			}
+		}
+		// the objects are added in the order of their resource versions, so that the watch resumed from the resource
+		// version of any added object receives the rest of them as the buffered changes
+		sort.Slice(objects, func(i, j int) bool {
+			ri, rj := parseResourceVersion(objects[i].GetResourceVersion()), parseResourceVersion(objects[j].GetResourceVersion())
+			if ri != rj {
+				return ri < rj
+			}
+			return client.ObjectKeyFromObject(objects[i]).String() < client.ObjectKeyFromObject(objects[j]).String()
+		})
+		for _, o := range objects {
+			converted, ok := filter(o)
+			if !ok {
+				continue
+			}
+			// the informer may be ahead of the handler, the changes before the added objects are skipped
+			w.seen[client.ObjectKeyFromObject(o)] = parseResourceVersion(o.GetResourceVersion())
+			w.queue = append(w.queue, Event{Type: apiwatch.Added, Object: converted, ResourceVersion: o.GetResourceVersion()})
+		}
+	} else {
+		rv, err := strconv.ParseUint(resourceVersion, 10, 64)
+		if err != nil {
+			return nil, ErrInvalidResourceVersion
+		}
+		if rv < b.since {
+			return nil, ErrExpired
+		}
+		for _, c := range b.changes {
+			if c.resourceVersion > rv {
+				w.send(c)
+			}
+		}
+	}
+	w.notify()
+	b.watchers[w] = struct{}{}
+	return w, nil
+}
+
+func (b *Broadcaster) remove(w *Watcher) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	delete(b.watchers, w)
+}
+
+// Watcher receives the events of the objects passing its filter
+type Watcher struct {
+	filter   Filter
+	capacity int
+	// seen is the resource versions of the objects added by the watch
+	seen        map[client.ObjectKey]uint64
+	broadcaster *Broadcaster
+
+	lock    sync.Mutex
+	queue   []Event
+	stopped bool
+	ready   chan struct{}
+}
+
+// send queues the event of the change, and returns false if the watcher is stopped since too many events are
+// pending
+func (w *Watcher) send(c change) bool {
+	event, ok := w.event(c)
+	if !ok {
+		return true
+	}
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if w.stopped {
+		return false
+	}
+	if len(w.queue) >= w.capacity {
+		w.stopped = true
+	} else {
+		w.queue = append(w.queue, event)
+	}
+	w.notify()
+	return !w.stopped
+}
+
+// event returns the event of the change for the watcher, the object moving into the watch is added, and the one
+// moving out of it is deleted
+func (w *Watcher) event(c change) (Event, bool) {
+	key := client.ObjectKeyFromObject(c.object())
+	if rv, ok := w.seen[key]; ok {
+		if c.new != nil && c.resourceVersion <= rv {
+			return Event{}, false
+		}
+		delete(w.seen, key)
+	}
+	var oldObject, newObject interface{}
+	var oldMatched, newMatched bool
+	if c.old != nil {
+		oldObject, oldMatched = w.filter(c.old)
+	}
+	if c.new != nil {
+		newObject, newMatched = w.filter(c.new)
+	}
+	resourceVersion := c.object().GetResourceVersion()
+	switch {
+	case oldMatched && newMatched:
+		return Event{Type: apiwatch.Modified, Object: newObject, ResourceVersion: resourceVersion}, true
+	case newMatched:
+		return Event{Type: apiwatch.Added, Object: newObject, ResourceVersion: resourceVersion}, true
+	case oldMatched:
+		return Event{Type: apiwatch.Deleted, Object: oldObject, ResourceVersion: resourceVersion}, true
+	}
+	return Event{}, false
+}
+
+func (w *Watcher) notify() {
+	select {
+	case w.ready <- struct{}{}:
+	default:
+	}
+}
+
+// Ready is notified once there are events pending or the watcher is stopped
+func (w *Watcher) Ready() <-chan struct{} {
+	return w.ready
+}
+
+// Pop returns the pending events, and false if the watcher is stopped
+func (w *Watcher) Pop() ([]Event, bool) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	events := w.queue
+	w.queue = nil
+	return events, !w.stopped
+}
+
+// Stop stops receiving the events
+func (w *Watcher) Stop() {
+	w.broadcaster.remove(w)
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.stopped = true
+}
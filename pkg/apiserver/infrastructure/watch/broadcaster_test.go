@@ -0,0 +1,156 @@
+/*
+Copyright 2024 KDP(Kubernetes Data Platform).
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package watch
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apiwatch "k8s.io/apimachinery/pkg/watch"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func configMap(name, resourceVersion, phase string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, ResourceVersion: resourceVersion},
+		Data:       map[string]string{"phase": phase},
+	}
+}
+
+// runningOnly passes the config maps in the running phase, and converts them to their names
+func runningOnly(obj client.Object) (interface{}, bool) {
+	cm := obj.(*corev1.ConfigMap)
+	return cm.Name, cm.Data["phase"] == "running"
+}
+
+type event struct {
+	Type            apiwatch.EventType
+	Name            string
+	ResourceVersion string
+}
+
+func pop(t *testing.T, w *Watcher) ([]event, bool) {
+	t.Helper()
+	events, ok := w.Pop()
+	var result []event
+	for _, e := range events {
+		result = append(result, event{Type: e.Type, Name: e.Object.(string), ResourceVersion: e.ResourceVersion})
+	}
+	return result, ok
+}
+
+func TestBroadcasterWatch(t *testing.T) {
+	indexer := toolscache.NewIndexer(toolscache.MetaNamespaceKeyFunc, toolscache.Indexers{})
+	b := newBroadcaster(indexer, 10)
+	b.setSince(10)
+	for _, cm := range []*corev1.ConfigMap{configMap("b", "5", "running"), configMap("a", "7", "running"), configMap("c", "3", "pending")} {
+		if err := indexer.Add(cm); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// the informer has updated b before the handler receives it
+	updated := configMap("b", "11", "running")
+	if err := indexer.Update(updated); err != nil {
+		t.Fatal(err)
+	}
+
+	w, err := b.Watch("", runningOnly)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Stop()
+	<-w.Ready()
+	events, ok := pop(t, w)
+	want := []event{{apiwatch.Added, "a", "7"}, {apiwatch.Added, "b", "11"}}
+	if !ok || !reflect.DeepEqual(events, want) {
+		t.Fatalf("expected the snapshot %v, got %v %v", want, events, ok)
+	}
+
+	b.OnUpdate(configMap("b", "5", "running"), updated)
+	b.OnUpdate(configMap("c", "3", "pending"), configMap("c", "12", "running"))
+	b.OnUpdate(configMap("a", "7", "running"), configMap("a", "13", "running"))
+	b.OnUpdate(configMap("a", "13", "running"), configMap("a", "14", "failed"))
+	// the final state of the deleted object has the resource version of the deletion
+	b.OnDelete(toolscache.DeletedFinalStateUnknown{Key: "default/b", Obj: configMap("b", "15", "running")})
+	<-w.Ready()
+	events, ok = pop(t, w)
+	want = []event{
+		{apiwatch.Added, "c", "12"},
+		{apiwatch.Modified, "a", "13"},
+		{apiwatch.Deleted, "a", "14"},
+		{apiwatch.Deleted, "b", "15"},
+	}
+	if !ok || !reflect.DeepEqual(events, want) {
+		t.Fatalf("expected the changes %v, got %v %v", want, events, ok)
+	}
+
+	resumed, err := b.Watch("12", runningOnly)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resumed.Stop()
+	events, _ = pop(t, resumed)
+	want = []event{{apiwatch.Modified, "a", "13"}, {apiwatch.Deleted, "a", "14"}, {apiwatch.Deleted, "b", "15"}}
+	if !reflect.DeepEqual(events, want) {
+		t.Fatalf("expected the resumed changes %v, got %v", want, events)
+	}
+
+	if _, err := b.Watch("9", runningOnly); !errors.Is(err, ErrExpired) {
+		t.Fatalf("expected ErrExpired, got %v", err)
+	}
+	if _, err := b.Watch("abc", runningOnly); !errors.Is(err, ErrInvalidResourceVersion) {
+		t.Fatalf("expected ErrInvalidResourceVersion, got %v", err)
+	}
+}
+
+func TestBroadcasterEviction(t *testing.T) {
+	b := newBroadcaster(toolscache.NewIndexer(toolscache.MetaNamespaceKeyFunc, toolscache.Indexers{}), 2)
+	w, err := b.Watch("0", runningOnly)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.OnAdd(configMap("a", "1", "running"))
+	b.OnAdd(configMap("b", "2", "running"))
+	b.OnAdd(configMap("c", "3", "running"))
+
+	// the watcher falling behind is stopped
+	events, ok := pop(t, w)
+	if ok || len(events) != 2 {
+		t.Fatalf("expected the watcher stopped with 2 events, got %v %v", events, ok)
+	}
+	if len(b.watchers) != 0 {
+		t.Fatalf("expected the stopped watcher removed, got %d watchers", len(b.watchers))
+	}
+
+	// the evicted change can't be resumed from
+	if _, err := b.Watch("0", runningOnly); !errors.Is(err, ErrExpired) {
+		t.Fatalf("expected ErrExpired, got %v", err)
+	}
+	resumed, err := b.Watch("1", runningOnly)
+	if err != nil {
+		t.Fatal(err)
+	}
+	events, _ = pop(t, resumed)
+	want := []event{{apiwatch.Added, "b", "2"}, {apiwatch.Added, "c", "3"}}
+	if !reflect.DeepEqual(events, want) {
+		t.Fatalf("expected the resumed changes %v, got %v", want, events)
+	}
+}
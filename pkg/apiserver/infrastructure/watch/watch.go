@@ -0,0 +1,121 @@
+/*
+Copyright 2024 KDP(Kubernetes Data Platform).
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package watch broadcasts the changes of the objects received by the informers to the watchers of the apiserver,
+// the recent changes are buffered so that the watchers resume from the resource version they have seen
+package watch
+
+import (
+	"context"
+	"reflect"
+	"strconv"
+	"sync"
+
+	"github.com/pkg/errors"
+	apiwatch "k8s.io/apimachinery/pkg/watch"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ErrExpired is returned if the resource version to resume from is older than the buffered changes
+var ErrExpired = errors.New("the resource version is too old to resume the watch")
+
+// ErrInvalidResourceVersion is returned if the resource version isn't issued by the kube-apiserver
+var ErrInvalidResourceVersion = errors.New("invalid resource version")
+
+// Event is a change of an object visible to the watcher
+type Event struct {
+	Type apiwatch.EventType
+	// Object is the object converted by the Filter of the watcher
+	Object          interface{}
+	ResourceVersion string
+}
+
+// Filter converts the object for the watcher, and returns false if the object is out of the watch
+type Filter func(obj client.Object) (interface{}, bool)
+
+// sharedInformer is the informer of the controller-runtime cache
+type sharedInformer interface {
+	GetIndexer() toolscache.Indexer
+	LastSyncResourceVersion() string
+}
+
+// Hub creates the broadcaster of a type on its first watch
+type Hub struct {
+	informers    cache.Informers
+	capacity     int
+	lock         sync.Mutex
+	broadcasters map[reflect.Type]*Broadcaster
+}
+
+// NewHub returns the Hub of the informers, each broadcaster buffers capacity changes at most, which is also the max
+// number of the events pending for a watcher
+func NewHub(informers cache.Informers, capacity int) *Hub {
+	return &Hub{informers: informers, capacity: capacity, broadcasters: map[reflect.Type]*Broadcaster{}}
+}
+
+// Watch watches the objects of the type of obj from the resource version, all the objects are added first if the
+// resource version is empty
+func (h *Hub) Watch(ctx context.Context, obj client.Object, resourceVersion string, filter Filter) (*Watcher, error) {
+	b, err := h.broadcaster(ctx, obj)
+	if err != nil {
+		return nil, err
+	}
+	return b.Watch(resourceVersion, filter)
+}
+
+func (h *Hub) broadcaster(ctx context.Context, obj client.Object) (*Broadcaster, error) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	t := reflect.TypeOf(obj)
+	if b, ok := h.broadcasters[t]; ok {
+		return b, nil
+	}
+	informer, err := h.informers.GetInformer(ctx, obj)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the informer of %T", obj)
+	}
+	shared, ok := informer.(sharedInformer)
+	if !ok {
+		return nil, errors.Errorf("the informer of %T doesn't support the watch", obj)
+	}
+	b := newBroadcaster(shared.GetIndexer(), h.capacity)
+	if _, err := informer.AddEventHandler(b); err != nil {
+		return nil, errors.Wrapf(err, "failed to add the event handler of %T", obj)
+	}
+	// the changes after the resource version read after the handler is added are all received by the handler
+	b.setSince(parseResourceVersion(shared.LastSyncResourceVersion()))
+	h.broadcasters[t] = b
+	return b, nil
+}
+
+func parseResourceVersion(resourceVersion string) uint64 {
+	rv, _ := strconv.ParseUint(resourceVersion, 10, 64)
+	return rv
+}
+
+var hub *Hub
+
+// SetHub sets the Hub of the apiserver
+func SetHub(h *Hub) {
+	hub = h
+}
+
+// GetHub returns the Hub of the apiserver, nil if the watch isn't set up
+func GetHub() *Hub {
+	return hub
+}
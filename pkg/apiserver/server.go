@@ -17,10 +17,16 @@ limitations under the License.
 package apiserver
 
 import (
+	"fmt"
 	"kdp-oam-operator/pkg/apiserver/apis/v1/webservice"
 	"kdp-oam-operator/pkg/apiserver/config"
+	"kdp-oam-operator/pkg/apiserver/infrastructure/audit"
+	"kdp-oam-operator/pkg/apiserver/infrastructure/auth"
 	"kdp-oam-operator/pkg/apiserver/infrastructure/clients"
+	"kdp-oam-operator/pkg/apiserver/infrastructure/tenancy"
+	"kdp-oam-operator/pkg/apiserver/infrastructure/watch"
 	"kdp-oam-operator/pkg/apiserver/utils"
+	pkgutils "kdp-oam-operator/pkg/utils"
 	"kdp-oam-operator/pkg/utils/log"
 	"os"
 	"path/filepath"
@@ -32,6 +38,11 @@ import (
 	restfulspec "github.com/emicklei/go-restful-openapi/v2"
 	"github.com/emicklei/go-restful/v3"
 	"github.com/go-openapi/spec"
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	"k8s.io/client-go/kubernetes"
+	authenticationv1client "k8s.io/client-go/kubernetes/typed/authentication/v1"
+	"k8s.io/client-go/tools/cache"
+	informercache "sigs.k8s.io/controller-runtime/pkg/cache"
 )
 
 var _ APIServer = &RestServer{}
@@ -43,8 +54,14 @@ type APIServer interface {
 }
 
 type RestServer struct {
-	webContainer *restful.Container
-	cfg          config.APIServerConfig
+	webContainer  *restful.Container
+	cfg           config.APIServerConfig
+	authenticator authenticator.Token
+	authorizer    auth.Authorizer
+	// localOnly makes the authorizer check the Local routes only, the other routes are authorized by the
+	// kube-apiserver for the impersonated caller
+	localOnly  bool
+	membership tenancy.Source
 }
 
 // New create restServer with config data
@@ -62,6 +79,13 @@ func (s *RestServer) Run(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	if err := s.setupAuth(); err != nil {
+		return err
+	}
+	s.setupAudit()
+	if err := s.setupWatch(ctx); err != nil {
+		return err
+	}
 	s.BuildRestfulConfig()
 	return s.startHTTP(ctx)
 }
@@ -75,7 +99,7 @@ func (s *RestServer) BuildRestfulConfig() restfulspec.Config {
 	// Add container filter to enable CORS
 	cors := restful.CrossOriginResourceSharing{
 		ExposeHeaders:  []string{},
-		AllowedHeaders: []string{"Content-Type", "Accept"},
+		AllowedHeaders: []string{"Content-Type", "Accept", "Authorization"},
 		AllowedMethods: []string{"GET", "POST", "PUT", "DELETE", "OPTIONS", "PATCH"},
 		CookiesAllowed: true,
 		Container:      s.webContainer}
@@ -87,6 +111,14 @@ func (s *RestServer) BuildRestfulConfig() restfulspec.Config {
 	// Add request log
 	s.webContainer.Filter(s.requestLog)
 
+	// Record the mutating requests, including the denied ones
+	s.webContainer.Filter(s.recordAudit)
+
+	// Authenticate and authorize the callers
+	s.webContainer.Filter(s.authenticate)
+	s.webContainer.Filter(s.authorize)
+	s.webContainer.Filter(s.scope)
+
 	// Register all custom webservice
 	for _, handler := range webservice.GetRegisteredWebService() {
 		s.webContainer.Add(handler.GetWebService())
@@ -148,13 +180,108 @@ func (s *RestServer) requestLog(req *restful.Request, resp *restful.Response, ch
 		"time", takeTime.String(),
 		"responseSize", len(c.Bytes()),
 		"proto", req.Request.Proto,
-		"headers", req.Request.Header,
+		"headers", redactedHeader(req.Request.Header),
 	).Infof("request log")
 	if s.cfg.GenericOptions.LogLevel == "debug" {
 		log.Logger.With("responseData", c.Bytes()).Debugf("request log")
 	}
 }
 
+// setupAuth creates the authenticator and the authorizer of the requests according to the config
+func (s *RestServer) setupAuth() error {
+	conf, err := clients.GetKubeConfig()
+	if err != nil {
+		return err
+	}
+	kubeClient, err := kubernetes.NewForConfig(conf)
+	if err != nil {
+		return err
+	}
+	var tokenReview authenticationv1client.TokenReviewInterface
+	if s.cfg.Authentication.TokenReview {
+		tokenReview = kubeClient.AuthenticationV1().TokenReviews()
+	}
+	s.authenticator, err = auth.NewTokenAuthenticator(s.cfg.Authentication.TokenAuthFile, tokenReview)
+	if err != nil {
+		return err
+	}
+	if err := s.setupTenancy(kubeClient); err != nil {
+		return err
+	}
+
+	switch s.cfg.AuthorizationMode {
+	case "", auth.AuthorizationModeAlwaysAllow:
+		return nil
+	case auth.AuthorizationModeSubjectAccessReview:
+		s.authorizer = auth.NewSubjectAccessReviewAuthorizer(kubeClient.AuthorizationV1().SubjectAccessReviews())
+	case auth.AuthorizationModeImpersonation:
+		clients.EnableImpersonation()
+		s.authorizer = auth.NewSubjectAccessReviewAuthorizer(kubeClient.AuthorizationV1().SubjectAccessReviews())
+		s.localOnly = true
+	default:
+		return fmt.Errorf("unknown authorization mode %s", s.cfg.AuthorizationMode)
+	}
+	if s.authenticator == nil {
+		return fmt.Errorf("authorization mode %s requires the token file or the token review to authenticate the callers", s.cfg.AuthorizationMode)
+	}
+	return nil
+}
+
+// setupTenancy creates the membership source of the callers according to the config
+func (s *RestServer) setupTenancy(kubeClient kubernetes.Interface) error {
+	cfg := s.cfg.Tenancy
+	switch cfg.MembershipSource {
+	case "":
+		return nil
+	case tenancy.SourceGroups:
+		s.membership = tenancy.NewGroupSource(cfg.OrgGroupPrefix, cfg.BDCGroupPrefix)
+	case tenancy.SourceConfigMap:
+		namespace, name, err := cache.SplitMetaNamespaceKey(cfg.MembershipConfigMap)
+		if err != nil {
+			return err
+		}
+		if namespace == "" {
+			namespace = pkgutils.GetEnv("NAMESPACE", "default")
+		}
+		s.membership = tenancy.NewConfigMapSource(kubeClient.CoreV1(), namespace, name)
+	default:
+		return fmt.Errorf("unknown tenancy membership source %s", cfg.MembershipSource)
+	}
+	if s.authenticator == nil {
+		return fmt.Errorf("tenancy requires the token file or the token review to authenticate the callers")
+	}
+	s.membership = tenancy.WithAdminGroups(s.membership, cfg.AdminGroups)
+	return nil
+}
+
+// setupAudit creates the audit logger if the audit log path is set
+func (s *RestServer) setupAudit() {
+	cfg := s.cfg.Audit
+	if cfg.LogPath == "" {
+		return
+	}
+	audit.SetLogger(audit.NewLogger(cfg.LogPath, cfg.MaxSize, cfg.MaxBackups, cfg.MaxAge))
+}
+
+// setupWatch starts the informer cache of the watch endpoints, the informer of a type is started on its first watch
+func (s *RestServer) setupWatch(ctx context.Context) error {
+	conf, err := clients.GetKubeConfig()
+	if err != nil {
+		return err
+	}
+	informers, err := informercache.New(conf, informercache.Options{Scheme: clients.Scheme})
+	if err != nil {
+		return err
+	}
+	go func() {
+		if err := informers.Start(ctx); err != nil {
+			log.Logger.Errorw("start the informers of the watch failure", "error", err)
+		}
+	}()
+	watch.SetHub(watch.NewHub(informers, s.cfg.WatchCacheSize))
+	return nil
+}
+
 func (s *RestServer) startHTTP(ctx context.Context) error {
 	// Start HTTP api server
 	log.Logger.Infof("HTTP APIs are being served on: %s, ctx: %s", s.cfg.BindAddr, ctx)
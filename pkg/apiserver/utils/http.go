@@ -56,6 +56,8 @@ type ResponseCapture struct {
 	wroteHeader bool
 	status      int
 	body        *bytes.Buffer
+	// streaming is set once the response is flushed, the body of the streaming responses isn't captured
+	streaming bool
 }
 
 // NewResponseCapture new response capture
@@ -77,10 +79,21 @@ func (c ResponseCapture) Write(data []byte) (int, error) {
 	if !c.wroteHeader {
 		c.WriteHeader(http.StatusOK)
 	}
-	c.body.Write(data)
+	if !c.streaming {
+		c.body.Write(data)
+	}
 	return c.ResponseWriter.Write(data)
 }
 
+// Flush sends the buffered data to the client
+func (c *ResponseCapture) Flush() {
+	c.streaming = true
+	c.body.Reset()
+	if f, ok := c.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
 // WriteHeader write header to response writer
 func (c *ResponseCapture) WriteHeader(statusCode int) {
 	c.status = statusCode
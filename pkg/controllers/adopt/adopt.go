@@ -0,0 +1,200 @@
+/*
+Copyright 2024 KDP(Kubernetes Data Platform).
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package adopt takes over the existing ConfigMaps and Secrets labeled by users as ContextSettings and ContextSecrets
+// of the BigDataCluster which has their namespaces
+package adopt
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	bdcv1alpha1 "kdp-oam-operator/api/bdc/v1alpha1"
+	bdcctrl "kdp-oam-operator/pkg/controllers/bdc"
+	"kdp-oam-operator/pkg/controllers/bdc/constants"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// IndexBigDataClusterNamespace is the field index of BigDataClusters by the names of all their namespaces
+const IndexBigDataClusterNamespace = "spec.namespaces.name"
+
+var (
+	errNoBigDataCluster        = errors.New("no BigDataCluster has the namespace")
+	errAmbiguousBigDataCluster = errors.New("more than one BigDataCluster have the namespace")
+)
+
+type options struct {
+	concurrentReconciles int
+}
+
+// Setup registers the BigDataCluster namespace index shared by the adopting controllers, and sets up them
+func Setup(mgr ctrl.Manager, args bdcctrl.Args) error {
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &bdcv1alpha1.BigDataCluster{},
+		IndexBigDataClusterNamespace, IndexBigDataClusterNamespaces); err != nil {
+		return err
+	}
+	configMapReconciler := ConfigMapReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("bdc-configmap-syncer"),
+		options:  parseOptions(args),
+	}
+	if err := configMapReconciler.SetupWithManager(mgr); err != nil {
+		return err
+	}
+	secretReconciler := SecretReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("bdc-secret-syncer"),
+		options:  parseOptions(args),
+	}
+	return secretReconciler.SetupWithManager(mgr)
+}
+
+func parseOptions(args bdcctrl.Args) options {
+	return options{
+		concurrentReconciles: args.ConcurrentReconciles,
+	}
+}
+
+// IndexBigDataClusterNamespaces returns the names of all the namespaces of the BigDataCluster
+func IndexBigDataClusterNamespaces(obj client.Object) []string {
+	bdc, ok := obj.(*bdcv1alpha1.BigDataCluster)
+	if !ok {
+		return nil
+	}
+	names := make([]string, 0, len(bdc.Spec.Namespaces))
+	for _, ns := range bdc.Spec.Namespaces {
+		names = append(names, ns.Name)
+	}
+	return names
+}
+
+// bigDataClusterOf returns the BigDataCluster which has the namespace, the one having it as the default namespace is
+// preferred if there are more than one
+func bigDataClusterOf(ctx context.Context, c client.Reader, namespace string) (*bdcv1alpha1.BigDataCluster, error) {
+	var bdcList bdcv1alpha1.BigDataClusterList
+	if err := c.List(ctx, &bdcList, client.MatchingFields{IndexBigDataClusterNamespace: namespace}); err != nil {
+		return nil, err
+	}
+	candidates := bdcList.Items
+	if len(candidates) > 1 {
+		var defaults []bdcv1alpha1.BigDataCluster
+		for _, bdc := range candidates {
+			for _, ns := range bdc.Spec.Namespaces {
+				if ns.IsDefault && ns.Name == namespace {
+					defaults = append(defaults, bdc)
+					break
+				}
+			}
+		}
+		if len(defaults) > 0 {
+			candidates = defaults
+		}
+	}
+	switch len(candidates) {
+	case 0:
+		return nil, errors.WithMessagef(errNoBigDataCluster, "namespace %s", namespace)
+	case 1:
+		return &candidates[0], nil
+	}
+	names := make([]string, 0, len(candidates))
+	for _, bdc := range candidates {
+		names = append(names, bdc.Name)
+	}
+	sort.Strings(names)
+	return nil, errors.WithMessagef(errAmbiguousBigDataCluster, "namespace %s is in %s", namespace, strings.Join(names, ", "))
+}
+
+// unresolved returns whether err means the BigDataCluster can't be resolved until the namespaces of BigDataClusters change
+func unresolved(err error) bool {
+	return errors.Is(err, errNoBigDataCluster) || errors.Is(err, errAmbiguousBigDataCluster)
+}
+
+// orgNameOf returns the org of the BigDataCluster, the annotation overrides the label
+func orgNameOf(bdc *bdcv1alpha1.BigDataCluster) string {
+	var orgName string
+	if org, ok := bdc.GetLabels()[constants.LabelBDCOrgName]; ok {
+		orgName = org
+	}
+	if org, ok := bdc.GetAnnotations()[constants.AnnotationOrgName]; ok {
+		orgName = org
+	}
+	return orgName
+}
+
+// adopterName returns the name of the ContextSetting or ContextSecret adopting the resource namespace/name
+func adopterName(namespace, name string) string {
+	return fmt.Sprintf("%s-%s", namespace, name)
+}
+
+// adopting returns whether the adopter is marked by adoptKey and refers to the resource namespace/name by refKey
+func adopting(adopter client.Object, adoptKey, refKey, namespace, name string) bool {
+	annotations := adopter.GetAnnotations()
+	return annotations[adoptKey] == "true" &&
+		annotations[constants.AnnotationBDCDefaultNamespace] == namespace &&
+		annotations[refKey] == name
+}
+
+// requestsOfBigDataCluster returns a MapFunc which maps a BigDataCluster to the resources labeled with
+// adoptLabels in its namespaces, newList creates an empty list of the resources
+func requestsOfBigDataCluster(c client.Client, newList func() client.ObjectList, adoptLabels client.MatchingLabels) handler.MapFunc {
+	return func(obj client.Object) []reconcile.Request {
+		bdc, ok := obj.(*bdcv1alpha1.BigDataCluster)
+		if !ok {
+			return nil
+		}
+		var requests []reconcile.Request
+		for _, ns := range bdc.Spec.Namespaces {
+			list := newList()
+			if err := c.List(context.Background(), list, client.InNamespace(ns.Name), adoptLabels); err != nil {
+				klog.ErrorS(err, "Failed to list the resources to be adopted of bigdatacluster", "bigdatacluster", bdc.Name, "namespace", ns.Name)
+				continue
+			}
+			if err := meta.EachListItem(list, func(item runtime.Object) error {
+				if o, ok := item.(client.Object); ok {
+					requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(o)})
+				}
+				return nil
+			}); err != nil {
+				klog.ErrorS(err, "Failed to extract the resources to be adopted", "bigdatacluster", bdc.Name)
+			}
+		}
+		return requests
+	}
+}
+
+// requestOfAdopter returns a MapFunc which maps an adopter marked by adoptKey to the resource referred by refKey
+func requestOfAdopter(adoptKey, refKey string) handler.MapFunc {
+	return func(obj client.Object) []reconcile.Request {
+		annotations := obj.GetAnnotations()
+		name, namespace := annotations[refKey], annotations[constants.AnnotationBDCDefaultNamespace]
+		if annotations[adoptKey] != "true" || name == "" || namespace == "" {
+			return nil
+		}
+		return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: namespace, Name: name}}}
+	}
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2024 KDP(Kubernetes Data Platform).
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adopt
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	bdcv1alpha1 "kdp-oam-operator/api/bdc/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// The adopting controllers are tested against the fake client, so that no control plane is needed to run the suite.
+
+var testScheme = runtime.NewScheme()
+
+func TestAdopt(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	RunSpecs(t, "Adopt Suite")
+}
+
+var _ = BeforeSuite(func() {
+	Expect(clientgoscheme.AddToScheme(testScheme)).Should(Succeed())
+	Expect(bdcv1alpha1.AddToScheme(testScheme)).Should(Succeed())
+})
+
+// newClient creates a fake client with the objects, and the BigDataCluster index registered as the manager does
+func newClient(objs ...client.Object) client.Client {
+	return fake.NewClientBuilder().
+		WithScheme(testScheme).
+		WithObjects(objs...).
+		WithIndex(&bdcv1alpha1.BigDataCluster{}, IndexBigDataClusterNamespace, IndexBigDataClusterNamespaces).
+		Build()
+}
+
+// newReconciler creates a ConfigMapReconciler with the objects
+func newReconciler(objs ...client.Object) (*ConfigMapReconciler, *record.FakeRecorder) {
+	recorder := record.NewFakeRecorder(20)
+	return &ConfigMapReconciler{Client: newClient(objs...), Scheme: testScheme, Recorder: recorder}, recorder
+}
+
+// newSecretReconciler creates a SecretReconciler with the objects
+func newSecretReconciler(objs ...client.Object) (*SecretReconciler, *record.FakeRecorder) {
+	recorder := record.NewFakeRecorder(20)
+	return &SecretReconciler{Client: newClient(objs...), Scheme: testScheme, Recorder: recorder}, recorder
+}
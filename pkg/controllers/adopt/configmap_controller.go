@@ -0,0 +1,404 @@
+/*
+Copyright 2024 KDP(Kubernetes Data Platform).
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adopt
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+	"kdp-oam-operator/api/bdc/common"
+	conditiontype "kdp-oam-operator/api/bdc/condition"
+	bdcv1alpha1 "kdp-oam-operator/api/bdc/v1alpha1"
+	"kdp-oam-operator/pkg/controllers/bdc/constants"
+	"kdp-oam-operator/pkg/controllers/utils/metrics"
+	"kdp-oam-operator/pkg/utils"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// sourceConfig is the value of the source label of the ConfigMaps to be adopted
+const sourceConfig = "config"
+
+var (
+	errSyncConflict  = errors.New("both the ContextSetting and the ConfigMap are changed since last synced")
+	errNotWritable   = errors.New("the properties of the ContextSetting can't be written back to the ConfigMap")
+	errPropertyValue = errors.New("the value is not a string")
+)
+
+// syncDirection is the direction the ContextSetting and the ConfigMap adopted by it are synced in
+type syncDirection int
+
+const (
+	syncedNone syncDirection = iota
+	syncedToContextSetting
+	syncedToConfigMap
+)
+
+// ConfigMapReconciler adopts the ConfigMaps labeled with setting.ctx.bdc.kdp.io/source=config into ContextSettings,
+// the ContextSettings are deleted when the ConfigMaps are deleted or no longer labeled.
+// The ConfigMap and the ContextSetting are synced both ways: the changed side since last synced is copied to the other,
+// nothing is synced if both sides are changed until they are made the same or the ContextSetting is deleted
+type ConfigMapReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+	options
+}
+
+//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=bdc.kdp.io,resources=contextsettings,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=bdc.kdp.io,resources=contextsettings/status,verbs=get;update;patch
+
+// Reconcile adopts the ConfigMap into a ContextSetting of the BigDataCluster which has the namespace of the ConfigMap
+func (r *ConfigMapReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	klog.InfoS("Reconcile configmap", "", klog.KRef(req.Namespace, req.Name))
+
+	var configMap corev1.ConfigMap
+	if err := r.Get(ctx, req.NamespacedName, &configMap); err != nil {
+		if apierrors.IsNotFound(err) {
+			// the ConfigMap may be deleted before the finalizer is added
+			return ctrl.Result{}, r.deleteContextSetting(ctx, req.Namespace, req.Name)
+		}
+		return ctrl.Result{}, err
+	}
+
+	if !configMap.DeletionTimestamp.IsZero() || !configMapAdoptable(&configMap) {
+		return ctrl.Result{}, r.release(ctx, &configMap)
+	}
+
+	if !controllerutil.ContainsFinalizer(&configMap, constants.FinalizerCtxSettingAdopt) {
+		patch := client.MergeFrom(configMap.DeepCopy())
+		controllerutil.AddFinalizer(&configMap, constants.FinalizerCtxSettingAdopt)
+		if err := r.Patch(ctx, &configMap, patch); err != nil {
+			return ctrl.Result{}, err
+		}
+		klog.InfoS("Register new finalizer for configmap", "finalizer", constants.FinalizerCtxSettingAdopt)
+		r.Recorder.Event(&configMap, corev1.EventTypeNormal, constants.EventReasonFinalizerAdded, "Added finalizer "+constants.FinalizerCtxSettingAdopt)
+	}
+
+	name := adopterName(configMap.Namespace, configMap.Name)
+	bdc, err := bigDataClusterOf(ctx, r.Client, configMap.Namespace)
+	if err != nil {
+		klog.ErrorS(err, "Failed to find the BigDataCluster of configmap", "configmap", klog.KObj(&configMap))
+		r.adoptFailed(&configMap, err)
+		if statusErr := r.updateStatus(ctx, name, err.Error(), conditiontype.ReconcileError(err)); statusErr != nil {
+			klog.ErrorS(statusErr, "Failed to update the status of context setting", "contextSetting", name)
+		}
+		if unresolved(err) {
+			// the ConfigMap is requeued once the namespaces of BigDataClusters change
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	direction, err := r.sync(ctx, &configMap, newContextSetting(&configMap, bdc))
+	if errors.Is(err, errSyncConflict) || errors.Is(err, errNotWritable) {
+		// nothing to retry until either side is changed
+		klog.ErrorS(err, "Failed to sync configmap", "configmap", klog.KObj(&configMap), "contextSetting", name)
+		r.Recorder.Eventf(&configMap, corev1.EventTypeWarning, constants.EventReasonSyncConflict, "Failed to sync with ContextSetting %s: %v", name, err)
+		return ctrl.Result{}, r.updateStatus(ctx, name, err.Error(), conditiontype.SyncConflict(err.Error()))
+	}
+	if err != nil {
+		klog.ErrorS(err, "Failed to adopt configmap", "configmap", klog.KObj(&configMap))
+		r.adoptFailed(&configMap, err)
+		return ctrl.Result{}, err
+	}
+	switch direction {
+	case syncedToContextSetting:
+		klog.InfoS("Adopted configmap", "configmap", klog.KObj(&configMap), "contextSetting", name, "bigdatacluster", bdc.Name)
+		r.Recorder.Eventf(&configMap, corev1.EventTypeNormal, constants.EventReasonAdopted, "Adopted into ContextSetting %s", name)
+		metrics.RecordAdoption("ContextSetting", nil)
+	case syncedToConfigMap:
+		klog.InfoS("Wrote the properties of context setting back to configmap", "configmap", klog.KObj(&configMap), "contextSetting", name)
+		r.Recorder.Eventf(&configMap, corev1.EventTypeNormal, constants.EventReasonSyncedBack, "Wrote the properties of ContextSetting %s back", name)
+	}
+	msg := fmt.Sprintf("Adopted from ConfigMap %s of BigDataCluster %s", klog.KObj(&configMap), bdc.Name)
+	return ctrl.Result{}, r.updateStatus(ctx, name, msg, conditiontype.ReconcileSuccess(), conditiontype.Available())
+}
+
+func (r *ConfigMapReconciler) adoptFailed(configMap *corev1.ConfigMap, err error) {
+	r.Recorder.Eventf(configMap, corev1.EventTypeWarning, constants.EventReasonAdoptFailed, "Failed to adopt into ContextSetting: %v", err)
+	metrics.RecordAdoption("ContextSetting", err)
+}
+
+// release deletes the ContextSetting adopting the ConfigMap, and removes the finalizer of the ConfigMap
+func (r *ConfigMapReconciler) release(ctx context.Context, configMap *corev1.ConfigMap) error {
+	if !controllerutil.ContainsFinalizer(configMap, constants.FinalizerCtxSettingAdopt) {
+		return nil
+	}
+	r.Recorder.Event(configMap, corev1.EventTypeNormal, constants.EventReasonFinalizing, "Deleting the adopting ContextSetting")
+	if err := r.deleteContextSetting(ctx, configMap.Namespace, configMap.Name); err != nil {
+		r.Recorder.Eventf(configMap, corev1.EventTypeWarning, constants.EventReasonFinalizeFailed, "Failed to delete the adopting ContextSetting: %v", err)
+		return err
+	}
+	patch := client.MergeFrom(configMap.DeepCopy())
+	controllerutil.RemoveFinalizer(configMap, constants.FinalizerCtxSettingAdopt)
+	if err := r.Patch(ctx, configMap, patch); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	r.Recorder.Event(configMap, corev1.EventTypeNormal, constants.EventReasonFinalized, "Removed finalizer "+constants.FinalizerCtxSettingAdopt)
+	return nil
+}
+
+// deleteContextSetting deletes the ContextSetting adopting the ConfigMap namespace/name,
+// the ContextSetting of the same name but not adopting the ConfigMap is kept
+func (r *ConfigMapReconciler) deleteContextSetting(ctx context.Context, namespace, name string) error {
+	var contextSetting bdcv1alpha1.ContextSetting
+	if err := r.Get(ctx, client.ObjectKey{Name: adopterName(namespace, name)}, &contextSetting); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if !adopting(&contextSetting, constants.AnnotationCtxSettingAdopt, constants.AnnotationCtxSettingReferencedConfigMap, namespace, name) {
+		klog.InfoS("Skip deleting the context setting not adopting the configmap", "contextSetting", contextSetting.Name)
+		return nil
+	}
+	klog.InfoS("will delete", "context setting", contextSetting.Name)
+	return client.IgnoreNotFound(r.Delete(ctx, &contextSetting))
+}
+
+// sync creates the ContextSetting, or syncs the ContextSetting and the ConfigMap in the direction of the side changed
+// since last synced, which is told by the synced hash annotation of the ContextSetting. The ConfigMap wins if the
+// ContextSetting has no synced hash. Returns errSyncConflict if both sides are changed.
+func (r *ConfigMapReconciler) sync(ctx context.Context, configMap *corev1.ConfigMap, desired *bdcv1alpha1.ContextSetting) (syncDirection, error) {
+	direction := syncedNone
+	err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		var existing bdcv1alpha1.ContextSetting
+		if err := r.Get(ctx, client.ObjectKey{Name: desired.Name}, &existing); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return err
+			}
+			klog.InfoS("ContextSetting not found, it will be created", "", desired.Name)
+			if err := r.Create(ctx, desired.DeepCopy()); err != nil {
+				return err
+			}
+			direction = syncedToContextSetting
+			return nil
+		}
+
+		if synced := existing.GetAnnotations()[constants.AnnotationCtxSettingSyncedHash]; synced != "" {
+			configMapHash := dataHash(configMap.Data)
+			data, err := propertiesData(existing.Spec.Properties)
+			if err != nil {
+				if synced != configMapHash {
+					return errors.WithMessagef(errSyncConflict, "ContextSetting %s, ConfigMap %s", existing.Name, klog.KObj(configMap))
+				}
+				return errors.WithMessage(errNotWritable, err.Error())
+			}
+			if settingHash := dataHash(data); settingHash != synced && settingHash != configMapHash {
+				if synced != configMapHash {
+					return errors.WithMessagef(errSyncConflict, "ContextSetting %s, ConfigMap %s", existing.Name, klog.KObj(configMap))
+				}
+				// only the ContextSetting is changed, the ConfigMap is not patched if it's changed since read
+				patched := configMap.DeepCopy()
+				patched.Data = data
+				if err := r.Patch(ctx, patched, client.MergeFromWithOptions(configMap, client.MergeFromWithOptimisticLock{})); err != nil {
+					return err
+				}
+				patched.DeepCopyInto(configMap)
+				direction = syncedToConfigMap
+				desired.Spec.Properties = existing.Spec.Properties
+				desired.Annotations[constants.AnnotationCtxSettingSyncedHash] = settingHash
+			}
+		}
+
+		if metadataEqual(&existing, desired) && contextSettingSpecEqual(&existing.Spec, &desired.Spec) {
+			return nil
+		}
+		mergeMetadata(&existing, desired)
+		existing.Spec = desired.Spec
+		if err := r.Update(ctx, &existing); err != nil {
+			return err
+		}
+		if direction == syncedNone {
+			direction = syncedToContextSetting
+		}
+		return nil
+	})
+	return direction, err
+}
+
+// updateStatus sets the message and conditions of the ContextSetting if it exists and they are changed
+func (r *ConfigMapReconciler) updateStatus(ctx context.Context, name, message string, conditions ...conditiontype.Condition) error {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		var contextSetting bdcv1alpha1.ContextSetting
+		if err := r.Get(ctx, client.ObjectKey{Name: name}, &contextSetting); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		status := contextSetting.Status.DeepCopy()
+		contextSetting.Status.Message = message
+		contextSetting.Status.SetConditions(conditions...)
+		if reflect.DeepEqual(status, &contextSetting.Status) {
+			return nil
+		}
+		return r.Status().Update(ctx, &contextSetting)
+	})
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ConfigMapReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		WithOptions(controller.Options{
+			MaxConcurrentReconciles: r.concurrentReconciles,
+		}).
+		For(&corev1.ConfigMap{}, builder.WithPredicates(predicate.NewPredicateFuncs(func(obj client.Object) bool {
+			return configMapAdoptable(obj) || controllerutil.ContainsFinalizer(obj, constants.FinalizerCtxSettingAdopt)
+		}))).
+		Watches(&source.Kind{Type: &bdcv1alpha1.BigDataCluster{}},
+			handler.EnqueueRequestsFromMapFunc(requestsOfBigDataCluster(r.Client, func() client.ObjectList { return &corev1.ConfigMapList{} },
+				client.MatchingLabels{constants.AnnotationCtxSettingSource: sourceConfig})),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})). // watch BigDataCluster for the changed namespaces
+		Watches(&source.Kind{Type: &bdcv1alpha1.ContextSetting{}},
+			handler.EnqueueRequestsFromMapFunc(requestOfAdopter(constants.AnnotationCtxSettingAdopt, constants.AnnotationCtxSettingReferencedConfigMap)),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})). // watch ContextSetting for reverting the changed or deleted ones
+		Complete(r)
+}
+
+// configMapAdoptable returns whether the ConfigMap is labeled to be adopted
+func configMapAdoptable(obj client.Object) bool {
+	return obj.GetLabels()[constants.AnnotationCtxSettingSource] == sourceConfig
+}
+
+// newContextSetting returns the ContextSetting adopting the ConfigMap for the BigDataCluster
+func newContextSetting(configMap *corev1.ConfigMap, bdc *bdcv1alpha1.BigDataCluster) *bdcv1alpha1.ContextSetting {
+	orgName := orgNameOf(bdc)
+	// get configmap referenced definition type
+	settingType := configMap.GetAnnotations()[constants.AnnotationCtxSettingType]
+	if settingType == "" {
+		settingType = "default"
+	}
+	return &bdcv1alpha1.ContextSetting{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ContextSetting",
+			APIVersion: bdcv1alpha1.GroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: adopterName(configMap.Namespace, configMap.Name),
+			Annotations: map[string]string{
+				constants.AnnotationCtxSettingOrigin:              string(common.CtxSettingCreatedViaSystem),
+				constants.AnnotationCtxSettingSource:              sourceConfig,
+				constants.AnnotationCtxSettingAdopt:               "true",
+				constants.AnnotationBDCDefaultNamespace:           configMap.Namespace,
+				constants.AnnotationBDCName:                       bdc.Name,
+				constants.AnnotationOrgName:                       orgName,
+				constants.AnnotationCtxSettingReferencedConfigMap: configMap.Name,
+				constants.AnnotationCtxSettingSyncedHash:          dataHash(configMap.Data),
+			},
+			Labels: map[string]string{
+				constants.AnnotationBDCName: bdc.Name,
+				constants.AnnotationOrgName: orgName,
+			},
+		},
+		Spec: bdcv1alpha1.ContextSettingSpec{
+			Name:       configMap.Name,
+			Type:       settingType,
+			Properties: utils.Object2RawExtension(configMap.Data),
+		},
+	}
+}
+
+// propertiesData returns the properties of the ContextSetting as the data of the ConfigMap, all the values must be strings
+func propertiesData(properties *runtime.RawExtension) (map[string]string, error) {
+	props, err := utils.RawExtension2Map(properties)
+	if err != nil {
+		return nil, err
+	}
+	var invalid []string
+	data := make(map[string]string, len(props))
+	for k, v := range props {
+		value, ok := v.(string)
+		if !ok {
+			invalid = append(invalid, k)
+			continue
+		}
+		data[k] = value
+	}
+	if len(invalid) > 0 {
+		sort.Strings(invalid)
+		return nil, errors.WithMessagef(errPropertyValue, "property %s", strings.Join(invalid, ", "))
+	}
+	return data, nil
+}
+
+// dataHash returns the hash of the ConfigMap data, the empty and nil data have the same hash
+func dataHash(data map[string]string) string {
+	if data == nil {
+		data = map[string]string{}
+	}
+	// the keys of a map are sorted when marshalled, and a map of strings is always marshalled
+	raw, _ := json.Marshal(data)
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:8])
+}
+
+// contextSettingSpecEqual returns whether the spec of the existing ContextSetting equals to the desired one
+func contextSettingSpecEqual(existing, desired *bdcv1alpha1.ContextSettingSpec) bool {
+	if existing.Name != desired.Name || existing.Type != desired.Type {
+		return false
+	}
+	existingProps, err := utils.RawExtension2Map(existing.Properties)
+	if err != nil {
+		return false
+	}
+	desiredProps, err := utils.RawExtension2Map(desired.Properties)
+	if err != nil {
+		return false
+	}
+	return reflect.DeepEqual(existingProps, desiredProps)
+}
+
+// metadataEqual returns whether the existing adopter has the labels and annotations of the desired one
+func metadataEqual(existing, desired client.Object) bool {
+	for k, v := range desired.GetLabels() {
+		if existing.GetLabels()[k] != v {
+			return false
+		}
+	}
+	for k, v := range desired.GetAnnotations() {
+		if existing.GetAnnotations()[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// mergeMetadata merges the labels and annotations of the desired adopter into the existing one, and sets its updateTime
+func mergeMetadata(existing, desired client.Object) {
+	existing.SetLabels(utils.MergeMapOverrideWithDst(existing.GetLabels(), desired.GetLabels()))
+	annotations := utils.MergeMapOverrideWithDst(existing.GetAnnotations(), desired.GetAnnotations())
+	annotations[constants.AnnotationBDCUpdatedTime] = metav1.Now().Format(time.RFC3339)
+	existing.SetAnnotations(annotations)
+}
@@ -0,0 +1,285 @@
+/*
+Copyright 2024 KDP(Kubernetes Data Platform).
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adopt
+
+import (
+	"context"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	conditiontype "kdp-oam-operator/api/bdc/condition"
+	bdcv1alpha1 "kdp-oam-operator/api/bdc/v1alpha1"
+	"kdp-oam-operator/pkg/controllers/bdc/constants"
+	"kdp-oam-operator/pkg/utils"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+func newBigDataCluster(name string, namespaces ...bdcv1alpha1.Namespace) *bdcv1alpha1.BigDataCluster {
+	return &bdcv1alpha1.BigDataCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{constants.LabelBDCOrgName: "org-a"}},
+		Spec:       bdcv1alpha1.BigDataClusterSpec{Namespaces: namespaces},
+	}
+}
+
+func newConfigMap(namespace, name string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   namespace,
+			Labels:      map[string]string{constants.AnnotationCtxSettingSource: "config"},
+			Annotations: map[string]string{constants.AnnotationCtxSettingType: "hdfs"},
+		},
+		Data: map[string]string{"host": "hdfs-namenode"},
+	}
+}
+
+func drainEvents(recorder *record.FakeRecorder) string {
+	var events []string
+	for {
+		select {
+		case e := <-recorder.Events:
+			events = append(events, e)
+		default:
+			return strings.Join(events, "\n")
+		}
+	}
+}
+
+var _ = Describe("Test ConfigMap Syncer", func() {
+	ctx := context.Background()
+	request := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "hdfs-ns", Name: "hdfs-config"}}
+	settingKey := client.ObjectKey{Name: "hdfs-ns-hdfs-config"}
+
+	It("adopts the ConfigMap in a non-default namespace of BigDataCluster", func() {
+		r, recorder := newReconciler(
+			newBigDataCluster("bdc-a", bdcv1alpha1.Namespace{Name: "bdc-a-ns", IsDefault: true}, bdcv1alpha1.Namespace{Name: "hdfs-ns"}),
+			newConfigMap("hdfs-ns", "hdfs-config"),
+		)
+		_, err := r.Reconcile(ctx, request)
+		Expect(err).Should(BeNil())
+
+		var cm corev1.ConfigMap
+		Expect(r.Get(ctx, request.NamespacedName, &cm)).Should(Succeed())
+		Expect(controllerutil.ContainsFinalizer(&cm, constants.FinalizerCtxSettingAdopt)).Should(BeTrue())
+
+		var setting bdcv1alpha1.ContextSetting
+		Expect(r.Get(ctx, settingKey, &setting)).Should(Succeed())
+		Expect(setting.Labels).Should(HaveKeyWithValue(constants.AnnotationBDCName, "bdc-a"))
+		Expect(setting.Annotations).Should(HaveKeyWithValue(constants.AnnotationOrgName, "org-a"))
+		Expect(setting.Spec.Type).Should(Equal("hdfs"))
+		props, err := utils.RawExtension2Map(setting.Spec.Properties)
+		Expect(err).Should(BeNil())
+		Expect(props).Should(HaveKeyWithValue("host", "hdfs-namenode"))
+		Expect(setting.Status.Message).Should(Equal("Adopted from ConfigMap hdfs-ns/hdfs-config of BigDataCluster bdc-a"))
+		Expect(setting.Status.GetCondition(conditiontype.TypeSynced).Reason).Should(Equal(conditiontype.ReasonReconcileSuccess))
+		Expect(setting.Status.GetCondition(conditiontype.TypeReady).Status).Should(Equal(corev1.ConditionTrue))
+		Expect(drainEvents(recorder)).Should(ContainSubstring(constants.EventReasonAdopted))
+
+		By("Reconcile again without any change")
+		_, err = r.Reconcile(ctx, request)
+		Expect(err).Should(BeNil())
+		var unchanged bdcv1alpha1.ContextSetting
+		Expect(r.Get(ctx, settingKey, &unchanged)).Should(Succeed())
+		Expect(unchanged.ResourceVersion).Should(Equal(setting.ResourceVersion))
+		Expect(drainEvents(recorder)).ShouldNot(ContainSubstring(constants.EventReasonAdopted))
+
+		By("Update the ConfigMap")
+		cm.Data["host"] = "hdfs-namenode-0"
+		Expect(r.Update(ctx, &cm)).Should(Succeed())
+		_, err = r.Reconcile(ctx, request)
+		Expect(err).Should(BeNil())
+		Expect(r.Get(ctx, settingKey, &setting)).Should(Succeed())
+		props, err = utils.RawExtension2Map(setting.Spec.Properties)
+		Expect(err).Should(BeNil())
+		Expect(props).Should(HaveKeyWithValue("host", "hdfs-namenode-0"))
+		Expect(setting.Annotations).Should(HaveKey(constants.AnnotationBDCUpdatedTime))
+	})
+
+	It("writes the changed properties of the ContextSetting back to the ConfigMap", func() {
+		r, recorder := newReconciler(
+			newBigDataCluster("bdc-a", bdcv1alpha1.Namespace{Name: "hdfs-ns", IsDefault: true}),
+			newConfigMap("hdfs-ns", "hdfs-config"),
+		)
+		_, err := r.Reconcile(ctx, request)
+		Expect(err).Should(BeNil())
+		drainEvents(recorder)
+
+		var setting bdcv1alpha1.ContextSetting
+		Expect(r.Get(ctx, settingKey, &setting)).Should(Succeed())
+		setting.Spec.Properties = utils.Object2RawExtension(map[string]string{"host": "hdfs-namenode-1", "port": "8020"})
+		Expect(r.Update(ctx, &setting)).Should(Succeed())
+		_, err = r.Reconcile(ctx, request)
+		Expect(err).Should(BeNil())
+
+		var cm corev1.ConfigMap
+		Expect(r.Get(ctx, request.NamespacedName, &cm)).Should(Succeed())
+		Expect(cm.Data).Should(Equal(map[string]string{"host": "hdfs-namenode-1", "port": "8020"}))
+		Expect(r.Get(ctx, settingKey, &setting)).Should(Succeed())
+		Expect(setting.Annotations).Should(HaveKeyWithValue(constants.AnnotationCtxSettingSyncedHash, dataHash(cm.Data)))
+		Expect(drainEvents(recorder)).Should(ContainSubstring(constants.EventReasonSyncedBack))
+
+		By("Reconcile again without any change")
+		_, err = r.Reconcile(ctx, request)
+		Expect(err).Should(BeNil())
+		var unchanged corev1.ConfigMap
+		Expect(r.Get(ctx, request.NamespacedName, &unchanged)).Should(Succeed())
+		Expect(unchanged.ResourceVersion).Should(Equal(cm.ResourceVersion))
+		Expect(drainEvents(recorder)).Should(BeEmpty())
+	})
+
+	It("reports the conflict when both the ContextSetting and the ConfigMap are changed", func() {
+		r, recorder := newReconciler(
+			newBigDataCluster("bdc-a", bdcv1alpha1.Namespace{Name: "hdfs-ns", IsDefault: true}),
+			newConfigMap("hdfs-ns", "hdfs-config"),
+		)
+		_, err := r.Reconcile(ctx, request)
+		Expect(err).Should(BeNil())
+		drainEvents(recorder)
+
+		var setting bdcv1alpha1.ContextSetting
+		Expect(r.Get(ctx, settingKey, &setting)).Should(Succeed())
+		setting.Spec.Properties = utils.Object2RawExtension(map[string]string{"host": "from-setting"})
+		Expect(r.Update(ctx, &setting)).Should(Succeed())
+		var cm corev1.ConfigMap
+		Expect(r.Get(ctx, request.NamespacedName, &cm)).Should(Succeed())
+		cm.Data["host"] = "from-configmap"
+		Expect(r.Update(ctx, &cm)).Should(Succeed())
+
+		_, err = r.Reconcile(ctx, request)
+		Expect(err).Should(BeNil())
+		Expect(r.Get(ctx, request.NamespacedName, &cm)).Should(Succeed())
+		Expect(cm.Data).Should(HaveKeyWithValue("host", "from-configmap"))
+		Expect(r.Get(ctx, settingKey, &setting)).Should(Succeed())
+		props, err := utils.RawExtension2Map(setting.Spec.Properties)
+		Expect(err).Should(BeNil())
+		Expect(props).Should(HaveKeyWithValue("host", "from-setting"))
+		Expect(setting.Status.GetCondition(conditiontype.TypeSynced).Reason).Should(Equal(conditiontype.ReasonSyncConflict))
+		Expect(drainEvents(recorder)).Should(ContainSubstring(constants.EventReasonSyncConflict))
+
+		By("Delete the ContextSetting to take the ConfigMap")
+		Expect(r.Delete(ctx, &setting)).Should(Succeed())
+		_, err = r.Reconcile(ctx, request)
+		Expect(err).Should(BeNil())
+		Expect(r.Get(ctx, settingKey, &setting)).Should(Succeed())
+		props, err = utils.RawExtension2Map(setting.Spec.Properties)
+		Expect(err).Should(BeNil())
+		Expect(props).Should(HaveKeyWithValue("host", "from-configmap"))
+		Expect(setting.Status.GetCondition(conditiontype.TypeSynced).Reason).Should(Equal(conditiontype.ReasonReconcileSuccess))
+	})
+
+	It("prefers the BigDataCluster having the namespace as default", func() {
+		r, _ := newReconciler(
+			newBigDataCluster("bdc-a", bdcv1alpha1.Namespace{Name: "hdfs-ns"}),
+			newBigDataCluster("bdc-b", bdcv1alpha1.Namespace{Name: "hdfs-ns", IsDefault: true}),
+			newConfigMap("hdfs-ns", "hdfs-config"),
+		)
+		_, err := r.Reconcile(ctx, request)
+		Expect(err).Should(BeNil())
+		var setting bdcv1alpha1.ContextSetting
+		Expect(r.Get(ctx, settingKey, &setting)).Should(Succeed())
+		Expect(setting.Annotations).Should(HaveKeyWithValue(constants.AnnotationBDCName, "bdc-b"))
+	})
+
+	It("reports the namespace owned by no BigDataCluster or more than one", func() {
+		tests := []struct {
+			name    string
+			bdcs    []*bdcv1alpha1.BigDataCluster
+			message string
+		}{
+			{name: "no BigDataCluster", message: "namespace hdfs-ns: no BigDataCluster has the namespace"},
+			{
+				name: "ambiguous BigDataClusters",
+				bdcs: []*bdcv1alpha1.BigDataCluster{
+					newBigDataCluster("bdc-b", bdcv1alpha1.Namespace{Name: "hdfs-ns"}),
+					newBigDataCluster("bdc-a", bdcv1alpha1.Namespace{Name: "hdfs-ns"}),
+				},
+				message: "namespace hdfs-ns is in bdc-a, bdc-b: more than one BigDataCluster have the namespace",
+			},
+		}
+		for _, tt := range tests {
+			By(tt.name)
+			objs := []client.Object{newConfigMap("hdfs-ns", "hdfs-config")}
+			for _, bdc := range tt.bdcs {
+				objs = append(objs, bdc)
+			}
+			r, recorder := newReconciler(objs...)
+			_, err := r.Reconcile(ctx, request)
+			Expect(err).Should(BeNil())
+			Expect(apierrors.IsNotFound(r.Get(ctx, settingKey, &bdcv1alpha1.ContextSetting{}))).Should(BeTrue())
+			Expect(drainEvents(recorder)).Should(ContainSubstring(tt.message))
+		}
+	})
+
+	It("deletes the ContextSetting when the ConfigMap is deleted or no longer labeled", func() {
+		for _, release := range []func(r *ConfigMapReconciler, cm *corev1.ConfigMap){
+			func(r *ConfigMapReconciler, cm *corev1.ConfigMap) {
+				Expect(r.Delete(ctx, cm)).Should(Succeed())
+			},
+			func(r *ConfigMapReconciler, cm *corev1.ConfigMap) {
+				delete(cm.Labels, constants.AnnotationCtxSettingSource)
+				Expect(r.Update(ctx, cm)).Should(Succeed())
+			},
+		} {
+			r, recorder := newReconciler(
+				newBigDataCluster("bdc-a", bdcv1alpha1.Namespace{Name: "hdfs-ns", IsDefault: true}),
+				newConfigMap("hdfs-ns", "hdfs-config"),
+			)
+			_, err := r.Reconcile(ctx, request)
+			Expect(err).Should(BeNil())
+			Expect(r.Get(ctx, settingKey, &bdcv1alpha1.ContextSetting{})).Should(Succeed())
+
+			var cm corev1.ConfigMap
+			Expect(r.Get(ctx, request.NamespacedName, &cm)).Should(Succeed())
+			release(r, &cm)
+			_, err = r.Reconcile(ctx, request)
+			Expect(err).Should(BeNil())
+			Expect(apierrors.IsNotFound(r.Get(ctx, settingKey, &bdcv1alpha1.ContextSetting{}))).Should(BeTrue())
+			if err := r.Get(ctx, request.NamespacedName, &cm); err == nil {
+				Expect(controllerutil.ContainsFinalizer(&cm, constants.FinalizerCtxSettingAdopt)).Should(BeFalse())
+			}
+			Expect(drainEvents(recorder)).Should(ContainSubstring(constants.EventReasonFinalized))
+		}
+	})
+
+	It("keeps the ContextSetting of the same name not adopting the ConfigMap", func() {
+		r, _ := newReconciler(&bdcv1alpha1.ContextSetting{ObjectMeta: metav1.ObjectMeta{Name: settingKey.Name}})
+		_, err := r.Reconcile(ctx, request)
+		Expect(err).Should(BeNil())
+		Expect(r.Get(ctx, settingKey, &bdcv1alpha1.ContextSetting{})).Should(Succeed())
+	})
+
+	It("maps the watched objects to the ConfigMaps", func() {
+		bdc := newBigDataCluster("bdc-a", bdcv1alpha1.Namespace{Name: "hdfs-ns", IsDefault: true}, bdcv1alpha1.Namespace{Name: "other-ns"})
+		unlabeled := newConfigMap("hdfs-ns", "unlabeled")
+		unlabeled.Labels = nil
+		r, _ := newReconciler(bdc, newConfigMap("hdfs-ns", "hdfs-config"), unlabeled)
+		Expect(requestsOfBigDataCluster(r.Client, func() client.ObjectList { return &corev1.ConfigMapList{} },
+			client.MatchingLabels{constants.AnnotationCtxSettingSource: sourceConfig})(bdc)).Should(Equal([]ctrl.Request{request}))
+
+		setting := newContextSetting(newConfigMap("hdfs-ns", "hdfs-config"), bdc)
+		Expect(requestOfAdopter(constants.AnnotationCtxSettingAdopt, constants.AnnotationCtxSettingReferencedConfigMap)(setting)).Should(Equal([]ctrl.Request{request}))
+		Expect(requestOfAdopter(constants.AnnotationCtxSettingAdopt, constants.AnnotationCtxSettingReferencedConfigMap)(&bdcv1alpha1.ContextSetting{})).Should(BeEmpty())
+	})
+})
@@ -0,0 +1,269 @@
+/*
+Copyright 2024 KDP(Kubernetes Data Platform).
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adopt
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+	conditiontype "kdp-oam-operator/api/bdc/condition"
+	bdcv1alpha1 "kdp-oam-operator/api/bdc/v1alpha1"
+	"kdp-oam-operator/pkg/controllers/bdc/constants"
+	"kdp-oam-operator/pkg/controllers/utils/metrics"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// sourceSecret is the value of the source label of the Secrets to be adopted
+const sourceSecret = "secret"
+
+// SecretReconciler adopts the Secrets labeled with secret.ctx.bdc.kdp.io/source=secret into ContextSecrets,
+// the ContextSecrets only refer to the Secrets, their values are never copied into the ContextSecrets.
+// The ContextSecrets are deleted when the Secrets are deleted or no longer labeled
+type SecretReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+	options
+}
+
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=bdc.kdp.io,resources=contextsecrets,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=bdc.kdp.io,resources=contextsecrets/status,verbs=get;update;patch
+
+// Reconcile adopts the Secret into a ContextSecret of the BigDataCluster which has the namespace of the Secret
+func (r *SecretReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	klog.InfoS("Reconcile secret", "", klog.KRef(req.Namespace, req.Name))
+
+	var secret corev1.Secret
+	if err := r.Get(ctx, req.NamespacedName, &secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			// the Secret may be deleted before the finalizer is added
+			return ctrl.Result{}, r.deleteContextSecret(ctx, req.Namespace, req.Name)
+		}
+		return ctrl.Result{}, err
+	}
+
+	if !secret.DeletionTimestamp.IsZero() || !secretAdoptable(&secret) {
+		return ctrl.Result{}, r.release(ctx, &secret)
+	}
+
+	if !controllerutil.ContainsFinalizer(&secret, constants.FinalizerCtxSecretAdopt) {
+		patch := client.MergeFrom(secret.DeepCopy())
+		controllerutil.AddFinalizer(&secret, constants.FinalizerCtxSecretAdopt)
+		if err := r.Patch(ctx, &secret, patch); err != nil {
+			return ctrl.Result{}, err
+		}
+		klog.InfoS("Register new finalizer for secret", "finalizer", constants.FinalizerCtxSecretAdopt)
+		r.Recorder.Event(&secret, corev1.EventTypeNormal, constants.EventReasonFinalizerAdded, "Added finalizer "+constants.FinalizerCtxSecretAdopt)
+	}
+
+	name := adopterName(secret.Namespace, secret.Name)
+	bdc, err := bigDataClusterOf(ctx, r.Client, secret.Namespace)
+	if err != nil {
+		klog.ErrorS(err, "Failed to find the BigDataCluster of secret", "secret", klog.KObj(&secret))
+		r.adoptFailed(&secret, err)
+		if statusErr := r.updateStatus(ctx, name, err.Error(), conditiontype.ReconcileError(err)); statusErr != nil {
+			klog.ErrorS(statusErr, "Failed to update the status of context secret", "contextSecret", name)
+		}
+		if unresolved(err) {
+			// the Secret is requeued once the namespaces of BigDataClusters change
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	changed, err := r.upsert(ctx, newContextSecret(&secret, bdc))
+	if err != nil {
+		klog.ErrorS(err, "Failed to adopt secret", "secret", klog.KObj(&secret))
+		r.adoptFailed(&secret, err)
+		return ctrl.Result{}, err
+	}
+	if changed {
+		klog.InfoS("Adopted secret", "secret", klog.KObj(&secret), "contextSecret", name, "bigdatacluster", bdc.Name)
+		r.Recorder.Eventf(&secret, corev1.EventTypeNormal, constants.EventReasonAdopted, "Adopted into ContextSecret %s", name)
+		metrics.RecordAdoption("ContextSecret", nil)
+	}
+	// only the keys of the Secret are exposed in the status
+	keys := make([]string, 0, len(secret.Data))
+	for key := range secret.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	msg := fmt.Sprintf("Adopted from Secret %s of BigDataCluster %s, keys: %s", klog.KObj(&secret), bdc.Name, strings.Join(keys, ", "))
+	return ctrl.Result{}, r.updateStatus(ctx, name, msg, conditiontype.ReconcileSuccess(), conditiontype.Available())
+}
+
+func (r *SecretReconciler) adoptFailed(secret *corev1.Secret, err error) {
+	r.Recorder.Eventf(secret, corev1.EventTypeWarning, constants.EventReasonAdoptFailed, "Failed to adopt into ContextSecret: %v", err)
+	metrics.RecordAdoption("ContextSecret", err)
+}
+
+// release deletes the ContextSecret adopting the Secret, and removes the finalizer of the Secret
+func (r *SecretReconciler) release(ctx context.Context, secret *corev1.Secret) error {
+	if !controllerutil.ContainsFinalizer(secret, constants.FinalizerCtxSecretAdopt) {
+		return nil
+	}
+	r.Recorder.Event(secret, corev1.EventTypeNormal, constants.EventReasonFinalizing, "Deleting the adopting ContextSecret")
+	if err := r.deleteContextSecret(ctx, secret.Namespace, secret.Name); err != nil {
+		r.Recorder.Eventf(secret, corev1.EventTypeWarning, constants.EventReasonFinalizeFailed, "Failed to delete the adopting ContextSecret: %v", err)
+		return err
+	}
+	patch := client.MergeFrom(secret.DeepCopy())
+	controllerutil.RemoveFinalizer(secret, constants.FinalizerCtxSecretAdopt)
+	if err := r.Patch(ctx, secret, patch); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	r.Recorder.Event(secret, corev1.EventTypeNormal, constants.EventReasonFinalized, "Removed finalizer "+constants.FinalizerCtxSecretAdopt)
+	return nil
+}
+
+// deleteContextSecret deletes the ContextSecret adopting the Secret namespace/name,
+// the ContextSecret of the same name but not adopting the Secret is kept
+func (r *SecretReconciler) deleteContextSecret(ctx context.Context, namespace, name string) error {
+	var contextSecret bdcv1alpha1.ContextSecret
+	if err := r.Get(ctx, client.ObjectKey{Name: adopterName(namespace, name)}, &contextSecret); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if !adopting(&contextSecret, constants.AnnotationCtxSecretAdopt, constants.AnnotationCtxSecretReferencedSecret, namespace, name) {
+		klog.InfoS("Skip deleting the context secret not adopting the secret", "contextSecret", contextSecret.Name)
+		return nil
+	}
+	klog.InfoS("will delete", "context secret", contextSecret.Name)
+	return client.IgnoreNotFound(r.Delete(ctx, &contextSecret))
+}
+
+// upsert creates the ContextSecret or updates it if it differs from the desired one, returns whether it's changed
+func (r *SecretReconciler) upsert(ctx context.Context, desired *bdcv1alpha1.ContextSecret) (bool, error) {
+	changed := false
+	err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		var existing bdcv1alpha1.ContextSecret
+		if err := r.Get(ctx, client.ObjectKey{Name: desired.Name}, &existing); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return err
+			}
+			klog.InfoS("ContextSecret not found, it will be created", "", desired.Name)
+			if err := r.Create(ctx, desired.DeepCopy()); err != nil {
+				return err
+			}
+			changed = true
+			return nil
+		}
+		if metadataEqual(&existing, desired) && reflect.DeepEqual(existing.Spec, desired.Spec) {
+			return nil
+		}
+		mergeMetadata(&existing, desired)
+		existing.Spec = desired.Spec
+		if err := r.Update(ctx, &existing); err != nil {
+			return err
+		}
+		changed = true
+		return nil
+	})
+	return changed, err
+}
+
+// updateStatus sets the message and conditions of the ContextSecret if it exists and they are changed
+func (r *SecretReconciler) updateStatus(ctx context.Context, name, message string, conditions ...conditiontype.Condition) error {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		var contextSecret bdcv1alpha1.ContextSecret
+		if err := r.Get(ctx, client.ObjectKey{Name: name}, &contextSecret); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		status := contextSecret.Status.DeepCopy()
+		contextSecret.Status.Message = message
+		contextSecret.Status.SetConditions(conditions...)
+		if reflect.DeepEqual(status, &contextSecret.Status) {
+			return nil
+		}
+		return r.Status().Update(ctx, &contextSecret)
+	})
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *SecretReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		WithOptions(controller.Options{
+			MaxConcurrentReconciles: r.concurrentReconciles,
+		}).
+		For(&corev1.Secret{}, builder.WithPredicates(predicate.NewPredicateFuncs(func(obj client.Object) bool {
+			return secretAdoptable(obj) || controllerutil.ContainsFinalizer(obj, constants.FinalizerCtxSecretAdopt)
+		}))).
+		Watches(&source.Kind{Type: &bdcv1alpha1.BigDataCluster{}},
+			handler.EnqueueRequestsFromMapFunc(requestsOfBigDataCluster(r.Client, func() client.ObjectList { return &corev1.SecretList{} },
+				client.MatchingLabels{constants.AnnotationCtxSecretSource: sourceSecret})),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})). // watch BigDataCluster for the changed namespaces
+		Watches(&source.Kind{Type: &bdcv1alpha1.ContextSecret{}},
+			handler.EnqueueRequestsFromMapFunc(requestOfAdopter(constants.AnnotationCtxSecretAdopt, constants.AnnotationCtxSecretReferencedSecret)),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})). // watch ContextSecret for reverting the changed or deleted ones
+		Complete(r)
+}
+
+// secretAdoptable returns whether the Secret is labeled to be adopted
+func secretAdoptable(obj client.Object) bool {
+	return obj.GetLabels()[constants.AnnotationCtxSecretSource] == sourceSecret
+}
+
+// newContextSecret returns the ContextSecret adopting the Secret for the BigDataCluster, which refers to the Secret
+// by spec.name and the annotations, no value of the Secret is copied into it
+func newContextSecret(secret *corev1.Secret, bdc *bdcv1alpha1.BigDataCluster) *bdcv1alpha1.ContextSecret {
+	orgName := orgNameOf(bdc)
+	secretType := secret.GetAnnotations()[constants.AnnotationCtxSecretType]
+	if secretType == "" {
+		secretType = "default"
+	}
+	return &bdcv1alpha1.ContextSecret{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ContextSecret",
+			APIVersion: bdcv1alpha1.GroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: adopterName(secret.Namespace, secret.Name),
+			Annotations: map[string]string{
+				constants.AnnotationCtxSecretAdopt:            "true",
+				constants.AnnotationBDCDefaultNamespace:       secret.Namespace,
+				constants.AnnotationBDCName:                   bdc.Name,
+				constants.AnnotationOrgName:                   orgName,
+				constants.AnnotationCtxSecretReferencedSecret: secret.Name,
+			},
+			Labels: map[string]string{
+				constants.AnnotationBDCName: bdc.Name,
+				constants.AnnotationOrgName: orgName,
+			},
+		},
+		Spec: bdcv1alpha1.ContextSecretSpec{
+			Name: secret.Name,
+			Type: secretType,
+		},
+	}
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2024 KDP(Kubernetes Data Platform).
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adopt
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	conditiontype "kdp-oam-operator/api/bdc/condition"
+	bdcv1alpha1 "kdp-oam-operator/api/bdc/v1alpha1"
+	"kdp-oam-operator/pkg/controllers/bdc/constants"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+func newSecret(namespace, name string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   namespace,
+			Labels:      map[string]string{constants.AnnotationCtxSecretSource: "secret"},
+			Annotations: map[string]string{constants.AnnotationCtxSecretType: "mysql"},
+		},
+		Data: map[string][]byte{"user": []byte("root"), "password": []byte("s3cr3t")},
+	}
+}
+
+var _ = Describe("Test Secret Syncer", func() {
+	ctx := context.Background()
+	request := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "mysql-ns", Name: "mysql-secret"}}
+	secretKey := client.ObjectKey{Name: "mysql-ns-mysql-secret"}
+
+	It("adopts the Secret without copying its values", func() {
+		r, recorder := newSecretReconciler(
+			newBigDataCluster("bdc-a", bdcv1alpha1.Namespace{Name: "mysql-ns", IsDefault: true}),
+			newSecret("mysql-ns", "mysql-secret"),
+		)
+		_, err := r.Reconcile(ctx, request)
+		Expect(err).Should(BeNil())
+
+		var secret corev1.Secret
+		Expect(r.Get(ctx, request.NamespacedName, &secret)).Should(Succeed())
+		Expect(controllerutil.ContainsFinalizer(&secret, constants.FinalizerCtxSecretAdopt)).Should(BeTrue())
+
+		var contextSecret bdcv1alpha1.ContextSecret
+		Expect(r.Get(ctx, secretKey, &contextSecret)).Should(Succeed())
+		Expect(contextSecret.Labels).Should(HaveKeyWithValue(constants.AnnotationBDCName, "bdc-a"))
+		Expect(contextSecret.Annotations).Should(HaveKeyWithValue(constants.AnnotationCtxSecretReferencedSecret, "mysql-secret"))
+		Expect(contextSecret.Annotations).Should(HaveKeyWithValue(constants.AnnotationBDCDefaultNamespace, "mysql-ns"))
+		Expect(contextSecret.Spec.Name).Should(Equal("mysql-secret"))
+		Expect(contextSecret.Spec.Type).Should(Equal("mysql"))
+		Expect(contextSecret.Spec.Properties).Should(BeNil())
+		Expect(contextSecret.Status.Message).Should(Equal("Adopted from Secret mysql-ns/mysql-secret of BigDataCluster bdc-a, keys: password, user"))
+		Expect(contextSecret.Status.Message).ShouldNot(ContainSubstring("s3cr3t"))
+		Expect(contextSecret.Status.GetCondition(conditiontype.TypeReady).Status).Should(Equal(corev1.ConditionTrue))
+		Expect(drainEvents(recorder)).Should(ContainSubstring(constants.EventReasonAdopted))
+
+		By("Reconcile again without any change")
+		_, err = r.Reconcile(ctx, request)
+		Expect(err).Should(BeNil())
+		var unchanged bdcv1alpha1.ContextSecret
+		Expect(r.Get(ctx, secretKey, &unchanged)).Should(Succeed())
+		Expect(unchanged.ResourceVersion).Should(Equal(contextSecret.ResourceVersion))
+		Expect(drainEvents(recorder)).ShouldNot(ContainSubstring(constants.EventReasonAdopted))
+	})
+
+	It("reports the namespace owned by no BigDataCluster", func() {
+		r, recorder := newSecretReconciler(newSecret("mysql-ns", "mysql-secret"))
+		_, err := r.Reconcile(ctx, request)
+		Expect(err).Should(BeNil())
+		Expect(apierrors.IsNotFound(r.Get(ctx, secretKey, &bdcv1alpha1.ContextSecret{}))).Should(BeTrue())
+		Expect(drainEvents(recorder)).Should(ContainSubstring(constants.EventReasonAdoptFailed))
+	})
+
+	It("deletes the ContextSecret when the Secret is no longer labeled", func() {
+		r, recorder := newSecretReconciler(
+			newBigDataCluster("bdc-a", bdcv1alpha1.Namespace{Name: "mysql-ns", IsDefault: true}),
+			newSecret("mysql-ns", "mysql-secret"),
+		)
+		_, err := r.Reconcile(ctx, request)
+		Expect(err).Should(BeNil())
+		Expect(r.Get(ctx, secretKey, &bdcv1alpha1.ContextSecret{})).Should(Succeed())
+
+		var secret corev1.Secret
+		Expect(r.Get(ctx, request.NamespacedName, &secret)).Should(Succeed())
+		delete(secret.Labels, constants.AnnotationCtxSecretSource)
+		Expect(r.Update(ctx, &secret)).Should(Succeed())
+		_, err = r.Reconcile(ctx, request)
+		Expect(err).Should(BeNil())
+		Expect(apierrors.IsNotFound(r.Get(ctx, secretKey, &bdcv1alpha1.ContextSecret{}))).Should(BeTrue())
+		Expect(r.Get(ctx, request.NamespacedName, &secret)).Should(Succeed())
+		Expect(controllerutil.ContainsFinalizer(&secret, constants.FinalizerCtxSecretAdopt)).Should(BeFalse())
+		Expect(drainEvents(recorder)).Should(ContainSubstring(constants.EventReasonFinalized))
+	})
+
+	It("maps the watched objects to the Secrets", func() {
+		bdc := newBigDataCluster("bdc-a", bdcv1alpha1.Namespace{Name: "mysql-ns", IsDefault: true})
+		unlabeled := newSecret("mysql-ns", "unlabeled")
+		unlabeled.Labels = nil
+		r, _ := newSecretReconciler(bdc, newSecret("mysql-ns", "mysql-secret"), unlabeled)
+		Expect(requestsOfBigDataCluster(r.Client, func() client.ObjectList { return &corev1.SecretList{} },
+			client.MatchingLabels{constants.AnnotationCtxSecretSource: sourceSecret})(bdc)).Should(Equal([]ctrl.Request{request}))
+
+		contextSecret := newContextSecret(newSecret("mysql-ns", "mysql-secret"), bdc)
+		mapFunc := requestOfAdopter(constants.AnnotationCtxSecretAdopt, constants.AnnotationCtxSecretReferencedSecret)
+		Expect(mapFunc(contextSecret)).Should(Equal([]ctrl.Request{request}))
+		Expect(mapFunc(&bdcv1alpha1.ContextSecret{})).Should(BeEmpty())
+	})
+})
@@ -38,6 +38,10 @@ type Args struct {
 	// ConcurrentReconciles is the concurrent reconcile number of the controller
 	ConcurrentReconciles int
 
+	// DefinitionRolloutRate is the maximum number of resources re-rendered per second when their XDefinition
+	// or the KDP context changes. Zero means no limit.
+	DefinitionRolloutRate int
+
 	// AutoGenWorkloadDefinition indicates whether automatic generated workloadDefinition which componentDefinition refers to
 	AutoGenWorkloadDefinition bool
 
@@ -58,6 +62,7 @@ func (a *Args) AddFlags(fs *pflag.FlagSet, c *Args) {
 		"definition-revision-limit is the maximum number of definition useless revisions that will be maintained, if the useless revisions exceed this number, older ones will be GCed first.The default value is 20.")
 	fs.BoolVar(&a.AutoGenWorkloadDefinition, "autogen-workload-definition", c.AutoGenWorkloadDefinition, "Automatic generated workloadDefinition which componentDefinition refers to.")
 	fs.IntVar(&a.ConcurrentReconciles, "concurrent-reconciles", c.ConcurrentReconciles, "concurrent-reconciles is the concurrent reconcile number of the controller. The default value is 4")
+	fs.IntVar(&a.DefinitionRolloutRate, "definition-rollout-rate", c.DefinitionRolloutRate, "definition-rollout-rate is the maximum number of resources re-rendered per second when their XDefinition or the KDP context changes, 0 means no limit. The default value is 0")
 	fs.BoolVar(&a.IgnoreAppWithoutControllerRequirement, "ignore-app-without-controller-version", c.IgnoreAppWithoutControllerRequirement, "If true, application controller will not defcontext the app without 'app.oam.dev/controller-version-require' annotation")
 	fs.BoolVar(&a.IgnoreDefinitionWithoutControllerRequirement, "ignore-definition-without-controller-version", c.IgnoreDefinitionWithoutControllerRequirement, "If true, definition controller will not defcontext the definition without 'definition.oam.dev/controller-version-require' annotation")
 }
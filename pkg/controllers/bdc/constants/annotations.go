@@ -24,8 +24,18 @@ const (
 	AnnotationLastAppliedConfig = "bdc.kdp.io/last-applied-configuration"
 	// AnnotationDefinitionDescription is the annotation which describe what is the capability used for in a Definition Object
 	AnnotationDefinitionDescription = "definition.bdc.kdp.io/description"
+	// AnnotationDefinitionOverride allows an XDefinition to take over the type and kind of API resource
+	// registered by another XDefinition
+	AnnotationDefinitionOverride = "definition.bdc.kdp.io/override"
 	// AnnotationCtxSettingAdopt is the annotation which describe what is the capability used for in a Context Setting Object
 	AnnotationCtxSettingAdopt = "setting.ctx.bdc.kdp.io/adopt"
+	// AnnotationCtxSettingSyncedHash is the hash of the properties of the adopting ContextSetting and the data of
+	// the ConfigMap when they were last synced, to find out which side is changed since then
+	AnnotationCtxSettingSyncedHash = "setting.ctx.bdc.kdp.io/synced-hash"
+	// AnnotationCtxSecretAdopt marks the ContextSecret adopting an existing Secret, whose values are not copied into it
+	AnnotationCtxSecretAdopt = "secret.ctx.bdc.kdp.io/adopt"
+	// AnnotationCtxSecretReferencedSecret is the name of the Secret adopted by the ContextSecret
+	AnnotationCtxSecretReferencedSecret = "secret.ctx.bdc.kdp.io/referenced-secret"
 
 	AnnotationBDCDefaultNamespace           = "bdc.kdp.io/namespace"
 	AnnotationBDCAlias                      = "bdc.kdp.io/alias"
@@ -34,7 +44,40 @@ const (
 	AnnotationBDCAppliedConfiguration       = "bdc.kdp.io/applied-configuration"
 	AnnotationCtxSettingOrigin              = "setting.ctx.bdc.kdp.io/origin"
 	AnnotationCtxSettingReferencedConfigMap = "setting.ctx.bdc.kdp.io/referenced-configmap"
+	// AnnotationSuspendedComponents records the original scaler traits of the vela application components suspended by a
+	// disabled BigDataCluster
+	AnnotationSuspendedComponents = "bdc.kdp.io/suspended-components"
+	// AnnotationRollbackRevision is the annotation to roll back an Application to the properties of the revision,
+	// the value is the name or the number of the ApplicationRevision
+	AnnotationRollbackRevision = "bdc.kdp.io/rollback-revision"
+	// AnnotationUpdatedBy is the annotation describe the user who made the latest change, set by the apiserver and
+	// recorded in ApplicationRevision
+	AnnotationUpdatedBy = "bdc.kdp.io/updated-by"
+	// AnnotationDefinitionRevision pins an object to a revision of its XDefinition,
+	// the value is the name or the number of the XDefinitionRevision
+	AnnotationDefinitionRevision = "bdc.kdp.io/definition-revision"
+	// AnnotationStableRevision is the XDefinition annotation to keep rendering with the revision until the latest one
+	// is verified, the objects of the canary BigDataClusters are rendered with the latest XDefinition
+	AnnotationStableRevision = "definition.bdc.kdp.io/stable-revision"
+	// AnnotationCanaryBigDataClusters is the XDefinition annotation of the comma separated BigDataClusters
+	// which the latest XDefinition is rolled out to first
+	AnnotationCanaryBigDataClusters = "definition.bdc.kdp.io/canary-bigdataclusters"
+	// AnnotationApplyMode chooses how the rendered manifests are applied, it can be set on a rendered manifest
+	// or the object rendering it, the value is one of three-way-merge, server-side and server-side-force
+	AnnotationApplyMode = "bdc.kdp.io/apply-mode"
+	// AnnotationDefinitionApplyMode is the XDefinition annotation of the default apply mode of its manifests
+	AnnotationDefinitionApplyMode = "definition.bdc.kdp.io/apply-mode"
+	// AnnotationDryRun makes the object rendered but not dispatched when it's "true", the rendered manifests and
+	// their diff against the live objects are stored in the ConfigMap referred by the dryRun status
+	AnnotationDryRun = "bdc.kdp.io/dry-run"
+	// AnnotationPaused stops the reconciliation of the object when it's "true", so that the downstream resources
+	// can be edited by hand without being reverted
+	AnnotationPaused = "bdc.kdp.io/paused"
 
 	// FinalizerResourceTracker finalizer for gc
 	FinalizerResourceTracker = "bdc.kdp.io/resource-tracker-finalizer"
+	// FinalizerCtxSettingAdopt is added to the adopted ConfigMaps, the adopting ContextSettings are deleted along with them
+	FinalizerCtxSettingAdopt = "setting.ctx.bdc.kdp.io/adopt-finalizer"
+	// FinalizerCtxSecretAdopt is added to the adopted Secrets, the adopting ContextSecrets are deleted along with them
+	FinalizerCtxSecretAdopt = "secret.ctx.bdc.kdp.io/adopt-finalizer"
 )
@@ -25,4 +25,12 @@ const (
 	ErrGetBdbFile                                     = "cannot generate XDefinition File: %v"
 	ErrGenerateManifests                              = "cannot generate manifest: %v"
 	ErrGetVelaApplication                             = "cannot get vela application: %v"
+	ErrBigDataClusterNotWritable                      = "bigdatacluster %s is %s, creating or updating its resources is not allowed"
+	ErrSuspendVelaApplication                         = "cannot suspend vela application %s/%s: %v"
+	ErrRollbackRevision                               = "cannot roll back application %s to revision %s: %v"
+	ErrDefinitionRevisionNotFound                     = "revision %s of xdefinition %s not found"
+	ErrNamespaceClaimed                               = "namespace %s is already claimed by bigdatacluster %s"
+	ErrNamespaceHoldsApplications                     = "namespace %s cannot be removed, it still holds applications %s"
+	ErrDefinitionConflict                             = "%s is already registered by xdefinition %s, set annotation %s to \"true\" to override it"
+	ErrApplyConflict                                  = "cannot apply %s %s, fields are managed by others: %s"
 )
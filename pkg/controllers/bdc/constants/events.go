@@ -0,0 +1,51 @@
+/*
+Copyright 2023 KDP(Kubernetes Data Platform).
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package constants
+
+// Reasons of the Events recorded by the controllers, they are shared by all kinds so that alerts can be set up by reason
+const (
+	// EventReasonTemplateLoadFailed means the XDefinition template of the object fails to be loaded
+	EventReasonTemplateLoadFailed = "TemplateLoadFailed"
+	// EventReasonRenderFailed means the manifests fail to be rendered from the template
+	EventReasonRenderFailed = "RenderFailed"
+	// EventReasonApplied means a rendered manifest is applied
+	EventReasonApplied = "Applied"
+	// EventReasonApplyFailed means a rendered manifest fails to be applied
+	EventReasonApplyFailed = "ApplyFailed"
+	// EventReasonPruned means a tracked resource no longer rendered is deleted
+	EventReasonPruned = "Pruned"
+	// EventReasonPruneFailed means a tracked resource no longer rendered fails to be deleted
+	EventReasonPruneFailed = "PruneFailed"
+	// EventReasonFinalizerAdded means the finalizer is added to the object
+	EventReasonFinalizerAdded = "FinalizerAdded"
+	// EventReasonFinalizing means the downstream resources of the deleting object are being deleted
+	EventReasonFinalizing = "Finalizing"
+	// EventReasonFinalizeFailed means the downstream resources of the deleting object fail to be deleted
+	EventReasonFinalizeFailed = "FinalizeFailed"
+	// EventReasonFinalized means the finalizer is removed after the downstream resources are deleted
+	EventReasonFinalized = "Finalized"
+	// EventReasonVelaStatusChanged means the phase of the vela application of an Application changes
+	EventReasonVelaStatusChanged = "VelaStatusChanged"
+	// EventReasonAdopted means an existing ConfigMap or Secret is adopted into a ContextSetting or ContextSecret
+	EventReasonAdopted = "Adopted"
+	// EventReasonAdoptFailed means an existing ConfigMap or Secret fails to be adopted
+	EventReasonAdoptFailed = "AdoptFailed"
+	// EventReasonSyncedBack means the changed properties of an adopting ContextSetting are written back to the ConfigMap
+	EventReasonSyncedBack = "SyncedBack"
+	// EventReasonSyncConflict means both the adopting ContextSetting and the ConfigMap are changed since last synced
+	EventReasonSyncConflict = "SyncConflict"
+)
@@ -28,7 +28,17 @@ const (
 	LabelBDCName        = AnnotationBDCName
 	LabelOrgName        = AnnotationOrgName
 	LabelName           = "terminal.bdc.kdp.io/name"
+	// LabelAppRevisionOf is the label for the Application name of an ApplicationRevision
+	LabelAppRevisionOf = "revision.app.bdc.kdp.io/application"
+	// LabelDefRevisionOf is the label for the XDefinition name of an XDefinitionRevision
+	LabelDefRevisionOf = "revision.definition.bdc.kdp.io/name"
+	// LabelDryRunKind is the label for the kind of the object a dry-run ConfigMap records
+	LabelDryRunKind = "dry-run.bdc.kdp.io/kind"
 
 	AnnotationCtxSettingSource = "setting.ctx.bdc.kdp.io/source"
 	AnnotationCtxSettingType   = "setting.ctx.bdc.kdp.io/type"
+	// AnnotationCtxSecretSource labels the Secrets to be adopted into ContextSecrets with "secret"
+	AnnotationCtxSecretSource = "secret.ctx.bdc.kdp.io/source"
+	// AnnotationCtxSecretType is the type of the ContextSecret adopting the Secret, "default" if it's not set
+	AnnotationCtxSecretType = "secret.ctx.bdc.kdp.io/type"
 )
@@ -20,6 +20,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"regexp"
+	"sort"
 	"strings"
 	"unicode"
 )
@@ -95,6 +97,15 @@ func (ctx *ContextData) BaseContextFile() (string, error) {
 	return fmt.Sprintf("%s: %s", Context, structMarshal(buff)), nil
 }
 
+// BaseContextData returns a copy of the data of context, which is the same as the one rendered by BaseContextFile
+func (ctx *ContextData) BaseContextData() map[string]interface{} {
+	data := make(map[string]interface{}, len(ctx.data))
+	for k, v := range ctx.data {
+		data[k] = v
+	}
+	return data
+}
+
 func structMarshal(v string) string {
 	skip := false
 	v = strings.TrimFunc(v, func(r rune) bool {
@@ -113,3 +124,37 @@ func structMarshal(v string) string {
 	}
 	return fmt.Sprintf("{%s}", v)
 }
+
+var referredKeyPattern = regexp.MustCompile(`\bcontext(?:\.([A-Za-z_$][A-Za-z0-9_$]*)|\[\s*"([^"]+)"\s*\])`)
+
+// builtinKeys are the keys of context generated by controller rather than the KDP context ConfigMaps
+var builtinKeys = map[string]bool{
+	ContextName:           true,
+	ContextNamespace:      true,
+	ContextBDCName:        true,
+	ContextBDCLabels:      true,
+	ContextBDCAnnotations: true,
+	AppUuid:               true,
+	Group:                 true,
+	Bdc:                   true,
+}
+
+// ReferredKeys returns the sorted keys of the KDP context ConfigMaps referred by template,
+// such as context.domain or context["ingress.class"]
+func ReferredKeys(template string) []string {
+	seen := map[string]bool{}
+	var keys []string
+	for _, match := range referredKeyPattern.FindAllStringSubmatch(template, -1) {
+		key := match[1]
+		if key == "" {
+			key = match[2]
+		}
+		if builtinKeys[key] || seen[key] {
+			continue
+		}
+		seen[key] = true
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -285,3 +285,39 @@ func TestNewBDCContext(t *testing.T) {
 		})
 	}
 }
+
+func TestReferredKeys(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		want     []string
+	}{
+		{
+			name: "selector and index",
+			template: `output: {
+	metadata: namespace: context.namespace
+	spec: host: "app." + context.domain
+	spec: class: context["ingress.class"]
+	spec: domain: context.domain
+}`,
+			want: []string{"domain", "ingress.class"},
+		},
+		{
+			name:     "builtin keys only",
+			template: `output: metadata: {name: context.name, labels: context.bdcLabels}`,
+			want:     nil,
+		},
+		{
+			name:     "not context",
+			template: `output: spec: value: mycontext.domain`,
+			want:     nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ReferredKeys(tt.template); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ReferredKeys() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
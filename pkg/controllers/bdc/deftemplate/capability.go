@@ -146,11 +146,7 @@ func (def *CapabilityBaseDefinition) CreateOrUpdateConfigMap(ctx context.Context
 }
 
 func getSchemas(capability common.Capability) ([]byte, []byte, error) {
-	openAPISchema, err := generateOpenAPISchemaFromCapabilityParameter(capability)
-	if err != nil {
-		return nil, nil, err
-	}
-	schema, err := ConvertOpenAPISchema2SwaggerObject(openAPISchema)
+	schema, err := parameterSchema(capability)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -166,6 +162,19 @@ func getSchemas(capability common.Capability) ([]byte, []byte, error) {
 
 }
 
+// parameterSchema returns the schema of parameter, which is generated from the CUE template,
+// or the JSON Schema of Go template schematic
+func parameterSchema(capability common.Capability) (*openapi3.Schema, error) {
+	if capability.Category == common.GoTemplateCategory {
+		return ParseParameterSchema(capability.ParameterSchema)
+	}
+	openAPISchema, err := generateOpenAPISchemaFromCapabilityParameter(capability)
+	if err != nil {
+		return nil, err
+	}
+	return ConvertOpenAPISchema2SwaggerObject(openAPISchema)
+}
+
 const BaseTemplate = `
 context: {
  name: string
@@ -0,0 +1,170 @@
+/*
+Copyright 2023 KDP(Kubernetes Data Platform).
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deftemplate
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/Masterminds/sprig/v3"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/pkg/errors"
+	"helm.sh/helm/v3/pkg/releaseutil"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"kdp-oam-operator/pkg/controllers/bdc/defcontext"
+	"sigs.k8s.io/yaml"
+)
+
+// ParseGoTemplate parses the Go template of schematic, the sprig functions and toYaml are available
+func ParseGoTemplate(name, text string) (*template.Template, error) {
+	funcs := sprig.TxtFuncMap()
+	funcs["toYaml"] = toYaml
+	return template.New(name).Funcs(funcs).Option("missingkey=zero").Parse(text)
+}
+
+// toYaml marshals v into YAML without the trailing newline, it returns an empty string on error as Helm does
+func toYaml(v interface{}) string {
+	data, err := yaml.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSuffix(string(data), "\n")
+}
+
+// ParseParameterSchema parses the JSON Schema of parameter in JSON or YAML, an empty schema accepts any object
+func ParseParameterSchema(schema string) (*openapi3.Schema, error) {
+	if strings.TrimSpace(schema) == "" {
+		return openapi3.NewObjectSchema(), nil
+	}
+	data, err := yaml.YAMLToJSON([]byte(schema))
+	if err != nil {
+		return nil, errors.WithMessage(err, "parse schema of parameter")
+	}
+	paramSchema := &openapi3.Schema{}
+	if err := paramSchema.UnmarshalJSON(data); err != nil {
+		return nil, errors.WithMessage(err, "parse schema of parameter")
+	}
+	return paramSchema, nil
+}
+
+// RenderGoTemplate renders the Go template over .parameter and .context, the default values of schema are set
+// to parameter before rendering. The first manifest is the output, the others are named after their kinds.
+func (wd *BigDataClusterDef) RenderGoTemplate(ctx defcontext.ContextData, abstractTemplate string, schema string, params interface{}) ([]*unstructured.Unstructured, error) {
+	parameter := map[string]interface{}{}
+	if params != nil {
+		bt, err := json.Marshal(params)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "marshal parameter of workload %s", wd.name)
+		}
+		if string(bt) != "null" {
+			if err := json.Unmarshal(bt, &parameter); err != nil {
+				return nil, errors.WithMessagef(err, "unmarshal parameter of workload %s", wd.name)
+			}
+		}
+	}
+	paramSchema, err := ParseParameterSchema(schema)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "invalid schema of workload %s", wd.name)
+	}
+	if err := paramSchema.VisitJSON(parameter, openapi3.VisitAsRequest(), openapi3.DefaultsSet(func() {})); err != nil {
+		return nil, errors.WithMessagef(err, "invalid parameter of workload %s", wd.name)
+	}
+
+	tmpl, err := ParseGoTemplate(wd.name, abstractTemplate)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "parse template of workload %s", wd.name)
+	}
+	var out bytes.Buffer
+	data := map[string]interface{}{
+		ParameterFieldName: parameter,
+		defcontext.Context: ctx.BaseContextData(),
+	}
+	if err := tmpl.Execute(&out, data); err != nil {
+		return nil, errors.WithMessagef(err, "execute template of workload %s", wd.name)
+	}
+	// the missing values are rendered as empty as Helm does
+	rendered := strings.ReplaceAll(out.String(), "<no value>", "")
+
+	docs := releaseutil.SplitManifests(rendered)
+	keys := make([]string, 0, len(docs))
+	for key := range docs {
+		keys = append(keys, key)
+	}
+	sort.Sort(releaseutil.BySplitManifestsOrder(keys))
+
+	var workloads []*unstructured.Unstructured
+	var names outputNamer
+	for _, key := range keys {
+		obj, err := manifestToUnstructured(docs[key])
+		if err != nil {
+			return nil, errors.WithMessagef(err, "parse manifest of workload %s", wd.name)
+		}
+		if obj == nil {
+			continue
+		}
+		workloads = append(workloads, obj)
+		names.add(strings.ToLower(obj.GetKind()))
+	}
+	if len(workloads) == 0 {
+		return nil, errors.Errorf("no manifest is rendered by the template of workload %s", wd.name)
+	}
+	wd.outputNames = names.names
+
+	return workloads, nil
+}
+
+// manifestToUnstructured parses a YAML manifest, it returns nil if the manifest is empty
+func manifestToUnstructured(manifest string) (*unstructured.Unstructured, error) {
+	data, err := yaml.YAMLToJSON([]byte(manifest))
+	if err != nil {
+		return nil, err
+	}
+	if string(data) == "null" {
+		return nil, nil
+	}
+	obj := &unstructured.Unstructured{}
+	if err := obj.UnmarshalJSON(data); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// outputNamer names the rendered manifests, the first one is always "output",
+// the others are named after base names with a number suffix for the duplicated ones
+type outputNamer struct {
+	names []string
+	seen  map[string]int
+}
+
+func (n *outputNamer) add(base string) {
+	if len(n.names) == 0 {
+		n.names = append(n.names, OutputFieldName)
+		return
+	}
+	if n.seen == nil {
+		n.seen = map[string]int{}
+	}
+	n.seen[base]++
+	if n.seen[base] > 1 {
+		base = fmt.Sprintf("%s-%d", base, n.seen[base])
+	}
+	n.names = append(n.names, base)
+}
@@ -0,0 +1,158 @@
+package deftemplate
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"kdp-oam-operator/api/bdc/common"
+	bdcv1alpha1 "kdp-oam-operator/api/bdc/v1alpha1"
+	"kdp-oam-operator/pkg/controllers/bdc/defcontext"
+)
+
+var testGoTemplate = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: {{ .context.name }}
+  namespace: {{ .context.namespace }}
+  labels:
+    {{- toYaml .parameter.labels | nindent 4 }}
+spec:
+  replicas: {{ .parameter.replicas }}
+---
+# the service is optional
+{{- if .parameter.expose }}
+apiVersion: v1
+kind: Service
+metadata:
+  name: {{ .context.name }}-svc
+  namespace: {{ .context.namespace }}
+{{- end }}
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ .context.name | upper }}
+  namespace: {{ .context.namespace }}
+data:
+  domain: {{ .context.domain | quote }}
+  missing: "{{ .parameter.missing }}"
+`
+
+var testGoTemplateSchema = `
+type: object
+properties:
+  replicas:
+    type: integer
+    default: 1
+    description: "+ui:description=副本数\n+ui:order=1"
+  expose:
+    type: boolean
+    default: false
+  labels:
+    type: object
+    additionalProperties:
+      type: string
+`
+
+func TestBigDataClusterDef_RenderGoTemplate(t *testing.T) {
+	contextData := defcontext.NewBDCContext(defcontext.ContextData{Namespace: "kdp-test", Name: "hive", BDCName: "kdp-bdc-name"})
+	contextData.PushData("domain", "kdp.io")
+
+	tests := []struct {
+		name      string
+		params    interface{}
+		wantKinds []string
+		wantNames []string
+		wantErr   bool
+	}{
+		{
+			name:      "defaults",
+			params:    map[string]interface{}{"labels": map[string]interface{}{"app": "hive"}},
+			wantKinds: []string{"Deployment", "ConfigMap"},
+			wantNames: []string{"output", "configmap"},
+		},
+		{
+			name:      "exposed",
+			params:    map[string]interface{}{"replicas": 2, "expose": true},
+			wantKinds: []string{"Deployment", "Service", "ConfigMap"},
+			wantNames: []string{"output", "service", "configmap"},
+		},
+		{
+			name:    "invalid parameter",
+			params:  map[string]interface{}{"replicas": "two"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wd := &BigDataClusterDef{def: def{name: "hive-app"}}
+			manifests, err := wd.RenderGoTemplate(contextData, testGoTemplate, testGoTemplateSchema, tt.params)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RenderGoTemplate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			var kinds []string
+			for _, m := range manifests {
+				kinds = append(kinds, m.GetKind())
+				if m.GetNamespace() != "kdp-test" {
+					t.Errorf("RenderGoTemplate() namespace of %s = %s", m.GetKind(), m.GetNamespace())
+				}
+			}
+			if !reflect.DeepEqual(kinds, tt.wantKinds) {
+				t.Errorf("RenderGoTemplate() kinds = %v, want %v", kinds, tt.wantKinds)
+			}
+			if !reflect.DeepEqual(wd.OutputNames(), tt.wantNames) {
+				t.Errorf("OutputNames() = %v, want %v", wd.OutputNames(), tt.wantNames)
+			}
+
+			deployment := manifests[0].Object
+			replicas := deployment["spec"].(map[string]interface{})["replicas"]
+			if want := tt.params.(map[string]interface{})["replicas"]; want == nil && replicas != int64(1) || want != nil && replicas != int64(want.(int)) {
+				t.Errorf("RenderGoTemplate() replicas = %v", replicas)
+			}
+			configMap := manifests[len(manifests)-1]
+			if configMap.GetName() != "HIVE" {
+				t.Errorf("RenderGoTemplate() configmap name = %s, want HIVE", configMap.GetName())
+			}
+			data := configMap.Object["data"].(map[string]interface{})
+			if data["domain"] != "kdp.io" || data["missing"] != "" {
+				t.Errorf("RenderGoTemplate() configmap data = %v", data)
+			}
+		})
+	}
+}
+
+func TestCapabilityDefinition_GoTemplateSchema(t *testing.T) {
+	xDefinition := &bdcv1alpha1.XDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "hive-def"},
+		Spec: bdcv1alpha1.XDefinitionSpec{Schematic: &common.Schematic{GoTemplate: &common.GoTemplate{
+			Template: testGoTemplate,
+			Schema:   testGoTemplateSchema,
+		}}},
+	}
+	def := NewCapabilityXDef(xDefinition)
+	jsonSchema, uiSchema, err := def.GetOpenAPIAndUischemaSchema(xDefinition.Name)
+	if err != nil {
+		t.Fatalf("GetOpenAPIAndUischemaSchema() error = %v", err)
+	}
+
+	var schema map[string]interface{}
+	if err := json.Unmarshal(jsonSchema, &schema); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	replicas := schema["properties"].(map[string]interface{})["replicas"].(map[string]interface{})
+	if replicas["type"] != "integer" || replicas["default"] != float64(1) {
+		t.Errorf("GetOpenAPIAndUischemaSchema() replicas = %v", replicas)
+	}
+	var ui map[string]interface{}
+	if err := json.Unmarshal(uiSchema, &ui); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if ui["ui:order"] == nil {
+		t.Errorf("GetOpenAPIAndUischemaSchema() ui schema = %s, want the order of replicas", uiSchema)
+	}
+}
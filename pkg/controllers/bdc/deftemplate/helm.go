@@ -0,0 +1,155 @@
+/*
+Copyright 2023 KDP(Kubernetes Data Platform).
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deftemplate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/engine"
+	"helm.sh/helm/v3/pkg/releaseutil"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2"
+	"kdp-oam-operator/api/bdc/common"
+	pkgcommon "kdp-oam-operator/pkg/common"
+	"kdp-oam-operator/pkg/controllers/bdc/defcontext"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// LoadHelmChart loads the chart of the Helm schematic, either from a local chart directory or archive,
+// or from a chart archive stored in a ConfigMap
+func LoadHelmChart(ctx context.Context, cli client.Reader, helm *common.Helm) (*chart.Chart, error) {
+	if ref := helm.ChartArchive; ref != nil {
+		namespace := ref.Namespace
+		if namespace == "" {
+			namespace = pkgcommon.SystemDefaultNamespace
+		}
+		var cm v1.ConfigMap
+		if err := cli.Get(ctx, client.ObjectKey{Namespace: namespace, Name: ref.Name}, &cm); err != nil {
+			return nil, errors.WithMessagef(err, "get chart archive configmap %s/%s", namespace, ref.Name)
+		}
+		archive, ok := cm.BinaryData[ref.Key]
+		if !ok {
+			return nil, errors.Errorf("chart archive %s not found in configmap %s/%s", ref.Key, namespace, ref.Name)
+		}
+		chrt, err := loader.LoadArchive(bytes.NewReader(archive))
+		if err != nil {
+			return nil, errors.WithMessagef(err, "load chart archive %s of configmap %s/%s", ref.Key, namespace, ref.Name)
+		}
+		return chrt, nil
+	}
+	if helm.Chart == "" {
+		return nil, errors.New("either chart or chartArchive of helm schematic is required")
+	}
+	chrt, err := loader.Load(helm.Chart)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "load chart %s", helm.Chart)
+	}
+	return chrt, nil
+}
+
+// chartSource returns the content of all templates of chrt and its dependencies,
+// which is used to find the KDP context keys referred by the chart
+func chartSource(chrt *chart.Chart) string {
+	var source strings.Builder
+	for _, tmpl := range chrt.Templates {
+		source.Write(tmpl.Data)
+		source.WriteString("\n")
+	}
+	for _, dep := range chrt.Dependencies() {
+		source.WriteString(chartSource(dep))
+	}
+	return source.String()
+}
+
+// RenderHelmChart renders the chart with the values overridden by params, the context is exposed as .Values.context.
+// The hooks and CRDs of chart are not rendered, the manifests are sorted in the order Helm installs them,
+// and the outputs are named after the template files.
+func (wd *BigDataClusterDef) RenderHelmChart(ctx defcontext.ContextData, chrt *chart.Chart, values *runtime.RawExtension, params interface{}) ([]*unstructured.Unstructured, error) {
+	if chrt == nil {
+		return nil, errors.Errorf("chart of workload %s is not loaded", wd.name)
+	}
+	vals := map[string]interface{}{}
+	if values != nil && values.Raw != nil {
+		if err := json.Unmarshal(values.Raw, &vals); err != nil {
+			return nil, errors.WithMessagef(err, "unmarshal values of workload %s", wd.name)
+		}
+	}
+	if params != nil {
+		bt, err := json.Marshal(params)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "marshal parameter of workload %s", wd.name)
+		}
+		paramVals := map[string]interface{}{}
+		if err := json.Unmarshal(bt, &paramVals); err != nil {
+			return nil, errors.WithMessagef(err, "unmarshal parameter of workload %s", wd.name)
+		}
+		vals = chartutil.CoalesceTables(paramVals, vals)
+	}
+	vals[defcontext.Context] = ctx.BaseContextData()
+
+	releaseName, _ := ctx.GetData(defcontext.ContextName).(string)
+	if releaseName == "" {
+		releaseName = wd.name
+	}
+	options := chartutil.ReleaseOptions{Name: releaseName, Namespace: ctx.Namespace, Revision: 1, IsInstall: true}
+	renderVals, err := chartutil.ToRenderValues(chrt, vals, options, chartutil.DefaultCapabilities)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "invalid values of workload %s", wd.name)
+	}
+	files, err := engine.Render(chrt, renderVals)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "render chart of workload %s", wd.name)
+	}
+	hooks, manifests, err := releaseutil.SortManifests(files, chartutil.DefaultVersionSet, releaseutil.InstallOrder)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "parse manifests of workload %s", wd.name)
+	}
+	if len(hooks) > 0 {
+		klog.InfoS("Helm hooks are not supported, skipped", "workload", wd.name, "hooks", len(hooks))
+	}
+
+	var workloads []*unstructured.Unstructured
+	var names outputNamer
+	for _, manifest := range manifests {
+		obj, err := manifestToUnstructured(manifest.Content)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "parse manifest %s of workload %s", manifest.Name, wd.name)
+		}
+		if obj == nil {
+			continue
+		}
+		// Helm installs the resources without namespace into the namespace of release
+		if obj.GetNamespace() == "" {
+			obj.SetNamespace(ctx.Namespace)
+		}
+		workloads = append(workloads, obj)
+		names.add(strings.TrimSuffix(path.Base(manifest.Name), path.Ext(manifest.Name)))
+	}
+	wd.outputNames = names.names
+
+	return workloads, nil
+}
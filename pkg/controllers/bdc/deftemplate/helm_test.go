@@ -0,0 +1,174 @@
+package deftemplate
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"testing"
+
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"kdp-oam-operator/api/bdc/common"
+	bdcv1alpha1 "kdp-oam-operator/api/bdc/v1alpha1"
+	pkgcommon "kdp-oam-operator/pkg/common"
+	"kdp-oam-operator/pkg/controllers/bdc/defcontext"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func testChart() *chart.Chart {
+	return &chart.Chart{
+		Metadata: &chart.Metadata{APIVersion: chart.APIVersionV2, Name: "hdfs", Version: "0.1.0"},
+		Values:   map[string]interface{}{"replicas": 1, "image": "hdfs:3.1", "port": 8020},
+		Templates: []*chart.File{
+			{Name: "templates/_helpers.tpl", Data: []byte(`{{- define "hdfs.name" -}}{{ .Release.Name }}-hdfs{{- end -}}`)},
+			{Name: "templates/deployment.yaml", Data: []byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: {{ include "hdfs.name" . }}
+spec:
+  replicas: {{ .Values.replicas }}
+  template:
+    spec:
+      containers:
+      - name: hdfs
+        image: {{ .Values.image }}
+`)},
+			{Name: "templates/configmap.yaml", Data: []byte(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ include "hdfs.name" . }}-config
+  namespace: {{ .Release.Namespace }}
+data:
+  port: {{ .Values.port | quote }}
+  domain: {{ .Values.context.domain }}
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ include "hdfs.name" . }}-extra
+`)},
+			{Name: "templates/test.yaml", Data: []byte(`apiVersion: v1
+kind: Pod
+metadata:
+  name: {{ include "hdfs.name" . }}-test
+  annotations:
+    helm.sh/hook: test
+`)},
+		},
+	}
+}
+
+func TestBigDataClusterDef_RenderHelmChart(t *testing.T) {
+	contextData := defcontext.NewBDCContext(defcontext.ContextData{Namespace: "kdp-test", Name: "hdfs", BDCName: "kdp-bdc-name"})
+	contextData.PushData("domain", "kdp.io")
+
+	wd := &BigDataClusterDef{def: def{name: "hdfs-app"}}
+	manifests, err := wd.RenderHelmChart(contextData, testChart(),
+		&runtime.RawExtension{Raw: []byte(`{"image":"hdfs:3.2","port":9000}`)},
+		map[string]interface{}{"replicas": 3, "port": 9820})
+	if err != nil {
+		t.Fatalf("RenderHelmChart() error = %v", err)
+	}
+
+	// ConfigMaps are installed before Deployment, and the test hook is skipped
+	var got []string
+	for _, m := range manifests {
+		got = append(got, m.GetKind()+"/"+m.GetNamespace()+"/"+m.GetName())
+	}
+	want := []string{"ConfigMap/kdp-test/hdfs-hdfs-config", "ConfigMap/kdp-test/hdfs-hdfs-extra", "Deployment/kdp-test/hdfs-hdfs"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("RenderHelmChart() manifests = %v, want %v", got, want)
+	}
+	if names := wd.OutputNames(); !reflect.DeepEqual(names, []string{"output", "configmap", "deployment"}) {
+		t.Errorf("OutputNames() = %v", names)
+	}
+
+	data := manifests[0].Object["data"].(map[string]interface{})
+	if data["port"] != "9820" || data["domain"] != "kdp.io" {
+		t.Errorf("RenderHelmChart() configmap data = %v, want the port of parameter and the domain of context", data)
+	}
+	spec := manifests[2].Object["spec"].(map[string]interface{})
+	if spec["replicas"] != int64(3) {
+		t.Errorf("RenderHelmChart() replicas = %v, want 3", spec["replicas"])
+	}
+	containers := spec["template"].(map[string]interface{})["spec"].(map[string]interface{})["containers"].([]interface{})
+	if image := containers[0].(map[string]interface{})["image"]; image != "hdfs:3.2" {
+		t.Errorf("RenderHelmChart() image = %v, want the one of values", image)
+	}
+
+	if keys := defcontext.ReferredKeys(chartSource(testChart())); !reflect.DeepEqual(keys, []string{"domain"}) {
+		t.Errorf("ReferredKeys() = %v, want [domain]", keys)
+	}
+}
+
+func TestBigDataClusterDef_RenderHelmChartReleaseName(t *testing.T) {
+	// the release is named after the workload if the context has no name
+	contextData := defcontext.ContextData{Namespace: "kdp-test"}
+	contextData.PushData("domain", "kdp.io")
+
+	wd := &BigDataClusterDef{def: def{name: "hdfs-app"}}
+	manifests, err := wd.RenderHelmChart(contextData, testChart(), nil, nil)
+	if err != nil {
+		t.Fatalf("RenderHelmChart() error = %v", err)
+	}
+	if name := manifests[len(manifests)-1].GetName(); name != "hdfs-app-hdfs" {
+		t.Errorf("RenderHelmChart() deployment name = %q, want the release named after the workload", name)
+	}
+}
+
+func TestLoadHelmChart(t *testing.T) {
+	dir := t.TempDir()
+	archivePath, err := chartutil.Save(testChart(), dir)
+	if err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	archive, err := os.ReadFile(archivePath)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "hdfs-chart", Namespace: pkgcommon.SystemDefaultNamespace},
+		BinaryData: map[string][]byte{"hdfs-0.1.0.tgz": archive},
+	}).Build()
+
+	tests := []struct {
+		name    string
+		helm    *common.Helm
+		wantErr bool
+	}{
+		{name: "local archive", helm: &common.Helm{Chart: archivePath}},
+		{name: "configmap archive", helm: &common.Helm{ChartArchive: &common.ChartArchiveReference{Name: "hdfs-chart", Key: "hdfs-0.1.0.tgz"}}},
+		{name: "key not found", helm: &common.Helm{ChartArchive: &common.ChartArchiveReference{Name: "hdfs-chart", Key: "hive-0.1.0.tgz"}}, wantErr: true},
+		{name: "configmap not found", helm: &common.Helm{ChartArchive: &common.ChartArchiveReference{Name: "hive-chart", Key: "hive-0.1.0.tgz"}}, wantErr: true},
+		{name: "no chart", helm: &common.Helm{}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chrt, err := LoadHelmChart(context.Background(), cli, tt.helm)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadHelmChart() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && chrt.Name() != "hdfs" {
+				t.Errorf("LoadHelmChart() chart = %s, want hdfs", chrt.Name())
+			}
+		})
+	}
+}
+
+func TestCapabilityDefinition_HelmSchema(t *testing.T) {
+	xDefinition := &bdcv1alpha1.XDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "hdfs-def"},
+		Spec:       bdcv1alpha1.XDefinitionSpec{Schematic: &common.Schematic{Helm: &common.Helm{Chart: "/charts/hdfs"}}},
+	}
+	def := NewCapabilityXDef(xDefinition)
+	if _, _, err := def.GetOpenAPIAndUischemaSchema(xDefinition.Name); err != nil {
+		t.Errorf("GetOpenAPIAndUischemaSchema() error = %v", err)
+	}
+}
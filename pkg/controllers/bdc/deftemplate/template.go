@@ -30,12 +30,15 @@ import (
 	"kdp-oam-operator/api/bdc/common"
 	bdcv1alpha1 "kdp-oam-operator/api/bdc/v1alpha1"
 	pkgcommon "kdp-oam-operator/pkg/common"
+	"kdp-oam-operator/pkg/controllers/bdc/constants"
 	defcontext "kdp-oam-operator/pkg/controllers/bdc/defcontext"
+	"kdp-oam-operator/pkg/controllers/utils/defrevision"
 	cueutil "kdp-oam-operator/pkg/cue"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"strings"
 
 	"github.com/pkg/errors"
+	"helm.sh/helm/v3/pkg/chart"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -63,6 +66,22 @@ type DefinitionTemplate struct {
 	SchematicCategory     common.SchematicCategory
 	XDefinition           *bdcv1alpha1.XDefinition
 	XDefinitionSchemaName string
+	// XDefinitionRevision is the name of XDefinitionRevision the template loaded from, empty for the latest XDefinition
+	XDefinitionRevision string
+	// Helm is the Helm schematic of XDefinition, and HelmChart is the chart loaded from it
+	Helm      *common.Helm
+	HelmChart *chart.Chart
+	// GoTemplate is the Go template schematic of XDefinition, the template is also set to TemplateStr
+	GoTemplate *common.GoTemplate
+}
+
+// ContextSource returns the source of template which may refer to the KDP context,
+// that is the CUE or Go template, or the templates of Helm chart
+func (t *DefinitionTemplate) ContextSource() string {
+	if t.SchematicCategory == common.HelmCategory && t.HelmChart != nil {
+		return chartSource(t.HelmChart)
+	}
+	return t.TemplateStr
 }
 
 type TemplateLoaderFn func(context.Context, client.Reader, string, string) (*DefinitionTemplate, error)
@@ -86,9 +105,17 @@ func ConvertTemplateJSON2Object(capabilityName string, in *runtime.RawExtension,
 	if err := loadSchematicToTemplate(capTemplate, nil, schematic); err != nil {
 		return t, errors.WithMessage(err, "cannot resolve schematic")
 	}
-	if capTemplate.TemplateStr != "" {
+	if capTemplate.SchematicCategory == common.CUECategory && capTemplate.TemplateStr != "" {
 		t.CueTemplate = capTemplate.TemplateStr
 	}
+	if capTemplate.GoTemplate != nil {
+		t.Category = common.GoTemplateCategory
+		t.ParameterSchema = capTemplate.GoTemplate.Schema
+	}
+	if capTemplate.Helm != nil {
+		t.Category = common.HelmCategory
+		t.Source = helmSource(capTemplate.Helm)
+	}
 	return t, nil
 }
 
@@ -109,6 +136,34 @@ func LoadTemplate(ctx context.Context, cli client.Reader, objKind string, refDef
 	if err != nil {
 		return nil, err
 	}
+	if err := loadHelmChart(ctx, cli, tmpl); err != nil {
+		return nil, err
+	}
+	return tmpl, nil
+}
+
+// LoadRevisionTemplate loads the template from the revision of xDefinition referred by ref,
+// which is either the name or the number of the XDefinitionRevision.
+func LoadRevisionTemplate(ctx context.Context, cli client.Reader, xDefinition *bdcv1alpha1.XDefinition, ref string) (*DefinitionTemplate, error) {
+	revisions, err := defrevision.List(ctx, cli, xDefinition.Name)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "load revisions of definition")
+	}
+	revision := defrevision.Find(revisions, ref)
+	if revision == nil {
+		return nil, errors.Errorf(constants.ErrDefinitionRevisionNotFound, ref, xDefinition.Name)
+	}
+	bdcDef := xDefinition.DeepCopy()
+	bdcDef.Spec = *revision.Spec.Definition.DeepCopy()
+	bdcDef.Generation = revision.Spec.DefinitionGeneration
+	tmpl, err := newTemplateOfXDefinition(bdcDef)
+	if err != nil {
+		return nil, err
+	}
+	if err := loadHelmChart(ctx, cli, tmpl); err != nil {
+		return nil, err
+	}
+	tmpl.XDefinitionRevision = revision.Name
 	return tmpl, nil
 }
 
@@ -135,11 +190,45 @@ func loadSchematicToTemplate(tmpl *DefinitionTemplate, status *common.Status, sc
 			tmpl.SchematicCategory = common.CUECategory
 			tmpl.TemplateStr = schematic.CUE.Template
 		}
+		if schematic.Helm != nil {
+			tmpl.SchematicCategory = common.HelmCategory
+			tmpl.Helm = schematic.Helm
+		}
+		if schematic.GoTemplate != nil {
+			tmpl.SchematicCategory = common.GoTemplateCategory
+			tmpl.GoTemplate = schematic.GoTemplate
+			tmpl.TemplateStr = schematic.GoTemplate.Template
+		}
+
+	}
+	return nil
+}
 
+// loadHelmChart loads the chart of template if it is rendered by Helm
+func loadHelmChart(ctx context.Context, cli client.Reader, tmpl *DefinitionTemplate) error {
+	if tmpl.SchematicCategory != common.HelmCategory {
+		return nil
 	}
+	chrt, err := LoadHelmChart(ctx, cli, tmpl.Helm)
+	if err != nil {
+		return errors.WithMessage(err, "cannot load helm chart")
+	}
+	tmpl.HelmChart = chrt
 	return nil
 }
 
+// helmSource returns the source of capability rendered by the Helm schematic
+func helmSource(helm *common.Helm) *common.Source {
+	if ref := helm.ChartArchive; ref != nil {
+		namespace := ref.Namespace
+		if namespace == "" {
+			namespace = pkgcommon.SystemDefaultNamespace
+		}
+		return &common.Source{RepoName: fmt.Sprintf("configmap/%s/%s", namespace, ref.Name), ChartName: ref.Key}
+	}
+	return &common.Source{RepoName: "local", ChartName: helm.Chart}
+}
+
 type ApiResourceDefMap struct {
 	XDefName        string                  `json:"name"`
 	XDefinition     bdcv1alpha1.XDefinition `json:"xDefinition"`
@@ -202,6 +291,7 @@ type def struct {
 
 type BigDataClusterDef struct {
 	def
+	outputNames []string
 }
 
 func NewBigDataClusterDefAbstractEngine(name string) AbstractEngine {
@@ -214,6 +304,17 @@ func NewBigDataClusterDefAbstractEngine(name string) AbstractEngine {
 
 type AbstractEngine interface {
 	RenderCUETemplate(ctx defcontext.ContextData, abstractTemplate string, params interface{}) ([]*unstructured.Unstructured, error)
+	// RenderHelmChart renders the Helm chart into the same manifests as RenderCUETemplate
+	RenderHelmChart(ctx defcontext.ContextData, chrt *chart.Chart, values *runtime.RawExtension, params interface{}) ([]*unstructured.Unstructured, error)
+	// RenderGoTemplate renders the Go template into the same manifests as RenderCUETemplate
+	RenderGoTemplate(ctx defcontext.ContextData, abstractTemplate string, schema string, params interface{}) ([]*unstructured.Unstructured, error)
+	// OutputNames returns the names of outputs rendered by the last render in the same order,
+	// the first one is always "output" and the others are the field names under "outputs"
+	OutputNames() []string
+}
+
+func (wd *BigDataClusterDef) OutputNames() []string {
+	return wd.outputNames
 }
 
 func (wd *BigDataClusterDef) RenderCUETemplate(ctx defcontext.ContextData, abstractTemplate string, params interface{}) ([]*unstructured.Unstructured, error) {
@@ -259,6 +360,7 @@ func (wd *BigDataClusterDef) RenderCUETemplate(ctx defcontext.ContextData, abstr
 
 	var finalOutputs []cue.Value
 	finalOutputs = append(finalOutputs, output)
+	outputNames := []string{OutputFieldName}
 
 	iter, err := outputs.Fields(cue.Definitions(true), cue.Hidden(true), cue.All())
 	if err != nil {
@@ -266,6 +368,7 @@ func (wd *BigDataClusterDef) RenderCUETemplate(ctx defcontext.ContextData, abstr
 	}
 	for iter.Next() {
 		finalOutputs = append(finalOutputs, iter.Value())
+		outputNames = append(outputNames, iter.Label())
 	}
 
 	err = v.Err()
@@ -283,6 +386,7 @@ func (wd *BigDataClusterDef) RenderCUETemplate(ctx defcontext.ContextData, abstr
 		}
 		workloads = append(workloads, unstructuredOutput)
 	}
+	wd.outputNames = outputNames
 
 	return workloads, nil
 }
@@ -0,0 +1,192 @@
+/*
+Copyright 2023 KDP(Kubernetes Data Platform).
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/cuecontext"
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	conditiontype "kdp-oam-operator/api/bdc/condition"
+	"kdp-oam-operator/pkg/controllers/bdc/deftemplate"
+	"kdp-oam-operator/pkg/controllers/bdc/parser"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// HealthCheckPolicy is the field name of health policy result
+	HealthCheckPolicy = "isHealth"
+	// CustomMessage is the field name of custom status result
+	CustomMessage = "message"
+	// ContextFieldName is the field name of the live outputs passed to health policy and custom status
+	ContextFieldName = "context"
+)
+
+// UnhealthyRequeueInterval is the interval to evaluate the health of resources again when they are not healthy
+const UnhealthyRequeueInterval = 15 * time.Second
+
+// Status is the health of the resources rendered by a XDefinition
+type Status struct {
+	Healthy bool
+	Message string
+}
+
+// Condition returns the Ready condition reflecting the health status
+func (s *Status) Condition() conditiontype.Condition {
+	if s.Healthy {
+		return conditiontype.Available().WithMessage(s.Message)
+	}
+	return conditiontype.Unavailable().WithMessage(s.Message)
+}
+
+// RequeueAfter returns the interval to evaluate the health status again, zero means no need
+func (s *Status) RequeueAfter() time.Duration {
+	if s.Healthy {
+		return 0
+	}
+	return UnhealthyRequeueInterval
+}
+
+// FromError returns an unhealthy status for the error occurred while evaluating health
+func FromError(err error) *Status {
+	return &Status{Healthy: false, Message: err.Error()}
+}
+
+// Evaluate evaluates the health policy and custom status of the XDefinition of bdcFile against the live state
+// of manifests, which should be rendered by bdcFile.PrepareManifests. The live outputs can be referred as
+// context.output and context.outputs.<name> in CUE, the same as the template. Without health policy, resources
+// are regarded as healthy once all of them exist.
+func Evaluate(ctx context.Context, c client.Reader, bdcFile *parser.BDCFile, manifests []*unstructured.Unstructured) (*Status, error) {
+	liveOutputs, missing, err := getLiveOutputs(ctx, c, bdcFile.BDCTemplate.Engine.OutputNames(), manifests)
+	if err != nil {
+		return nil, err
+	}
+	if len(missing) > 0 {
+		return &Status{Healthy: false, Message: fmt.Sprintf("waiting for resources to be created: %s", strings.Join(missing, ", "))}, nil
+	}
+
+	tmpl := bdcFile.BDCTemplate.FullTemplate
+	status := &Status{Healthy: true}
+	if tmpl.Health != "" {
+		if status.Healthy, err = CheckHealth(liveOutputs, tmpl.Health, bdcFile.BDCTemplate.Params); err != nil {
+			return nil, err
+		}
+	}
+	if tmpl.CustomStatus != "" {
+		if status.Message, err = GetStatusMessage(liveOutputs, tmpl.CustomStatus, bdcFile.BDCTemplate.Params); err != nil {
+			return nil, err
+		}
+	}
+	return status, nil
+}
+
+// CheckHealth evaluates healthPolicy against templateContext, the result is the bool field isHealth
+func CheckHealth(templateContext map[string]interface{}, healthPolicy string, parameter interface{}) (bool, error) {
+	v, err := compile(templateContext, healthPolicy, parameter)
+	if err != nil {
+		return false, errors.WithMessage(err, "evaluate health policy")
+	}
+	healthy := v.LookupPath(cue.ParsePath(HealthCheckPolicy))
+	if !healthy.Exists() {
+		return false, errors.Errorf("health policy must contain a field named %s", HealthCheckPolicy)
+	}
+	isHealth, err := healthy.Bool()
+	if err != nil {
+		// the live state is not complete yet, such as status is not reported
+		return false, nil
+	}
+	return isHealth, nil
+}
+
+// GetStatusMessage evaluates customStatus against templateContext, the result is the string field message
+func GetStatusMessage(templateContext map[string]interface{}, customStatus string, parameter interface{}) (string, error) {
+	v, err := compile(templateContext, customStatus, parameter)
+	if err != nil {
+		return "", errors.WithMessage(err, "evaluate custom status")
+	}
+	message := v.LookupPath(cue.ParsePath(CustomMessage))
+	if !message.Exists() {
+		return "", errors.Errorf("custom status must contain a field named %s", CustomMessage)
+	}
+	msg, err := message.String()
+	if err != nil {
+		return "", nil
+	}
+	return msg, nil
+}
+
+func compile(templateContext map[string]interface{}, policy string, parameter interface{}) (cue.Value, error) {
+	contextJSON, err := json.Marshal(templateContext)
+	if err != nil {
+		return cue.Value{}, err
+	}
+	paramJSON := []byte("{}")
+	if parameter != nil {
+		if paramJSON, err = json.Marshal(parameter); err != nil {
+			return cue.Value{}, err
+		}
+		if string(paramJSON) == "null" {
+			paramJSON = []byte("{}")
+		}
+	}
+
+	var file strings.Builder
+	file.WriteString(policy + "\n")
+	file.WriteString(fmt.Sprintf("%s: %s\n", ContextFieldName, string(contextJSON)))
+	file.WriteString(fmt.Sprintf("%s: %s\n", deftemplate.ParameterFieldName, string(paramJSON)))
+
+	v := cuecontext.New().CompileString(file.String())
+	if err := v.Err(); err != nil {
+		return cue.Value{}, err
+	}
+	return v, nil
+}
+
+// getLiveOutputs gets the live state of manifests from cluster, and returns the context for health policy
+// and the resources which don't exist yet
+func getLiveOutputs(ctx context.Context, c client.Reader, names []string, manifests []*unstructured.Unstructured) (map[string]interface{}, []string, error) {
+	templateContext := map[string]interface{}{}
+	outputs := map[string]interface{}{}
+	var missing []string
+	for i, manifest := range manifests {
+		live := &unstructured.Unstructured{}
+		live.SetGroupVersionKind(manifest.GroupVersionKind())
+		if err := c.Get(ctx, client.ObjectKeyFromObject(manifest), live); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return nil, nil, err
+			}
+			missing = append(missing, fmt.Sprintf("%s %s", manifest.GetKind(), client.ObjectKeyFromObject(manifest)))
+			continue
+		}
+		if i == 0 {
+			templateContext[deftemplate.OutputFieldName] = live.Object
+			continue
+		}
+		if i < len(names) {
+			outputs[names[i]] = live.Object
+		}
+	}
+	templateContext[deftemplate.OutputsFieldName] = outputs
+	return templateContext, missing, nil
+}
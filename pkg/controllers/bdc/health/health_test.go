@@ -0,0 +1,106 @@
+package health
+
+import (
+	"testing"
+)
+
+func TestCheckHealth(t *testing.T) {
+	templateContext := map[string]interface{}{
+		"output": map[string]interface{}{
+			"kind":   "Deployment",
+			"spec":   map[string]interface{}{"replicas": 2},
+			"status": map[string]interface{}{"readyReplicas": 2},
+		},
+		"outputs": map[string]interface{}{
+			"service": map[string]interface{}{"kind": "Service"},
+		},
+	}
+	tests := []struct {
+		name         string
+		healthPolicy string
+		parameter    interface{}
+		want         bool
+		wantErr      bool
+	}{
+		{
+			name:         "healthy",
+			healthPolicy: `isHealth: context.output.status.readyReplicas == context.output.spec.replicas`,
+			want:         true,
+		},
+		{
+			name:         "refer to outputs and parameter",
+			healthPolicy: `isHealth: context.outputs.service.kind == "Service" && parameter.enabled`,
+			parameter:    map[string]interface{}{"enabled": false},
+			want:         false,
+		},
+		{
+			name:         "live status is not complete",
+			healthPolicy: `isHealth: context.output.status.availableReplicas == 2`,
+			want:         false,
+		},
+		{
+			name:         "default to unhealthy",
+			healthPolicy: `isHealth: *(context.output.status.phase == "running") | false`,
+			want:         false,
+		},
+		{
+			name:         "missing isHealth",
+			healthPolicy: `healthy: true`,
+			wantErr:      true,
+		},
+		{
+			name:         "invalid policy",
+			healthPolicy: `isHealth: true & false`,
+			wantErr:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CheckHealth(templateContext, tt.healthPolicy, tt.parameter)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CheckHealth() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("CheckHealth() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetStatusMessage(t *testing.T) {
+	templateContext := map[string]interface{}{
+		"output": map[string]interface{}{
+			"status": map[string]interface{}{"readyReplicas": 1},
+		},
+	}
+	tests := []struct {
+		name         string
+		customStatus string
+		want         string
+		wantErr      bool
+	}{
+		{
+			name:         "message",
+			customStatus: `message: "Ready: \(context.output.status.readyReplicas)"`,
+			want:         "Ready: 1",
+		},
+		{
+			name:         "missing message",
+			customStatus: `msg: "ok"`,
+			wantErr:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetStatusMessage(templateContext, tt.customStatus, nil)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetStatusMessage() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("GetStatusMessage() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -18,6 +18,9 @@ package parser
 
 import (
 	"context"
+	"fmt"
+	"time"
+
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -34,6 +37,8 @@ import (
 	"kdp-oam-operator/pkg/controllers/bdc/constants"
 	"kdp-oam-operator/pkg/controllers/bdc/defcontext"
 	"kdp-oam-operator/pkg/controllers/bdc/deftemplate"
+	"kdp-oam-operator/pkg/controllers/utils/defrevision"
+	"kdp-oam-operator/pkg/controllers/utils/metrics"
 	"kdp-oam-operator/pkg/controllers/utils/uuid"
 	"kdp-oam-operator/pkg/utils"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -60,6 +65,7 @@ func (p *Parser) GenerateBigDataClusterFile(ctx context.Context, bdcObj runtime.
 	var objSpec interface{}
 	var objKind string
 	var objUID string
+	var bdcName string
 	var downstreamNs string
 	refDefName := ""
 	specName := ""
@@ -74,6 +80,7 @@ func (p *Parser) GenerateBigDataClusterFile(ctx context.Context, bdcObj runtime.
 		objKind = bdcObject.Kind
 		downstreamNs, _ = GenerateDownStreamNamespace(bdcObject)
 		objUID = string(bdcObject.UID)
+		bdcName = bdcObject.Name
 	case *bdcv1alpha1.ContextSecret:
 		objName = bdcObject.Name
 		objAnnotations = bdcObject.Annotations
@@ -84,6 +91,7 @@ func (p *Parser) GenerateBigDataClusterFile(ctx context.Context, bdcObj runtime.
 			return nil, err
 		}
 		objUID = string(bdcObject.UID)
+		bdcName = bdcObject.Annotations[constants.AnnotationBDCName]
 		refDefName = bdcObject.Spec.Type
 		specName = bdcObject.Spec.Name
 	case *bdcv1alpha1.ContextSetting:
@@ -96,6 +104,7 @@ func (p *Parser) GenerateBigDataClusterFile(ctx context.Context, bdcObj runtime.
 			return nil, err
 		}
 		objUID = string(bdcObject.UID)
+		bdcName = bdcObject.Annotations[constants.AnnotationBDCName]
 		refDefName = bdcObject.Spec.Type
 		specName = bdcObject.Spec.Name
 	case *bdcv1alpha1.Application:
@@ -108,6 +117,7 @@ func (p *Parser) GenerateBigDataClusterFile(ctx context.Context, bdcObj runtime.
 			return nil, err
 		}
 		objUID = string(bdcObject.UID)
+		bdcName = bdcObject.Annotations[constants.AnnotationBDCName]
 		refDefName = bdcObject.Spec.Type
 		specName = bdcObject.Spec.Name
 	}
@@ -137,6 +147,15 @@ func (p *Parser) GenerateBigDataClusterFile(ctx context.Context, bdcObj runtime.
 	if err != nil {
 		return nil, errors.WithMessagef(err, "fail to load xdefition template")
 	}
+	// load the template from the pinned or stable revision of XDefinition
+	if obj, ok := bdcObj.(client.Object); ok {
+		if ref := defrevision.Resolve(templ.XDefinition, defrevision.Pin(obj), bdcName); ref != "" {
+			templ, err = deftemplate.LoadRevisionTemplate(ctx, p.Client, templ.XDefinition, ref)
+			if err != nil {
+				return nil, errors.WithMessagef(err, "fail to load xdefition revision template")
+			}
+		}
+	}
 
 	//bdcTemplate, err := p.makeTemplate(ctx, objSpec)
 
@@ -176,6 +195,10 @@ type BDCFile struct {
 	Parser                    *Parser
 	BDCTemplate               *BDCTemplate
 	SetOwnerReference         bool
+	// ContextKeys are the keys of KDP context ConfigMaps referred by the template, set by PrepareManifests
+	ContextKeys []string
+	// ContextHash is the hash of the referred KDP context data, set by PrepareManifests
+	ContextHash string
 }
 
 type BDCTemplate struct {
@@ -187,12 +210,26 @@ type BDCTemplate struct {
 	Engine            deftemplate.AbstractEngine
 }
 
+// ApplyMode returns the apply mode of the manifests, the annotation of the object overrides the one of XDefinition
+func (bdcf *BDCFile) ApplyMode() string {
+	if mode := bdcf.BigDataClusterAnnotations[constants.AnnotationApplyMode]; mode != "" {
+		return mode
+	}
+	if bdcf.BDCTemplate != nil && bdcf.BDCTemplate.FullTemplate.XDefinition != nil {
+		return bdcf.BDCTemplate.FullTemplate.XDefinition.GetAnnotations()[constants.AnnotationDefinitionApplyMode]
+	}
+	return ""
+}
+
 func (bdcf *BDCFile) PrepareManifests(ctx context.Context, req ctrl.Request) (manifests []*unstructured.Unstructured, err error) {
 	ctxData, err := GenerateContextDataFromBigDataClusterFile(bdcf, ctx, req)
 	if err != nil {
 		return nil, errors.Wrapf(err, "fail to generate context data")
 	}
 	bdcCtx := defcontext.NewBDCContext(ctxData)
+	if bdcf.ContextKeys, bdcf.ContextHash, err = contextHash(bdcCtx, bdcf.BDCTemplate.FullTemplate.ContextSource()); err != nil {
+		return nil, errors.Wrapf(err, "fail to hash context data")
+	}
 
 	// generate manifest，context added last step will be used to render the output
 	manifests, err = bdcf.EvalContext(bdcCtx)
@@ -219,7 +256,33 @@ func (bdcf *BDCFile) PrepareManifests(ctx context.Context, req ctrl.Request) (ma
 }
 
 func (bdcf *BDCFile) EvalContext(ctx defcontext.ContextData) ([]*unstructured.Unstructured, error) {
-	return bdcf.BDCTemplate.Engine.RenderCUETemplate(ctx, bdcf.BDCTemplate.FullTemplate.TemplateStr, bdcf.BDCTemplate.Params)
+	tmpl := bdcf.BDCTemplate.FullTemplate
+	if tmpl.XDefinition != nil {
+		defer metrics.ObserveRender(tmpl.XDefinition.Name, string(bdcf.BDCTemplate.SchematicCategory), time.Now())
+	}
+	switch bdcf.BDCTemplate.SchematicCategory {
+	case common.HelmCategory:
+		return bdcf.BDCTemplate.Engine.RenderHelmChart(ctx, tmpl.HelmChart, tmpl.Helm.Values, bdcf.BDCTemplate.Params)
+	case common.GoTemplateCategory:
+		return bdcf.BDCTemplate.Engine.RenderGoTemplate(ctx, tmpl.TemplateStr, tmpl.GoTemplate.Schema, bdcf.BDCTemplate.Params)
+	}
+	return bdcf.BDCTemplate.Engine.RenderCUETemplate(ctx, tmpl.TemplateStr, bdcf.BDCTemplate.Params)
+}
+
+// contextHash returns the keys of KDP context referred by template, and the hash of their values
+func contextHash(ctx defcontext.ContextData, template string) ([]string, string, error) {
+	keys := defcontext.ReferredKeys(template)
+	values := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if val := ctx.GetData(key); val != nil {
+			values = append(values, fmt.Sprintf("%s=%v\n", key, val))
+		}
+	}
+	hash, err := utils.GenerateShortHashID(16, values...)
+	if err != nil {
+		return nil, "", err
+	}
+	return keys, hash, nil
 }
 
 // SetCommonContextLabels get base context labels
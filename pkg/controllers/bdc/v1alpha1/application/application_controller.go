@@ -25,12 +25,20 @@ import (
 	bdcv1alpha1 "kdp-oam-operator/api/bdc/v1alpha1"
 	bdcctrl "kdp-oam-operator/pkg/controllers/bdc"
 	"kdp-oam-operator/pkg/controllers/bdc/constants"
+	"kdp-oam-operator/pkg/controllers/bdc/health"
 	"kdp-oam-operator/pkg/controllers/bdc/parser"
+	"kdp-oam-operator/pkg/controllers/utils/apprevision"
 	"kdp-oam-operator/pkg/controllers/utils/condition"
 	"kdp-oam-operator/pkg/controllers/utils/dispatch"
+	"kdp-oam-operator/pkg/controllers/utils/dryrun"
+	"kdp-oam-operator/pkg/controllers/utils/metrics"
+	"kdp-oam-operator/pkg/controllers/utils/pause"
 	"kdp-oam-operator/pkg/controllers/utils/vela"
+	"kdp-oam-operator/pkg/controllers/utils/watch"
+	"kdp-oam-operator/pkg/controllers/utils/workload"
 	"kdp-oam-operator/pkg/utils"
 	"kdp-oam-operator/version"
+	"reflect"
 
 	velav1beta1 "github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
 	v1 "k8s.io/api/core/v1"
@@ -43,7 +51,10 @@ import (
 	"k8s.io/klog/v2"
 	"k8s.io/utils/pointer"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
 // Reconciler reconciles a Application object
@@ -56,14 +67,18 @@ type Reconciler struct {
 
 type options struct {
 	defRevLimit          int
+	appRevLimit          int
 	concurrentReconciles int
 	ignoreDefNoCtrlReq   bool
 	controllerVersion    string
+	// defRolloutRate is the number of resources re-rendered per second when their XDefinition or KDP context changes
+	defRolloutRate int
 }
 
 //+kubebuilder:rbac:groups=bdc.kdp.io,resources=applications,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=bdc.kdp.io,resources=applications/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=bdc.kdp.io,resources=applications/finalizers,verbs=update
+//+kubebuilder:rbac:groups=bdc.kdp.io,resources=applicationrevisions,verbs=get;list;watch;create;delete
 //+kubebuilder:rbac:groups=core.oam.dev/v1beta1,resources=applications,verbs=get;list;watch;create;update;patch;delete
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
@@ -86,7 +101,7 @@ func (reconciler *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 	}
 
 	if application.Status.Status == "" {
-		return ctrl.Result{}, reconciler.reconcileStatus(ctx, application, "", "")
+		return ctrl.Result{}, reconciler.reconcileStatus(ctx, application, nil, nil, nil)
 	}
 
 	// Set BigDataCluster as metadata.ownerReferences
@@ -122,38 +137,189 @@ func (reconciler *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		return ctrl.Result{}, err
 	}
 
+	if pause.Paused(&application) {
+		return ctrl.Result{}, pause.Pause(ctx, reconciler.Client, &application)
+	}
+
+	// roll back to the revision, which is held until BigDataCluster allows changes
+	if ref := application.Annotations[constants.AnnotationRollbackRevision]; ref != "" && bigDataCluster.DesiredState() == bdcv1alpha1.ActiveBigDataCluster {
+		return ctrl.Result{}, reconciler.rollback(ctx, application, ref)
+	}
+
 	// Replace template.parameter with BigDataCluster Object spec
 	bdcParser := parser.NewParser(reconciler.Client)
 
 	// Dispatch manifests
 	bdcDispatcher := dispatch.NewManifestsDispatcher(reconciler.Client)
+	bdcDispatcher.Recorder, bdcDispatcher.Owner = reconciler.Recorder, &application
 	bdcFile, err := bdcParser.GenerateBigDataClusterFile(ctx, &application)
 	if err != nil {
 		klog.Errorf("[application] [namespace：%s, name: %s] Generate BigDataClusterFile error: %v", application.Namespace, application.Name, err)
+		reconciler.Recorder.Eventf(&application, v1.EventTypeWarning, constants.EventReasonTemplateLoadFailed, "Failed to load the template: %v", err)
 		return ctrl.Result{}, reconciler.reconcileStatusWithInitializeError(ctx, application, err)
 	}
+	bdcDispatcher.ApplyMode = bdcFile.ApplyMode()
 
 	manifests, err := bdcFile.PrepareManifests(ctx, req)
 	if err != nil {
 		klog.Errorf("[application] [namespace：%s, name: %s] Prepare manifests error: %v", application.Namespace, application.Name, err)
+		reconciler.Recorder.Eventf(&application, v1.EventTypeWarning, constants.EventReasonRenderFailed, "Failed to render the manifests: %v", err)
 		return ctrl.Result{}, reconciler.reconcileStatusWithInitializeError(ctx, application, err)
 	}
 	// klog.InfoS("ContextSetting", "output manifests", manifests)
 
-	if len(manifests) > 0 {
+	if err := pause.Resume(ctx, reconciler.Client, &application, manifests); err != nil {
+		klog.Errorf("[application] [namespace：%s, name: %s] Resume error: %v", application.Namespace, application.Name, err)
+	}
+
+	if dryrun.Enabled(&application) {
 		mergeMetaData(&manifests, application)
-		if err := bdcDispatcher.Dispatch(ctx, manifests...); err != nil {
-			klog.Errorf("[application] [namespace：%s, name: %s]Handle Apply Manifests error: %v", application.Namespace, application.Name, err)
-			return ctrl.Result{}, reconciler.reconcileStatusWithOutputDefError(ctx, application, err)
+		if err := dryrun.Reconcile(ctx, reconciler.Client, &application, &application.Status.DryRun, application.Status.OutputResources, manifests); err != nil {
+			klog.Errorf("[application] [namespace：%s, name: %s] Dry run error: %v", application.Namespace, application.Name, err)
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+	if err := dryrun.Clear(ctx, reconciler.Client, &application, &application.Status.DryRun); err != nil {
+		klog.Errorf("[application] [namespace：%s, name: %s] Clear dry run error: %v", application.Namespace, application.Name, err)
+	}
+
+	// frozen or disabled BigDataCluster doesn't allow any change of its applications
+	state := bigDataCluster.DesiredState()
+	tracked := application.Status.OutputResources
+	if state == bdcv1alpha1.ActiveBigDataCluster {
+		// the hash is computed before dispatching, which fills the manifests with the live state
+		manifestHash, err := apprevision.ManifestHash(manifests)
+		if err != nil {
+			return ctrl.Result{}, reconciler.reconcileStatusWithInitializeError(ctx, application, err)
+		}
+		if len(manifests) > 0 {
+			mergeMetaData(&manifests, application)
+			if err := bdcDispatcher.Dispatch(ctx, manifests...); err != nil {
+				klog.Errorf("[application] [namespace：%s, name: %s]Handle Apply Manifests error: %v", application.Namespace, application.Name, err)
+				return ctrl.Result{}, reconciler.reconcileStatusWithOutputDefError(ctx, application, err)
+			}
+			klog.Info("Successfully generated manifests")
+		}
+		if err := reconciler.reconcileRevision(ctx, application, bdcFile, manifestHash); err != nil {
+			klog.Errorf("[application] [namespace：%s, name: %s] Reconcile revision error: %v", application.Namespace, application.Name, err)
+		}
+		// Prune the resources which are tracked but no longer rendered
+		if tracked, err = bdcDispatcher.Prune(ctx, application.Status.OutputResources, manifests...); err != nil {
+			klog.Errorf("[application] [namespace：%s, name: %s] Prune resources error: %v", application.Namespace, application.Name, err)
 		}
-		klog.Info("Successfully generated manifests")
 	}
 
-	if err = reconciler.reconcileStatus(ctx, application, bdcFile.BDCTemplate.FullTemplate.XDefinitionSchemaName, bdcFile.DownStreamNamespace); err != nil {
+	healthStatus, err := health.Evaluate(ctx, reconciler.Client, bdcFile, manifests)
+	if err != nil {
+		klog.Errorf("[application] [namespace：%s, name: %s] Evaluate health error: %v", application.Namespace, application.Name, err)
+		healthStatus = health.FromError(err)
+	}
+
+	if err = reconciler.reconcileStatus(ctx, application, bdcFile, healthStatus, tracked); err != nil {
 		return ctrl.Result{}, err
 	}
 
-	return ctrl.Result{}, nil
+	if err := reconciler.reconcileBigDataClusterState(ctx, application, bigDataCluster); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: healthStatus.RequeueAfter()}, nil
+}
+
+// reconcileBigDataClusterState enforces the state of BigDataCluster on the workloads of application.
+// Workloads are rendered with zero replicas by the vela application when BigDataCluster is disabled, and rendered
+// with their original replicas when it is active again. The vela application is watched, so no requeue is needed
+// to follow the progress.
+func (reconciler *Reconciler) reconcileBigDataClusterState(ctx context.Context, application bdcv1alpha1.Application, bigDataCluster bdcv1alpha1.BigDataCluster) error {
+	current := application.GetCondition(conditiontype.TypeSuspended)
+
+	var desired conditiontype.Condition
+	var reconcileErr error
+	switch bigDataCluster.DesiredState() {
+	case bdcv1alpha1.FrozenBigDataCluster:
+		desired = conditiontype.Suspended(conditiontype.ReasonBigDataClusterFrozen)
+	case bdcv1alpha1.DisabledBigDataCluster:
+		done, err := reconciler.suspendVelaApplication(ctx, application, bigDataCluster)
+		switch {
+		case err != nil:
+			desired, reconcileErr = conditiontype.Suspending(err.Error()), err
+		case !done:
+			desired = conditiontype.Suspending("waiting for the vela application to render the workloads with zero replicas")
+		default:
+			desired = conditiontype.Suspended(conditiontype.ReasonBigDataClusterDisabled)
+		}
+	default:
+		if current.Reason == "" || current.Reason == conditiontype.ReasonBigDataClusterActive {
+			return nil
+		}
+		if err := reconciler.resumeVelaApplication(ctx, application, bigDataCluster); err != nil {
+			desired, reconcileErr = conditiontype.Resuming(err.Error()), err
+		} else {
+			desired = conditiontype.Resumed()
+		}
+	}
+
+	if !current.Equal(desired) {
+		if err := reconciler.setCondition(ctx, client.ObjectKeyFromObject(&application), desired); err != nil {
+			return err
+		}
+	}
+	return reconcileErr
+}
+
+// suspendVelaApplication suspends the workloads of the vela application of application, and returns whether they
+// are suspended
+func (reconciler *Reconciler) suspendVelaApplication(ctx context.Context, application bdcv1alpha1.Application, bigDataCluster bdcv1alpha1.BigDataCluster) (bool, error) {
+	velaApplication, err := reconciler.getVelaApplication(ctx, application, bigDataCluster)
+	if err != nil || velaApplication == nil {
+		return velaApplication == nil, err
+	}
+	done, err := workload.Suspend(ctx, reconciler.Client, velaApplication)
+	if err != nil {
+		klog.Errorf("[application] [namespace：%s, name: %s] Suspend vela application error: %v", application.Namespace, application.Name, err)
+	}
+	return done, err
+}
+
+// resumeVelaApplication restores the workloads of the vela application of application
+func (reconciler *Reconciler) resumeVelaApplication(ctx context.Context, application bdcv1alpha1.Application, bigDataCluster bdcv1alpha1.BigDataCluster) error {
+	velaApplication, err := reconciler.getVelaApplication(ctx, application, bigDataCluster)
+	if err != nil || velaApplication == nil {
+		return err
+	}
+	if err := workload.Resume(ctx, reconciler.Client, velaApplication); err != nil {
+		klog.Errorf("[application] [namespace：%s, name: %s] Resume vela application error: %v", application.Namespace, application.Name, err)
+		return err
+	}
+	return nil
+}
+
+// getVelaApplication returns the vela application of application, nil if it doesn't exist
+func (reconciler *Reconciler) getVelaApplication(ctx context.Context, application bdcv1alpha1.Application, bigDataCluster bdcv1alpha1.BigDataCluster) (*velav1beta1.Application, error) {
+	namespace, err := parser.GenerateDownStreamNamespace(&bigDataCluster)
+	if err != nil {
+		return nil, err
+	}
+	var velaApplication velav1beta1.Application
+	if err := reconciler.Get(ctx, client.ObjectKey{Name: application.Spec.Name, Namespace: namespace}, &velaApplication); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf(constants.ErrGetVelaApplication, err)
+	}
+	return &velaApplication, nil
+}
+
+// setCondition sets condition on the latest application, so that status synced from vela application is kept
+func (reconciler *Reconciler) setCondition(ctx context.Context, key client.ObjectKey, c conditiontype.Condition) error {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		var application bdcv1alpha1.Application
+		if err := reconciler.Get(ctx, key, &application); err != nil {
+			return err
+		}
+		application.SetConditions(c)
+		return reconciler.Status().Update(ctx, &application)
+	})
 }
 
 func (reconciler *Reconciler) handlerFinalizer(ctx context.Context, application bdcv1alpha1.Application, bigDataCluster bdcv1alpha1.BigDataCluster) (bool, error) {
@@ -172,6 +338,7 @@ func (reconciler *Reconciler) handlerFinalizer(ctx context.Context, application
 		}
 
 		klog.InfoS("vela application exist, waiting for delete.", "application name", application.Name)
+		reconciler.Recorder.Eventf(&application, v1.EventTypeNormal, constants.EventReasonFinalizing, "Deleting vela application %s", klog.KObj(&velaApplication))
 
 		application.Status.Status = common.ApplicationDeleting
 		err = reconciler.UpdateStatus(ctx, &application)
@@ -182,6 +349,7 @@ func (reconciler *Reconciler) handlerFinalizer(ctx context.Context, application
 		// delete vela application
 		err = reconciler.Delete(ctx, &velaApplication)
 		if err != nil {
+			reconciler.Recorder.Eventf(&application, v1.EventTypeWarning, constants.EventReasonFinalizeFailed, "Failed to delete vela application %s: %v", klog.KObj(&velaApplication), err)
 			return true, err
 		}
 		return true, nil
@@ -194,6 +362,7 @@ func (reconciler *Reconciler) handlerFinalizer(ctx context.Context, application
 			klog.Errorf("[application] [namespace：%s, name: %s] Add finalizer error: %v", application.Namespace, application.Name, err)
 			return true, err
 		}
+		reconciler.Recorder.Event(&application, v1.EventTypeNormal, constants.EventReasonFinalizerAdded, "Added finalizer "+constants.FinalizerResourceTracker)
 	}
 
 	return false, nil
@@ -203,7 +372,9 @@ func (reconciler *Reconciler) deleteFinalizer(ctx context.Context, application b
 	utils.RemoveFinalizer(&application, constants.FinalizerResourceTracker)
 	if err := reconciler.Update(ctx, &application); err != nil {
 		klog.Errorf("[application] [namespace：%s, name: %s] Remove finalizer error: %v", application.Namespace, application.Name, err)
+		return
 	}
+	reconciler.Recorder.Event(&application, v1.EventTypeNormal, constants.EventReasonFinalized, "Removed finalizer "+constants.FinalizerResourceTracker)
 }
 
 func mergeMetaData(manifest *[]*unstructured.Unstructured, application bdcv1alpha1.Application) {
@@ -239,14 +410,22 @@ func (reconciler *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&bdcv1alpha1.Application{}).
 		Owns(&velav1beta1.Application{}). // watch vela application for sync status
-		Complete(reconciler)
+		Watches(&source.Kind{Type: &bdcv1alpha1.BigDataCluster{}},
+			watch.EnqueueRequestsForBigDataCluster(reconciler.Client, func() client.ObjectList { return &bdcv1alpha1.ApplicationList{} }),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})). // watch BigDataCluster for frozen and disabled state
+		Watches(&source.Kind{Type: &bdcv1alpha1.XDefinition{}},
+			watch.EnqueueRequestsForXDefinition(reconciler.Client, "Application", func() client.ObjectList { return &bdcv1alpha1.ApplicationList{} }, reconciler.defRolloutRate),
+			builder.WithPredicates(predicate.Or(predicate.GenerationChangedPredicate{}, predicate.AnnotationChangedPredicate{}))). // watch XDefinition for re-rendering with the changed template or stable revision
+		Watches(&source.Kind{Type: &v1.ConfigMap{}},
+			watch.EnqueueRequestsForKDPContext(reconciler.Client, func() client.ObjectList { return &bdcv1alpha1.ApplicationList{} }, reconciler.defRolloutRate)). // watch KDP context for re-rendering with the changed context
+		Complete(metrics.InstrumentReconciler("Application", reconciler))
 }
 
 func Setup(mgr ctrl.Manager, args bdcctrl.Args) error {
 	r := Reconciler{
 		Client:   mgr.GetClient(),
 		Scheme:   mgr.GetScheme(),
-		Recorder: mgr.GetEventRecorderFor("bdc-org-resource-control-controller"),
+		Recorder: mgr.GetEventRecorderFor("bdc-application-controller"),
 		options:  parseOptions(args),
 	}
 	return r.SetupWithManager(mgr)
@@ -255,7 +434,9 @@ func Setup(mgr ctrl.Manager, args bdcctrl.Args) error {
 func parseOptions(args bdcctrl.Args) options {
 	return options{
 		defRevLimit:          args.DefRevisionLimit,
+		appRevLimit:          args.AppRevisionLimit,
 		concurrentReconciles: args.ConcurrentReconciles,
+		defRolloutRate:       args.DefinitionRolloutRate,
 		ignoreDefNoCtrlReq:   args.IgnoreDefinitionWithoutControllerRequirement,
 		controllerVersion:    version.CoreVersion,
 	}
@@ -286,35 +467,64 @@ func (reconciler *Reconciler) reconcileStatusWithOutputDefError(ctx context.Cont
 // reconcile application status to sync vela application status.
 // Actually this controller just generate vela application not for final workload(like deployment/sts),
 // so it will use vela application status directly
-func (reconciler *Reconciler) reconcileStatus(ctx context.Context, application bdcv1alpha1.Application, xDefinitionSchemaName string, DownStreamNamespace string) error {
+func (reconciler *Reconciler) reconcileStatus(ctx context.Context, application bdcv1alpha1.Application, bdcFile *parser.BDCFile, healthStatus *health.Status, outputResources []v1.ObjectReference) error {
 	if application.Status.Status == "" {
 		application.Status.Status = common.ApplicationInitializing
 		// update .status.status field only, trigger next reconcile handling
 		return reconciler.UpdateStatus(ctx, &application)
 	}
 
-	application.Status.SchemaConfigMapRef = xDefinitionSchemaName
+	application.Status.SchemaConfigMapRef = bdcFile.BDCTemplate.FullTemplate.XDefinitionSchemaName
+	contextChanged := application.Status.ContextHash != bdcFile.ContextHash || !reflect.DeepEqual(application.Status.ContextKeys, bdcFile.ContextKeys)
+	application.Status.ContextKeys = bdcFile.ContextKeys
+	application.Status.ContextHash = bdcFile.ContextHash
+	revisionChanged := application.Status.DefinitionRevision != bdcFile.BDCTemplate.FullTemplate.XDefinitionRevision
+	application.Status.DefinitionRevision = bdcFile.BDCTemplate.FullTemplate.XDefinitionRevision
 
 	// get vela application status, set into bdc application
 	var velaApplication velav1beta1.Application
-	if err := reconciler.Get(ctx, client.ObjectKey{Name: application.Spec.Name, Namespace: DownStreamNamespace}, &velaApplication); err != nil {
+	if err := reconciler.Get(ctx, client.ObjectKey{Name: application.Spec.Name, Namespace: bdcFile.DownStreamNamespace}, &velaApplication); err != nil {
 		klog.Error(err, "[Reconcile Status] failed to get vela application")
 		return condition.PatchCondition(ctx, reconciler, &application, conditiontype.ReconcileError(fmt.Errorf(constants.ErrGetVelaApplication, err)))
 	}
 
 	desiredConditions := vela.DesiredConditionFromVela(&velaApplication)
 	desiredWorkflowStatus := vela.WorkflowStatusFromVela(velaApplication.Status.Workflow)
-	if !vela.DiffCondition(desiredConditions, application.Status.Conditions) &&
+	healthChanged := false
+	if healthStatus != nil {
+		// Ready condition is evaluated from the health policy of XDefinition rather than copied from vela
+		ready := healthStatus.Condition()
+		healthChanged = !application.GetCondition(conditiontype.TypeReady).Equal(ready) || application.Status.Message != healthStatus.Message
+		desiredConditions = append(desiredConditions, ready)
+		application.Status.Message = healthStatus.Message
+	}
+	trackerChanged := false
+	if outputResources != nil {
+		trackerChanged = !reflect.DeepEqual(application.Status.OutputResources, outputResources)
+		application.Status.OutputResources = outputResources
+	}
+	if !healthChanged && !trackerChanged && !contextChanged && !revisionChanged &&
+		!vela.DiffCondition(desiredConditions, application.Status.Conditions) &&
 		!vela.DiffWorkflowStatus(desiredWorkflowStatus, application.Status.Workflow) &&
 		application.Status.Status == string(velaApplication.Status.Phase) {
 		// status no changes
 		return nil
 	}
 
-	if velaApplication.Status.Phase == "" {
-		application.Status.Status = common.ApplicationStarting
-	} else {
-		application.Status.Status = string(velaApplication.Status.Phase)
+	phase := string(velaApplication.Status.Phase)
+	if phase == "" {
+		phase = common.ApplicationStarting
+	}
+	if application.Status.Status != phase {
+		reconciler.Recorder.Eventf(&application, v1.EventTypeNormal, constants.EventReasonVelaStatusChanged,
+			"Vela application %s changed from %q to %q", klog.KObj(&velaApplication), application.Status.Status, phase)
+	}
+	application.Status.Status = phase
+	// Suspended and Paused conditions are maintained by this controller rather than vela
+	for _, ct := range []conditiontype.ConditionType{conditiontype.TypeSuspended, conditiontype.TypePaused} {
+		if c := application.GetCondition(ct); c.Reason != "" {
+			desiredConditions = append(desiredConditions, c)
+		}
 	}
 	application.Status.Conditions = desiredConditions
 	application.Status.Workflow = desiredWorkflowStatus
@@ -0,0 +1,160 @@
+/*
+Copyright 2023 KDP(Kubernetes Data Platform).
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
+	conditiontype "kdp-oam-operator/api/bdc/condition"
+	bdcv1alpha1 "kdp-oam-operator/api/bdc/v1alpha1"
+	"kdp-oam-operator/pkg/controllers/bdc/constants"
+	"kdp-oam-operator/pkg/controllers/bdc/parser"
+	"kdp-oam-operator/pkg/controllers/utils/apprevision"
+	"kdp-oam-operator/pkg/controllers/utils/condition"
+)
+
+// reconcileRevision creates an ApplicationRevision if the application is changed effectively since the latest
+// revision, that is the properties, the generation of XDefinition or the rendered manifests are changed.
+// The revisions exceeding the revision limit are pruned from the oldest one.
+func (reconciler *Reconciler) reconcileRevision(ctx context.Context, application bdcv1alpha1.Application, bdcFile *parser.BDCFile, manifestHash string) error {
+	revisions, err := apprevision.List(ctx, reconciler.Client, application.Name)
+	if err != nil {
+		return err
+	}
+
+	xDefinition := bdcFile.BDCTemplate.FullTemplate.XDefinition
+	var number int64 = 1
+	if len(revisions) > 0 {
+		latest := revisions[len(revisions)-1]
+		if latest.Spec.ManifestHash == manifestHash &&
+			latest.Spec.XDefinitionName == xDefinition.Name &&
+			latest.Spec.XDefinitionGeneration == xDefinition.Generation &&
+			reflect.DeepEqual(latest.Spec.Properties, application.Spec.Properties) {
+			return reconciler.pruneRevisions(ctx, revisions)
+		}
+		number = latest.Spec.Revision + 1
+	}
+
+	revision := bdcv1alpha1.ApplicationRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   apprevision.Name(application.Name, number),
+			Labels: map[string]string{constants.LabelAppRevisionOf: application.Name},
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion:         bdcv1alpha1.GroupVersion.String(),
+				Kind:               "Application",
+				Name:               application.Name,
+				UID:                application.UID,
+				Controller:         pointer.Bool(true),
+				BlockOwnerDeletion: pointer.Bool(true),
+			}},
+		},
+		Spec: bdcv1alpha1.ApplicationRevisionSpec{
+			ApplicationName:       application.Name,
+			Revision:              number,
+			Properties:            application.Spec.Properties.DeepCopy(),
+			XDefinitionName:       xDefinition.Name,
+			XDefinitionGeneration: xDefinition.Generation,
+			ManifestHash:          manifestHash,
+			Author:                revisionAuthor(application),
+		},
+	}
+	if err := reconciler.Create(ctx, &revision); err != nil {
+		// the revision may be created by the last reconcile, but not in the cache yet
+		if apierrors.IsAlreadyExists(err) {
+			return nil
+		}
+		return err
+	}
+	klog.InfoS("Created application revision", "application", application.Name, "revision", revision.Name)
+	return reconciler.pruneRevisions(ctx, append(revisions, revision))
+}
+
+// pruneRevisions deletes the oldest revisions, only the latest one and appRevLimit revisions before it are kept
+func (reconciler *Reconciler) pruneRevisions(ctx context.Context, revisions []bdcv1alpha1.ApplicationRevision) error {
+	limit := reconciler.appRevLimit
+	if limit < 0 {
+		limit = 0
+	}
+	for i := 0; i < len(revisions)-limit-1; i++ {
+		if err := reconciler.Delete(ctx, &revisions[i]); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		klog.InfoS("Pruned application revision", "revision", revisions[i].Name)
+	}
+	return nil
+}
+
+// rollback sets the properties of application to the ones of the revision referred by annotation
+// bdc.kdp.io/rollback-revision, and removes the annotation. A new revision is created by the next reconcile
+// once the rolled back properties are rendered.
+func (reconciler *Reconciler) rollback(ctx context.Context, application bdcv1alpha1.Application, ref string) error {
+	delete(application.Annotations, constants.AnnotationRollbackRevision)
+
+	revisions, err := apprevision.List(ctx, reconciler.Client, application.Name)
+	if err != nil {
+		return err
+	}
+	revision := apprevision.Find(revisions, ref)
+	if revision == nil {
+		klog.Errorf("[application] [namespace：%s, name: %s] Rollback revision %s not found", application.Namespace, application.Name, ref)
+		// remove the annotation, or the rollback will fail forever
+		if err := reconciler.Update(ctx, &application); err != nil {
+			return err
+		}
+		return condition.PatchCondition(ctx, reconciler, &application,
+			conditiontype.ReconcileError(fmt.Errorf(constants.ErrRollbackRevision, application.Name, ref, "revision not found")))
+	}
+
+	application.Spec.Properties = revision.Spec.Properties.DeepCopy()
+	application.Annotations[constants.AnnotationBDCUpdatedTime] = metav1.Now().Format(time.RFC3339)
+	if err := reconciler.Update(ctx, &application); err != nil {
+		return err
+	}
+	klog.InfoS("Rolled back application", "application", application.Name, "revision", revision.Name)
+	return nil
+}
+
+// revisionAuthor returns the user who made the latest change of application, which is recorded by the apiserver in
+// annotation bdc.kdp.io/updated-by and kept through the rollback, or the field manager of the spec if the
+// annotation is not set
+func revisionAuthor(application bdcv1alpha1.Application) string {
+	if author := application.Annotations[constants.AnnotationUpdatedBy]; author != "" {
+		return author
+	}
+	var author string
+	var latest time.Time
+	for _, entry := range application.ManagedFields {
+		if entry.Subresource != "" || entry.FieldsV1 == nil || entry.Time == nil {
+			continue
+		}
+		if !strings.Contains(string(entry.FieldsV1.Raw), `"f:spec"`) {
+			continue
+		}
+		if entry.Time.Time.After(latest) || author == "" {
+			author, latest = entry.Manager, entry.Time.Time
+		}
+	}
+	return author
+}
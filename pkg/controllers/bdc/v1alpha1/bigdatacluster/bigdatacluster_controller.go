@@ -19,7 +19,9 @@ package bigdatacluster
 import (
 	"context"
 	"fmt"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/retry"
@@ -28,14 +30,23 @@ import (
 	bdcv1alpha1 "kdp-oam-operator/api/bdc/v1alpha1"
 	bdcctrl "kdp-oam-operator/pkg/controllers/bdc"
 	"kdp-oam-operator/pkg/controllers/bdc/constants"
+	"kdp-oam-operator/pkg/controllers/bdc/health"
 	"kdp-oam-operator/pkg/controllers/bdc/parser"
 	"kdp-oam-operator/pkg/controllers/utils/condition"
 	"kdp-oam-operator/pkg/controllers/utils/dispatch"
+	"kdp-oam-operator/pkg/controllers/utils/dryrun"
+	"kdp-oam-operator/pkg/controllers/utils/metrics"
+	"kdp-oam-operator/pkg/controllers/utils/pause"
+	"kdp-oam-operator/pkg/controllers/utils/watch"
 	"kdp-oam-operator/version"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+	"sort"
 )
 
 // Reconciler reconciles a BigDataCluster object
@@ -51,11 +62,14 @@ type options struct {
 	concurrentReconciles int
 	ignoreDefNoCtrlReq   bool
 	controllerVersion    string
+	// defRolloutRate is the number of resources re-rendered per second when their XDefinition or KDP context changes
+	defRolloutRate int
 }
 
 //+kubebuilder:rbac:groups=bdc.kdp.io,resources=bigdataclusters,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=bdc.kdp.io,resources=bigdataclusters/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=bdc.kdp.io,resources=bigdataclusters/finalizers,verbs=update
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -81,51 +95,97 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	}
 	//klog.InfoS("BigDataCluster", "bdc", bigDataCluster)
 
+	if pause.Paused(&bigDataCluster) {
+		return ctrl.Result{}, pause.Pause(ctx, r.Client, &bigDataCluster)
+	}
+
+	// Handle Finalizer before rendering, so that the deletion isn't blocked by a missing or broken template
+	endReconcile, result, err := r.handleFinalizers(ctx, &bigDataCluster, req)
+	if err != nil {
+		return result, err
+	}
+	if endReconcile {
+		return result, nil
+	}
+
 	// Replace template.parameter with BigDataCluster Object spec
 	bdcParser := parser.NewParser(r.Client)
 
 	// Dispatch manifests
 	bdcDispatcher := dispatch.NewManifestsDispatcher(r.Client)
+	bdcDispatcher.Recorder, bdcDispatcher.Owner = r.Recorder, &bigDataCluster
 	bdcFile, err := bdcParser.GenerateBigDataClusterFile(ctx, &bigDataCluster)
 	if err != nil {
 		klog.Error(err, "[Generate BigDataClusterFile]")
+		r.Recorder.Eventf(&bigDataCluster, corev1.EventTypeWarning, constants.EventReasonTemplateLoadFailed, "Failed to load the template: %v", err)
 		return ctrl.Result{}, err
 	}
+	bdcDispatcher.ApplyMode = bdcFile.ApplyMode()
 	// SetOwnerReference as false, Because Finalizer logic needs to be executed when deleting downstream resources
 	bdcFile.SetOwnerReference = false
 
 	manifests, err := bdcFile.PrepareManifests(ctx, req)
 	if err != nil {
 		klog.Error(err, "[Handle PrepareManifests]")
+		r.Recorder.Eventf(&bigDataCluster, corev1.EventTypeWarning, constants.EventReasonRenderFailed, "Failed to render the manifests: %v", err)
 		return ctrl.Result{}, err
 	}
 	bdcFile.ReferredObjects = manifests
 	// klog.InfoS("BigDataCluster", "output manifests", manifests)
 
-	// Handle Finalizer
-	endReconcile, result, err := r.handleFinalizers(ctx, &bigDataCluster, bdcFile)
-	if err != nil {
-		return result, err
+	if err := pause.Resume(ctx, r.Client, &bigDataCluster, manifests); err != nil {
+		klog.Error(err, "[Resume]")
 	}
-	if endReconcile {
-		return result, nil
+
+	if dryrun.Enabled(&bigDataCluster) {
+		if err := dryrun.Reconcile(ctx, r.Client, &bigDataCluster, &bigDataCluster.Status.DryRun, bigDataCluster.Status.OutputResources, manifests); err != nil {
+			klog.Error(err, "[Dry Run]")
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+	if err := dryrun.Clear(ctx, r.Client, &bigDataCluster, &bigDataCluster.Status.DryRun); err != nil {
+		klog.Error(err, "[Clear Dry Run]")
 	}
 
 	if len(manifests) > 0 {
 		if err := bdcDispatcher.Dispatch(ctx, manifests...); err != nil {
 			klog.Error(err, "[Handle Apply Manifests]")
+			// the tracked resources are not pruned, otherwise the renamed resources are deleted without being replaced
+			if patchErr := condition.PatchCondition(ctx, r, &bigDataCluster,
+				conditiontype.ReconcileError(fmt.Errorf(constants.ErrCreateBDCResource, bigDataCluster.Kind, bigDataCluster.Name, err))); patchErr != nil {
+				klog.Error(patchErr, "[Patch Condition]")
+			}
+			return ctrl.Result{}, err
 		}
 		klog.Info("Successfully generated manifests")
 	}
+	// Prune the resources which are tracked but no longer rendered
+	tracked, err := bdcDispatcher.Prune(ctx, bigDataCluster.Status.OutputResources, manifests...)
+	if err != nil {
+		klog.Error(err, "[Prune Resources]")
+	}
+
+	healthStatus, err := health.Evaluate(ctx, r.Client, bdcFile, manifests)
+	if err != nil {
+		klog.Error(err, "[Evaluate Health]")
+		healthStatus = health.FromError(err)
+	}
+
 	// UpdateStatus
-	bigDataCluster.Status.Status = bdcv1alpha1.ActiveBigDataCluster
+	bigDataCluster.Status.Status = bigDataCluster.DesiredState()
+	bigDataCluster.Status.Message = healthStatus.Message
+	bigDataCluster.Status.ContextKeys = bdcFile.ContextKeys
+	bigDataCluster.Status.ContextHash = bdcFile.ContextHash
+	bigDataCluster.Status.OutputResources = tracked
+	bigDataCluster.Status.SetConditions(healthStatus.Condition())
 	bigDataCluster.Status.SchemaConfigMapRef = bdcFile.BDCTemplate.FullTemplate.XDefinitionSchemaName
-	if bigDataCluster.Spec.Disabled {
-		bigDataCluster.Status.Status = bdcv1alpha1.DisabledBigDataCluster
-	}
-	if bigDataCluster.Spec.Frozen {
-		bigDataCluster.Status.Status = bdcv1alpha1.FrozenBigDataCluster
+	applications, err := r.applicationStates(ctx, &bigDataCluster)
+	if err != nil {
+		klog.Error(err, "[List Applications]")
+		return ctrl.Result{}, err
 	}
+	bigDataCluster.Status.Applications = applications
 	err = r.UpdateStatus(ctx, &bigDataCluster)
 	if err != nil {
 		err = condition.PatchCondition(ctx, r, &bigDataCluster,
@@ -140,7 +200,41 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return ctrl.Result{}, err
 	}
 
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: healthStatus.RequeueAfter()}, nil
+}
+
+// applicationStates reports how far the state of bigDataCluster has taken effect on each of its applications
+func (r *Reconciler) applicationStates(ctx context.Context, bigDataCluster *bdcv1alpha1.BigDataCluster) ([]bdcv1alpha1.ApplicationStateStatus, error) {
+	applications, err := watch.ListBoundObjects(ctx, r.Client, bigDataCluster.Name, &bdcv1alpha1.ApplicationList{})
+	if err != nil {
+		return nil, err
+	}
+
+	state := bigDataCluster.DesiredState()
+	var states []bdcv1alpha1.ApplicationStateStatus
+	for _, obj := range applications {
+		application := obj.(*bdcv1alpha1.Application)
+		suspended := application.GetCondition(conditiontype.TypeSuspended)
+		var enforced bool
+		switch state {
+		case bdcv1alpha1.FrozenBigDataCluster:
+			enforced = suspended.Status == corev1.ConditionTrue && suspended.Reason == conditiontype.ReasonBigDataClusterFrozen
+		case bdcv1alpha1.DisabledBigDataCluster:
+			enforced = suspended.Status == corev1.ConditionTrue && suspended.Reason == conditiontype.ReasonBigDataClusterDisabled
+		default:
+			enforced = suspended.Reason == "" || suspended.Reason == conditiontype.ReasonBigDataClusterActive
+		}
+		states = append(states, bdcv1alpha1.ApplicationStateStatus{
+			Name:     application.Name,
+			State:    state,
+			Enforced: enforced,
+			Message:  suspended.Message,
+		})
+	}
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].Name < states[j].Name
+	})
+	return states, nil
 }
 
 // UpdateStatus update Status with retry.RetryOnConflict
@@ -162,7 +256,15 @@ func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
 			MaxConcurrentReconciles: r.concurrentReconciles,
 		}).
 		For(&bdcv1alpha1.BigDataCluster{}).
-		Complete(r)
+		Watches(&source.Kind{Type: &bdcv1alpha1.Application{}},
+			watch.EnqueueRequestForBoundBigDataCluster(),
+			builder.WithPredicates(watch.ApplicationStateChangedPredicate())). // watch application for reporting the progress of frozen and disabled state
+		Watches(&source.Kind{Type: &bdcv1alpha1.XDefinition{}},
+			watch.EnqueueRequestsForXDefinition(r.Client, "BigDataCluster", func() client.ObjectList { return &bdcv1alpha1.BigDataClusterList{} }, r.defRolloutRate),
+			builder.WithPredicates(predicate.Or(predicate.GenerationChangedPredicate{}, predicate.AnnotationChangedPredicate{}))). // watch XDefinition for re-rendering with the changed template or stable revision
+		Watches(&source.Kind{Type: &corev1.ConfigMap{}},
+			watch.EnqueueRequestsForKDPContext(r.Client, func() client.ObjectList { return &bdcv1alpha1.BigDataClusterList{} }, r.defRolloutRate)). // watch KDP context for re-rendering with the changed context
+		Complete(metrics.InstrumentReconciler("BigDataCluster", r))
 }
 
 func Setup(mgr ctrl.Manager, args bdcctrl.Args) error {
@@ -179,12 +281,13 @@ func parseOptions(args bdcctrl.Args) options {
 	return options{
 		defRevLimit:          args.DefRevisionLimit,
 		concurrentReconciles: args.ConcurrentReconciles,
+		defRolloutRate:       args.DefinitionRolloutRate,
 		ignoreDefNoCtrlReq:   args.IgnoreDefinitionWithoutControllerRequirement,
 		controllerVersion:    version.CoreVersion,
 	}
 }
 
-func (r *Reconciler) handleFinalizers(ctx context.Context, bigDataCluster *bdcv1alpha1.BigDataCluster, bdcFile *parser.BDCFile) (bool, ctrl.Result, error) {
+func (r *Reconciler) handleFinalizers(ctx context.Context, bigDataCluster *bdcv1alpha1.BigDataCluster, req ctrl.Request) (bool, ctrl.Result, error) {
 	// name of our custom finalizer
 	myFinalizerName := "ns.bdc.kdp.io/finalizer"
 
@@ -199,6 +302,7 @@ func (r *Reconciler) handleFinalizers(ctx context.Context, bigDataCluster *bdcv1
 				return true, ctrl.Result{}, err
 			}
 			klog.InfoS("Register new finalizer for bigdatacluster", "finalizer", myFinalizerName)
+			r.Recorder.Event(bigDataCluster, corev1.EventTypeNormal, constants.EventReasonFinalizerAdded, "Added finalizer "+myFinalizerName)
 		}
 	} else {
 		// The object is being deleted
@@ -211,7 +315,8 @@ func (r *Reconciler) handleFinalizers(ctx context.Context, bigDataCluster *bdcv1
 			}
 
 			// our finalizer is present, so lets handle any external dependency
-			if err := r.deleteExternalResources(ctx, bdcFile); err != nil {
+			r.Recorder.Event(bigDataCluster, corev1.EventTypeNormal, constants.EventReasonFinalizing, "Deleting the downstream resources")
+			if err := r.deleteExternalResources(ctx, bigDataCluster, req); err != nil {
 				// if fail to delete the external dependency here, return with error
 				// so that it can be retried
 				return true, ctrl.Result{}, err
@@ -222,6 +327,7 @@ func (r *Reconciler) handleFinalizers(ctx context.Context, bigDataCluster *bdcv1
 			if err := r.Update(ctx, bigDataCluster); err != nil {
 				return true, ctrl.Result{}, err
 			}
+			r.Recorder.Event(bigDataCluster, corev1.EventTypeNormal, constants.EventReasonFinalized, "Removed finalizer "+myFinalizerName)
 		}
 		// Stop reconciliation as the item is being deleted
 		return true, ctrl.Result{}, nil
@@ -229,21 +335,37 @@ func (r *Reconciler) handleFinalizers(ctx context.Context, bigDataCluster *bdcv1
 	return false, ctrl.Result{}, nil
 }
 
-func (r *Reconciler) deleteExternalResources(ctx context.Context, bdcFile *parser.BDCFile) error {
+func (r *Reconciler) deleteExternalResources(ctx context.Context, bigDataCluster *bdcv1alpha1.BigDataCluster, req ctrl.Request) error {
 	//
 	// delete any external resources associated with the cronJob
 	//
 	// Ensure that delete implementation is idempotent and safe to invoke
 	// multiple times for same object.
-	klog.InfoS("Prepare to delete downstream resource", "Objects", bdcFile.ReferredObjects)
-	//time.Sleep(10 * time.Second)
-	for _, ro := range bdcFile.ReferredObjects {
-		err := r.Client.Delete(ctx, ro)
-		if err != nil {
-			return err
-		}
-		klog.InfoS("Deleted", "Kind", ro.GroupVersionKind(), "name", ro.GetName())
+	// The tracked resources are deleted regardless of the template, which may have changed, been deleted or broken
+	// after the resources were applied. The rendered ones are deleted as well when the template still renders.
+	rendered := r.renderForDeletion(ctx, bigDataCluster, req)
+	klog.InfoS("Prepare to delete downstream resource", "Objects", rendered, "Tracked", bigDataCluster.Status.OutputResources)
+	bdcDispatcher := dispatch.NewManifestsDispatcher(r.Client)
+	bdcDispatcher.Recorder, bdcDispatcher.Owner = r.Recorder, bigDataCluster
+	if err := bdcDispatcher.DeleteTracked(ctx, bigDataCluster.Status.OutputResources, rendered...); err != nil {
+		return err
 	}
-	klog.InfoS("Finish to delete downstream resource", "Objects", bdcFile.ReferredObjects)
+	klog.InfoS("Finish to delete downstream resource", "Objects", rendered, "Tracked", bigDataCluster.Status.OutputResources)
 	return nil
 }
+
+// renderForDeletion renders the manifests of bigDataCluster, nil if the template fails to load or render
+func (r *Reconciler) renderForDeletion(ctx context.Context, bigDataCluster *bdcv1alpha1.BigDataCluster, req ctrl.Request) []*unstructured.Unstructured {
+	bdcFile, err := parser.NewParser(r.Client).GenerateBigDataClusterFile(ctx, bigDataCluster)
+	if err != nil {
+		klog.InfoS("Delete the tracked downstream resources only, fail to load the template", "bdc", klog.KObj(bigDataCluster), "err", err)
+		return nil
+	}
+	bdcFile.SetOwnerReference = false
+	manifests, err := bdcFile.PrepareManifests(ctx, req)
+	if err != nil {
+		klog.InfoS("Delete the tracked downstream resources only, fail to render the manifests", "bdc", klog.KObj(bigDataCluster), "err", err)
+		return nil
+	}
+	return manifests
+}
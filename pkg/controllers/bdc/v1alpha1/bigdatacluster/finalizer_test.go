@@ -0,0 +1,72 @@
+/*
+Copyright 2024 KDP(Kubernetes Data Platform).
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bigdatacluster
+
+import (
+	"context"
+	bdcv1alpha1 "kdp-oam-operator/api/bdc/v1alpha1"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestDeleteWithoutXDefinition(t *testing.T) {
+	ctx := context.Background()
+	s := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(s)
+	_ = bdcv1alpha1.AddToScheme(s)
+	now := metav1.Now()
+	// the XDefinition of the BigDataCluster no longer exists, so its manifests cannot be rendered
+	bdc := &bdcv1alpha1.BigDataCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "bdc-sample",
+			DeletionTimestamp: &now,
+			Finalizers:        []string{"ns.bdc.kdp.io/finalizer"},
+		},
+		Status: bdcv1alpha1.BigDataClusterStatus{
+			OutputResources: []corev1.ObjectReference{
+				{APIVersion: "v1", Kind: "Namespace", Name: "ns-bdc-sample"},
+			},
+		},
+	}
+	kubeClient := fake.NewClientBuilder().WithScheme(s).WithObjects(
+		bdc,
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns-bdc-sample"}},
+	).Build()
+	r := &Reconciler{Client: kubeClient, Scheme: s, Recorder: record.NewFakeRecorder(100)}
+
+	if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(bdc)}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if err := kubeClient.Get(ctx, client.ObjectKey{Name: "ns-bdc-sample"}, &corev1.Namespace{}); !apierrors.IsNotFound(err) {
+		t.Errorf("tracked namespace error = %v, want NotFound", err)
+	}
+	var got bdcv1alpha1.BigDataCluster
+	if err := kubeClient.Get(ctx, client.ObjectKeyFromObject(bdc), &got); err == nil && len(got.Finalizers) != 0 {
+		t.Errorf("finalizers = %v, want removed", got.Finalizers)
+	} else if err != nil && !apierrors.IsNotFound(err) {
+		t.Fatal(err)
+	}
+}
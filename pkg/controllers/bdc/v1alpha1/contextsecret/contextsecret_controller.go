@@ -19,6 +19,7 @@ package contextsecret
 import (
 	"context"
 	"fmt"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/retry"
@@ -28,11 +29,19 @@ import (
 	bdcv1alpha1 "kdp-oam-operator/api/bdc/v1alpha1"
 	bdcctrl "kdp-oam-operator/pkg/controllers/bdc"
 	"kdp-oam-operator/pkg/controllers/bdc/constants"
+	"kdp-oam-operator/pkg/controllers/bdc/health"
 	"kdp-oam-operator/pkg/controllers/bdc/parser"
 	"kdp-oam-operator/pkg/controllers/utils/condition"
 	"kdp-oam-operator/pkg/controllers/utils/dispatch"
+	"kdp-oam-operator/pkg/controllers/utils/dryrun"
+	"kdp-oam-operator/pkg/controllers/utils/metrics"
+	"kdp-oam-operator/pkg/controllers/utils/pause"
+	"kdp-oam-operator/pkg/controllers/utils/watch"
 	"kdp-oam-operator/version"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -52,6 +61,8 @@ type options struct {
 	concurrentReconciles int
 	ignoreDefNoCtrlReq   bool
 	controllerVersion    string
+	// defRolloutRate is the number of resources re-rendered per second when their XDefinition or KDP context changes
+	defRolloutRate int
 }
 
 //+kubebuilder:rbac:groups=bdc.kdp.io,resources=contextsecrets,verbs=get;list;watch;create;update;patch;delete
@@ -77,6 +88,12 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	}
 	//klog.InfoS("ContextSecret", "bdc.cs", contextSecret)
 
+	// Check if the contextSecret instance is adopting an existing Secret. If true, then no manifest will be created.
+	if contextSecret.GetAnnotations()[constants.AnnotationCtxSecretAdopt] == "true" {
+		klog.InfoS("skip dispatch manifests: take over existing secret which is not controlled by this contextSecret instance", "", klog.KRef(req.Namespace, req.Name))
+		return ctrl.Result{}, nil
+	}
+
 	// Set BigDataCluster as metadata.ownerReferences
 	var bigDataCluster bdcv1alpha1.BigDataCluster
 	if err := r.Get(ctx, client.ObjectKey{Name: contextSecret.GetAnnotations()[constants.AnnotationBDCName]}, &bigDataCluster); err != nil {
@@ -99,31 +116,86 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		}
 	}
 
+	if pause.Paused(&contextSecret) {
+		return ctrl.Result{}, pause.Pause(ctx, r.Client, &contextSecret)
+	}
+
+	// Frozen or disabled BigDataCluster doesn't allow any change of its resources
+	if state := bigDataCluster.DesiredState(); state != bdcv1alpha1.ActiveBigDataCluster {
+		klog.InfoS("skip dispatch manifests: bigdatacluster is not active", "state", state, "", klog.KRef(req.Namespace, req.Name))
+		reason := conditiontype.ReasonBigDataClusterFrozen
+		if state == bdcv1alpha1.DisabledBigDataCluster {
+			reason = conditiontype.ReasonBigDataClusterDisabled
+		}
+		return ctrl.Result{}, condition.PatchCondition(ctx, r, &contextSecret, conditiontype.Suspended(reason))
+	}
+
 	// Replace template.parameter with BigDataCluster Object spec
 	bdcParser := parser.NewParser(r.Client)
 
 	// Dispatch manifests
 	bdcDispatcher := dispatch.NewManifestsDispatcher(r.Client)
+	bdcDispatcher.Recorder, bdcDispatcher.Owner = r.Recorder, &contextSecret
 	bdcFile, err := bdcParser.GenerateBigDataClusterFile(ctx, &contextSecret)
 	if err != nil {
 		klog.Error(err, "[Generate BigDataClusterFile]")
+		r.Recorder.Eventf(&contextSecret, corev1.EventTypeWarning, constants.EventReasonTemplateLoadFailed, "Failed to load the template: %v", err)
 		return ctrl.Result{}, err
 	}
+	bdcDispatcher.ApplyMode = bdcFile.ApplyMode()
 
 	manifests, err := bdcFile.PrepareManifests(ctx, req)
 	if err != nil {
 		klog.Error(err, "[Handle PrepareManifests]")
+		r.Recorder.Eventf(&contextSecret, corev1.EventTypeWarning, constants.EventReasonRenderFailed, "Failed to render the manifests: %v", err)
 		return ctrl.Result{}, err
 	}
 	// klog.InfoS("ContextSecret", "output manifests", manifests)
 
+	if err := pause.Resume(ctx, r.Client, &contextSecret, manifests); err != nil {
+		klog.Error(err, "[Resume]")
+	}
+
+	if dryrun.Enabled(&contextSecret) {
+		if err := dryrun.Reconcile(ctx, r.Client, &contextSecret, &contextSecret.Status.DryRun, contextSecret.Status.OutputResources, manifests); err != nil {
+			klog.Error(err, "[Dry Run]")
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+	if err := dryrun.Clear(ctx, r.Client, &contextSecret, &contextSecret.Status.DryRun); err != nil {
+		klog.Error(err, "[Clear Dry Run]")
+	}
+
 	if len(manifests) > 0 {
 		if err := bdcDispatcher.Dispatch(ctx, manifests...); err != nil {
 			klog.Error(err, "[Handle Apply Manifests]")
+			// the tracked resources are not pruned, otherwise the renamed resources are deleted without being replaced
+			if patchErr := condition.PatchCondition(ctx, r, &contextSecret,
+				conditiontype.ReconcileError(fmt.Errorf(constants.ErrCreateBDCResource, contextSecret.Kind, contextSecret.Name, err))); patchErr != nil {
+				klog.Error(patchErr, "[Patch Condition]")
+			}
+			return ctrl.Result{}, err
 		}
 		klog.Info("Successfully generated manifests")
 	}
+	// Prune the resources which are tracked but no longer rendered
+	tracked, err := bdcDispatcher.Prune(ctx, contextSecret.Status.OutputResources, manifests...)
+	if err != nil {
+		klog.Error(err, "[Prune Resources]")
+	}
+	contextSecret.Status.OutputResources = tracked
 	contextSecret.Status.SchemaConfigMapRef = bdcFile.BDCTemplate.FullTemplate.XDefinitionSchemaName
+	healthStatus, err := health.Evaluate(ctx, r.Client, bdcFile, manifests)
+	if err != nil {
+		klog.Error(err, "[Evaluate Health]")
+		healthStatus = health.FromError(err)
+	}
+	contextSecret.Status.Message = healthStatus.Message
+	contextSecret.Status.ContextKeys = bdcFile.ContextKeys
+	contextSecret.Status.ContextHash = bdcFile.ContextHash
+	contextSecret.Status.SetConditions(healthStatus.Condition())
+
 	// UpdateStatus
 	err = r.UpdateStatus(ctx, &contextSecret)
 	if err != nil {
@@ -134,12 +206,16 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		}
 		return ctrl.Result{}, err
 	}
-	err = condition.PatchCondition(ctx, r, &contextSecret, conditiontype.ReconcileSuccess())
+	conditions := []conditiontype.Condition{conditiontype.ReconcileSuccess()}
+	if contextSecret.GetCondition(conditiontype.TypeSuspended).Reason != "" {
+		conditions = append(conditions, conditiontype.Resumed())
+	}
+	err = condition.PatchCondition(ctx, r, &contextSecret, conditions...)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
 
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: healthStatus.RequeueAfter()}, nil
 }
 
 // UpdateStatus update Status with retry.RetryOnConflict
@@ -169,7 +245,15 @@ func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
 			MaxConcurrentReconciles: r.concurrentReconciles,
 		}).
 		For(&bdcv1alpha1.ContextSecret{}).
-		Complete(r)
+		Watches(&source.Kind{Type: &bdcv1alpha1.BigDataCluster{}},
+			watch.EnqueueRequestsForBigDataCluster(r.Client, func() client.ObjectList { return &bdcv1alpha1.ContextSecretList{} }),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&source.Kind{Type: &bdcv1alpha1.XDefinition{}},
+			watch.EnqueueRequestsForXDefinition(r.Client, "ContextSecret", func() client.ObjectList { return &bdcv1alpha1.ContextSecretList{} }, r.defRolloutRate),
+			builder.WithPredicates(predicate.Or(predicate.GenerationChangedPredicate{}, predicate.AnnotationChangedPredicate{}))). // watch XDefinition for re-rendering with the changed template or stable revision
+		Watches(&source.Kind{Type: &corev1.ConfigMap{}},
+			watch.EnqueueRequestsForKDPContext(r.Client, func() client.ObjectList { return &bdcv1alpha1.ContextSecretList{} }, r.defRolloutRate)). // watch KDP context for re-rendering with the changed context
+		Complete(metrics.InstrumentReconciler("ContextSecret", r))
 }
 
 func Setup(mgr ctrl.Manager, args bdcctrl.Args) error {
@@ -186,6 +270,7 @@ func parseOptions(args bdcctrl.Args) options {
 	return options{
 		defRevLimit:          args.DefRevisionLimit,
 		concurrentReconciles: args.ConcurrentReconciles,
+		defRolloutRate:       args.DefinitionRolloutRate,
 		ignoreDefNoCtrlReq:   args.IgnoreDefinitionWithoutControllerRequirement,
 		controllerVersion:    version.CoreVersion,
 	}
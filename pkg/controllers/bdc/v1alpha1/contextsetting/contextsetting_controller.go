@@ -19,6 +19,7 @@ package contextsetting
 import (
 	"context"
 	"fmt"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/retry"
@@ -28,11 +29,19 @@ import (
 	bdcv1alpha1 "kdp-oam-operator/api/bdc/v1alpha1"
 	bdcctrl "kdp-oam-operator/pkg/controllers/bdc"
 	"kdp-oam-operator/pkg/controllers/bdc/constants"
+	"kdp-oam-operator/pkg/controllers/bdc/health"
 	"kdp-oam-operator/pkg/controllers/bdc/parser"
 	"kdp-oam-operator/pkg/controllers/utils/condition"
 	"kdp-oam-operator/pkg/controllers/utils/dispatch"
+	"kdp-oam-operator/pkg/controllers/utils/dryrun"
+	"kdp-oam-operator/pkg/controllers/utils/metrics"
+	"kdp-oam-operator/pkg/controllers/utils/pause"
+	"kdp-oam-operator/pkg/controllers/utils/watch"
 	"kdp-oam-operator/version"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -52,6 +61,8 @@ type options struct {
 	concurrentReconciles int
 	ignoreDefNoCtrlReq   bool
 	controllerVersion    string
+	// defRolloutRate is the number of resources re-rendered per second when their XDefinition or KDP context changes
+	defRolloutRate int
 }
 
 //+kubebuilder:rbac:groups=bdc.kdp.io,resources=contextsettings,verbs=get;list;watch;create;update;patch;delete
@@ -77,7 +88,8 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	}
 	//klog.InfoS("contextSetting", "bdc.cs", contextSetting)
 
-	// Check if the contextSetting instance is marked to be adopted. If true, then no manifest will be created.
+	// Check if the contextSetting instance is marked to be adopted. If true, then no manifest will be created,
+	// its properties are synced with the adopted ConfigMap by the configmap syncer instead.
 	if contextSetting.GetAnnotations() != nil {
 		if _, ok := contextSetting.GetAnnotations()[constants.AnnotationCtxSettingAdopt]; ok {
 			klog.InfoS("skip dispatch manifests: take over existing manifests which are not controlled by this contextSetting instance", "", klog.KRef(req.Namespace, req.Name))
@@ -107,30 +119,85 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		}
 	}
 
+	if pause.Paused(&contextSetting) {
+		return ctrl.Result{}, pause.Pause(ctx, r.Client, &contextSetting)
+	}
+
+	// Frozen or disabled BigDataCluster doesn't allow any change of its resources
+	if state := bigDataCluster.DesiredState(); state != bdcv1alpha1.ActiveBigDataCluster {
+		klog.InfoS("skip dispatch manifests: bigdatacluster is not active", "state", state, "", klog.KRef(req.Namespace, req.Name))
+		reason := conditiontype.ReasonBigDataClusterFrozen
+		if state == bdcv1alpha1.DisabledBigDataCluster {
+			reason = conditiontype.ReasonBigDataClusterDisabled
+		}
+		return ctrl.Result{}, condition.PatchCondition(ctx, r, &contextSetting, conditiontype.Suspended(reason))
+	}
+
 	// Replace template.parameter with BigDataCluster Object spec
 	bdcParser := parser.NewParser(r.Client)
 
 	// Dispatch manifests
 	bdcDispatcher := dispatch.NewManifestsDispatcher(r.Client)
+	bdcDispatcher.Recorder, bdcDispatcher.Owner = r.Recorder, &contextSetting
 	bdcFile, err := bdcParser.GenerateBigDataClusterFile(ctx, &contextSetting)
 	if err != nil {
 		klog.Error(err, "[Generate BigDataClusterFile]")
+		r.Recorder.Eventf(&contextSetting, corev1.EventTypeWarning, constants.EventReasonTemplateLoadFailed, "Failed to load the template: %v", err)
 		return ctrl.Result{}, err
 	}
+	bdcDispatcher.ApplyMode = bdcFile.ApplyMode()
 
 	manifests, err := bdcFile.PrepareManifests(ctx, req)
 	if err != nil {
 		klog.Error(err, "[Handle PrepareManifests]")
+		r.Recorder.Eventf(&contextSetting, corev1.EventTypeWarning, constants.EventReasonRenderFailed, "Failed to render the manifests: %v", err)
 		return ctrl.Result{}, err
 	}
 	// klog.InfoS("ContextSetting", "output manifests", manifests)
 
+	if err := pause.Resume(ctx, r.Client, &contextSetting, manifests); err != nil {
+		klog.Error(err, "[Resume]")
+	}
+
+	if dryrun.Enabled(&contextSetting) {
+		if err := dryrun.Reconcile(ctx, r.Client, &contextSetting, &contextSetting.Status.DryRun, contextSetting.Status.OutputResources, manifests); err != nil {
+			klog.Error(err, "[Dry Run]")
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+	if err := dryrun.Clear(ctx, r.Client, &contextSetting, &contextSetting.Status.DryRun); err != nil {
+		klog.Error(err, "[Clear Dry Run]")
+	}
+
 	if len(manifests) > 0 {
 		if err := bdcDispatcher.Dispatch(ctx, manifests...); err != nil {
 			klog.Error(err, "[Handle Apply Manifests]")
+			// the tracked resources are not pruned, otherwise the renamed resources are deleted without being replaced
+			if patchErr := condition.PatchCondition(ctx, r, &contextSetting,
+				conditiontype.ReconcileError(fmt.Errorf(constants.ErrCreateBDCResource, contextSetting.Kind, contextSetting.Name, err))); patchErr != nil {
+				klog.Error(patchErr, "[Patch Condition]")
+			}
+			return ctrl.Result{}, err
 		}
 		klog.Info("Successfully generated manifests")
 	}
+	// Prune the resources which are tracked but no longer rendered
+	tracked, err := bdcDispatcher.Prune(ctx, contextSetting.Status.OutputResources, manifests...)
+	if err != nil {
+		klog.Error(err, "[Prune Resources]")
+	}
+	contextSetting.Status.OutputResources = tracked
+	healthStatus, err := health.Evaluate(ctx, r.Client, bdcFile, manifests)
+	if err != nil {
+		klog.Error(err, "[Evaluate Health]")
+		healthStatus = health.FromError(err)
+	}
+	contextSetting.Status.Message = healthStatus.Message
+	contextSetting.Status.ContextKeys = bdcFile.ContextKeys
+	contextSetting.Status.ContextHash = bdcFile.ContextHash
+	contextSetting.Status.SetConditions(healthStatus.Condition())
+
 	// UpdateStatus
 	contextSetting.Status.SchemaConfigMapRef = bdcFile.BDCTemplate.FullTemplate.XDefinitionSchemaName
 	err = r.UpdateStatus(ctx, &contextSetting)
@@ -141,12 +208,16 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		}
 		return ctrl.Result{}, err
 	}
-	err = condition.PatchCondition(ctx, r, &contextSetting, conditiontype.ReconcileSuccess())
+	conditions := []conditiontype.Condition{conditiontype.ReconcileSuccess()}
+	if contextSetting.GetCondition(conditiontype.TypeSuspended).Reason != "" {
+		conditions = append(conditions, conditiontype.Resumed())
+	}
+	err = condition.PatchCondition(ctx, r, &contextSetting, conditions...)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
 
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: healthStatus.RequeueAfter()}, nil
 }
 
 // UpdateStatus update Status with retry.RetryOnConflict
@@ -176,7 +247,15 @@ func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
 			MaxConcurrentReconciles: r.concurrentReconciles,
 		}).
 		For(&bdcv1alpha1.ContextSetting{}).
-		Complete(r)
+		Watches(&source.Kind{Type: &bdcv1alpha1.BigDataCluster{}},
+			watch.EnqueueRequestsForBigDataCluster(r.Client, func() client.ObjectList { return &bdcv1alpha1.ContextSettingList{} }),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&source.Kind{Type: &bdcv1alpha1.XDefinition{}},
+			watch.EnqueueRequestsForXDefinition(r.Client, "ContextSetting", func() client.ObjectList { return &bdcv1alpha1.ContextSettingList{} }, r.defRolloutRate),
+			builder.WithPredicates(predicate.Or(predicate.GenerationChangedPredicate{}, predicate.AnnotationChangedPredicate{}))). // watch XDefinition for re-rendering with the changed template or stable revision
+		Watches(&source.Kind{Type: &corev1.ConfigMap{}},
+			watch.EnqueueRequestsForKDPContext(r.Client, func() client.ObjectList { return &bdcv1alpha1.ContextSettingList{} }, r.defRolloutRate)). // watch KDP context for re-rendering with the changed context
+		Complete(metrics.InstrumentReconciler("ContextSetting", r))
 }
 
 func Setup(mgr ctrl.Manager, args bdcctrl.Args) error {
@@ -193,6 +272,7 @@ func parseOptions(args bdcctrl.Args) options {
 	return options{
 		defRevLimit:          args.DefRevisionLimit,
 		concurrentReconciles: args.ConcurrentReconciles,
+		defRolloutRate:       args.DefinitionRolloutRate,
 		ignoreDefNoCtrlReq:   args.IgnoreDefinitionWithoutControllerRequirement,
 		controllerVersion:    version.CoreVersion,
 	}
@@ -0,0 +1,160 @@
+/*
+Copyright 2023 KDP(Kubernetes Data Platform).
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xdefinitions
+
+import (
+	"context"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
+	bdcv1alpha1 "kdp-oam-operator/api/bdc/v1alpha1"
+	"kdp-oam-operator/pkg/controllers/bdc/constants"
+	"kdp-oam-operator/pkg/controllers/bdc/deftemplate"
+	"kdp-oam-operator/pkg/controllers/utils/defrevision"
+	"kdp-oam-operator/pkg/controllers/utils/watch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// reconcileRevision creates an XDefinitionRevision if the spec of xDefinition is changed since the latest revision,
+// and returns the reference of the latest revision. The revisions exceeding the revision limit are pruned from
+// the oldest one, except the ones still pinned.
+func (r *Reconciler) reconcileRevision(ctx context.Context, xDefinition *bdcv1alpha1.XDefinition) (*bdcv1alpha1.XDefinitionRevisionRef, error) {
+	hash, err := defrevision.Hash(xDefinition.Spec)
+	if err != nil {
+		return nil, err
+	}
+	revisions, err := defrevision.List(ctx, r.Client, xDefinition.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	var number int64 = 1
+	if len(revisions) > 0 {
+		latest := revisions[len(revisions)-1]
+		if latest.Spec.RevisionHash == hash {
+			return revisionRef(latest), r.pruneRevisions(ctx, xDefinition, revisions)
+		}
+		number = latest.Spec.Revision + 1
+	}
+
+	def := deftemplate.NewCapabilityXDef(xDefinition)
+	schema, _, err := def.GetOpenAPIAndUischemaSchema(xDefinition.Name)
+	if err != nil {
+		return nil, err
+	}
+	revision := bdcv1alpha1.XDefinitionRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   defrevision.Name(xDefinition.Name, number),
+			Labels: map[string]string{constants.LabelDefRevisionOf: xDefinition.Name},
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion:         bdcv1alpha1.GroupVersion.String(),
+				Kind:               "XDefinition",
+				Name:               xDefinition.Name,
+				UID:                xDefinition.UID,
+				Controller:         pointer.Bool(true),
+				BlockOwnerDeletion: pointer.Bool(true),
+			}},
+		},
+		Spec: bdcv1alpha1.XDefinitionRevisionSpec{
+			DefinitionName:       xDefinition.Name,
+			Revision:             number,
+			DefinitionGeneration: xDefinition.Generation,
+			RevisionHash:         hash,
+			Definition:           *xDefinition.Spec.DeepCopy(),
+			OpenAPISchema:        string(schema),
+		},
+	}
+	if err := r.Create(ctx, &revision); err != nil && !apierrors.IsAlreadyExists(err) {
+		return nil, err
+	}
+	klog.InfoS("Created xdefinition revision", "xdefinition", xDefinition.Name, "revision", revision.Name)
+	return revisionRef(revision), r.pruneRevisions(ctx, xDefinition, append(revisions, revision))
+}
+
+// pruneRevisions deletes the oldest revisions, only the latest one and defRevLimit revisions before it are kept.
+// The revisions pinned by the objects or the stable revision annotation are never deleted.
+func (r *Reconciler) pruneRevisions(ctx context.Context, xDefinition *bdcv1alpha1.XDefinition, revisions []bdcv1alpha1.XDefinitionRevision) error {
+	limit := r.defRevLimit
+	if limit < 0 {
+		limit = 0
+	}
+	if len(revisions)-limit-1 <= 0 {
+		return nil
+	}
+	pinned, err := r.pinnedRevisions(ctx, xDefinition, revisions)
+	if err != nil {
+		return err
+	}
+	for i := 0; i < len(revisions)-limit-1; i++ {
+		if pinned[revisions[i].Name] {
+			continue
+		}
+		if err := r.Delete(ctx, &revisions[i]); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		klog.InfoS("Pruned xdefinition revision", "revision", revisions[i].Name)
+	}
+	return nil
+}
+
+// pinnedRevisions returns the names of revisions pinned by the objects rendered by xDefinition, or by its stable revision
+func (r *Reconciler) pinnedRevisions(ctx context.Context, xDefinition *bdcv1alpha1.XDefinition, revisions []bdcv1alpha1.XDefinitionRevision) (map[string]bool, error) {
+	refs := []string{xDefinition.Annotations[constants.AnnotationStableRevision]}
+	if list := newDependentList(xDefinition.Spec.APIResource.Definition.Kind); list != nil {
+		objects, err := watch.ListDependents(ctx, r.Client, xDefinition, list)
+		if err != nil {
+			return nil, err
+		}
+		for _, obj := range objects {
+			refs = append(refs, defrevision.Pin(obj))
+		}
+	}
+	pinned := make(map[string]bool)
+	for _, ref := range refs {
+		if ref == "" {
+			continue
+		}
+		if revision := defrevision.Find(revisions, ref); revision != nil {
+			pinned[revision.Name] = true
+		}
+	}
+	return pinned, nil
+}
+
+func newDependentList(kind string) client.ObjectList {
+	switch kind {
+	case "Application":
+		return &bdcv1alpha1.ApplicationList{}
+	case "BigDataCluster":
+		return &bdcv1alpha1.BigDataClusterList{}
+	case "ContextSetting":
+		return &bdcv1alpha1.ContextSettingList{}
+	case "ContextSecret":
+		return &bdcv1alpha1.ContextSecretList{}
+	}
+	return nil
+}
+
+func revisionRef(revision bdcv1alpha1.XDefinitionRevision) *bdcv1alpha1.XDefinitionRevisionRef {
+	return &bdcv1alpha1.XDefinitionRevisionRef{
+		Name:         revision.Name,
+		Revision:     revision.Spec.Revision,
+		RevisionHash: revision.Spec.RevisionHash,
+	}
+}
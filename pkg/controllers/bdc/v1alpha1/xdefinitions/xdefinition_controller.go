@@ -23,14 +23,18 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/retry"
 	"k8s.io/klog/v2"
 	"kdp-oam-operator/api/bdc/common"
+	conditiontype "kdp-oam-operator/api/bdc/condition"
 	bdcv1alpha1 "kdp-oam-operator/api/bdc/v1alpha1"
 	pkgcommon "kdp-oam-operator/pkg/common"
 	bdcctrl "kdp-oam-operator/pkg/controllers/bdc"
 	"kdp-oam-operator/pkg/controllers/bdc/constants"
 	"kdp-oam-operator/pkg/controllers/bdc/deftemplate"
+	"kdp-oam-operator/pkg/controllers/utils/metrics"
+	"kdp-oam-operator/pkg/controllers/utils/pause"
 	"kdp-oam-operator/pkg/utils"
 	"kdp-oam-operator/version"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -41,7 +45,8 @@ import (
 // Reconciler reconciles a XDefinition object
 type Reconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
 	options
 }
 
@@ -55,6 +60,7 @@ type options struct {
 //+kubebuilder:rbac:groups=bdc.kdp.io,resources=xdefinitions,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=bdc.kdp.io,resources=xdefinitions/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=bdc.kdp.io,resources=xdefinitions/finalizers,verbs=update
+//+kubebuilder:rbac:groups=bdc.kdp.io,resources=xdefinitionrevisions,verbs=get;list;watch;create;delete
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -81,16 +87,32 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return ctrl.Result{}, err
 	}
 
+	if pause.Paused(&xDefinition) {
+		return ctrl.Result{}, pause.Pause(ctx, r.Client, &xDefinition)
+	}
+	if xDefinition.GetCondition(conditiontype.TypePaused).Status == v1.ConditionTrue {
+		klog.InfoS("Reconciliation is resumed", "object", klog.KObj(&xDefinition))
+		xDefinition.SetConditions(conditiontype.Unpaused(""))
+	}
+
 	def := deftemplate.NewCapabilityXDef(&xDefinition)
 	// Store the XDefinition OpenAPI-Schema to configMap
 	schemaCMName, err := def.StoreOpenAPISchema(ctx, r.Client, pkgcommon.SystemDefaultNamespace, req.Name)
 	if err != nil {
 		klog.InfoS("Could not store capability in ConfigMap", "err", err)
+		r.Recorder.Eventf(&xDefinition, v1.EventTypeWarning, constants.EventReasonTemplateLoadFailed, "Failed to store the OpenAPI schema: %v", err)
 		return ctrl.Result{}, nil
 	}
+	// Snapshot the XDefinition into an immutable revision
+	latestRevision, err := r.reconcileRevision(ctx, &xDefinition)
+	if err != nil {
+		klog.InfoS("Could not reconcile xdefinition revision", "err", err)
+		return ctrl.Result{}, err
+	}
 	// Store SchemaConfigMapRef
 	xDefinition.Status.SchemaConfigMapRef = schemaCMName
 	xDefinition.Status.SchemaConfigMapRefNamespace = pkgcommon.SystemDefaultNamespace
+	xDefinition.Status.LatestRevision = latestRevision
 
 	if err := r.UpdateStatus(ctx, &xDefinition); err != nil {
 		klog.InfoS("Could not update x Status", "err", err)
@@ -143,7 +165,8 @@ func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
 			MaxConcurrentReconciles: r.concurrentReconciles,
 		}).
 		For(&bdcv1alpha1.XDefinition{}).
-		Complete(r)
+		Owns(&bdcv1alpha1.XDefinitionRevision{}). // watch revisions to recreate the deleted latest one
+		Complete(metrics.InstrumentReconciler("XDefinition", r))
 }
 
 func (r *Reconciler) handlerFinalizer(ctx context.Context, xdef bdcv1alpha1.XDefinition) (bool, error) {
@@ -152,12 +175,16 @@ func (r *Reconciler) handlerFinalizer(ctx context.Context, xdef bdcv1alpha1.XDef
 			// clean info in bdc definition configMap
 			_, err := CreateOrUpdateConfigMap(ctx, r.Client, extractData(xdef, make(map[string]string)), true)
 			if err != nil {
+				r.Recorder.Eventf(&xdef, v1.EventTypeWarning, constants.EventReasonFinalizeFailed, "Failed to unregister the API resource: %v", err)
 				return true, err
 			}
 			// Remove the finalizer
 			utils.RemoveFinalizer(&xdef, constants.FinalizerResourceTracker)
-			err = r.Update(ctx, &xdef)
-			return true, err
+			if err = r.Update(ctx, &xdef); err != nil {
+				return true, err
+			}
+			r.Recorder.Event(&xdef, v1.EventTypeNormal, constants.EventReasonFinalized, "Removed finalizer "+constants.FinalizerResourceTracker)
+			return true, nil
 		}
 		return true, nil
 	}
@@ -165,8 +192,11 @@ func (r *Reconciler) handlerFinalizer(ctx context.Context, xdef bdcv1alpha1.XDef
 	if !utils.FinalizerExists(&xdef, constants.FinalizerResourceTracker) {
 		// Add the finalizer
 		xdef.SetFinalizers(append(xdef.GetFinalizers(), constants.FinalizerResourceTracker))
-		err := r.Update(ctx, &xdef)
-		return true, err
+		if err := r.Update(ctx, &xdef); err != nil {
+			return true, err
+		}
+		r.Recorder.Event(&xdef, v1.EventTypeNormal, constants.EventReasonFinalizerAdded, "Added finalizer "+constants.FinalizerResourceTracker)
+		return true, nil
 	}
 
 	return false, nil
@@ -174,9 +204,10 @@ func (r *Reconciler) handlerFinalizer(ctx context.Context, xdef bdcv1alpha1.XDef
 
 func Setup(mgr ctrl.Manager, args bdcctrl.Args) error {
 	r := Reconciler{
-		Client:  mgr.GetClient(),
-		Scheme:  mgr.GetScheme(),
-		options: parseOptions(args),
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("bdc-xdefinition-controller"),
+		options:  parseOptions(args),
 	}
 	return r.SetupWithManager(mgr)
 }
@@ -37,6 +37,14 @@ type Applicator interface {
 type applyAction struct {
 	skipUpdate       bool
 	updateAnnotation bool
+	// changed is set to true if the object is created or changed by the apply
+	changed *bool
+}
+
+func (act *applyAction) markChanged() {
+	if act.changed != nil {
+		*act.changed = true
+	}
 }
 
 // ApplyOption is called before applying state to the object.
@@ -45,6 +53,15 @@ type applyAction struct {
 // nolint: golint
 type ApplyOption func(act *applyAction, existing, desired client.Object) error
 
+// Changed reports whether the object is created or changed by the apply through changed, which is left untouched
+// if the object is already in the desired state.
+func Changed(changed *bool) ApplyOption {
+	return func(act *applyAction, _, _ client.Object) error {
+		act.changed = changed
+		return nil
+	}
+}
+
 // NewAPIApplicator creates an Applicator that applies state to an
 // object or creates the object if not exist.
 func NewAPIApplicator(c client.Client) *APIApplicator {
@@ -118,7 +135,18 @@ func (a *APIApplicator) Apply(ctx context.Context, desired client.Object, ao ...
 	if err != nil {
 		return errors.Wrap(err, "cannot calculate patch by computing a three way diff")
 	}
-	return errors.Wrapf(a.c.Patch(ctx, desired, patch), "cannot patch object")
+	data, err := patch.Data(desired)
+	if err != nil {
+		return errors.Wrap(err, "cannot calculate patch by computing a three way diff")
+	}
+	// the empty patch is still sent to fill desired with the live state
+	if err := a.c.Patch(ctx, desired, patch); err != nil {
+		return errors.Wrap(err, "cannot patch object")
+	}
+	if string(data) != "{}" {
+		applyAct.markChanged()
+	}
+	return nil
 }
 
 // excludeLastAppliedConfigurationForSpecialResources will filter special object that can reduce the record for "bdc.kdp.io/last-applied-configuration" annotation.
@@ -169,7 +197,11 @@ func createOrGetExisting(ctx context.Context, act *applyAction, c client.Client,
 			return nil, err
 		}
 		loggingApply("creating object", desired)
-		return nil, errors.Wrap(c.Create(ctx, desired), "cannot create object")
+		if err := c.Create(ctx, desired); err != nil {
+			return nil, errors.Wrap(err, "cannot create object")
+		}
+		act.markChanged()
+		return nil, nil
 	}
 
 	if act.updateAnnotation {
@@ -0,0 +1,137 @@
+/*
+Copyright 2023 KDP(Kubernetes Data Platform).
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apply
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"kdp-oam-operator/pkg/controllers/bdc/constants"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// ThreeWayMergeMode applies the manifests by the three-way merge patch with the last applied configuration
+	ThreeWayMergeMode = "three-way-merge"
+	// ServerSideMode applies the manifests by server-side apply, the conflicts with other field managers are reported
+	ServerSideMode = "server-side"
+	// ServerSideForceMode applies the manifests by server-side apply, and takes the ownership of conflicting fields
+	ServerSideForceMode = "server-side-force"
+
+	// FieldManager is the field manager of the manifests applied by server-side apply
+	FieldManager = "kdp-oam-operator"
+)
+
+// Mode returns the apply mode of desired, the annotation of desired overrides defaultMode
+func Mode(desired client.Object, defaultMode string) string {
+	if mode := desired.GetAnnotations()[constants.AnnotationApplyMode]; mode != "" {
+		return mode
+	}
+	if defaultMode != "" {
+		return defaultMode
+	}
+	return ThreeWayMergeMode
+}
+
+// NewServerSideApplicator creates an Applicator that applies the state of object by server-side apply,
+// the ownership of conflicting fields is taken if force is true. It falls back to APIApplicator
+// if server-side apply is not supported.
+func NewServerSideApplicator(c client.Client, force bool) *ServerSideApplicator {
+	return &ServerSideApplicator{
+		c:        c,
+		force:    force,
+		fallback: NewAPIApplicator(c),
+	}
+}
+
+// ServerSideApplicator implements Applicator by server-side apply
+type ServerSideApplicator struct {
+	c        client.Client
+	force    bool
+	fallback Applicator
+}
+
+// Apply applies the state of desired by server-side apply, the object is created if not exist
+func (a *ServerSideApplicator) Apply(ctx context.Context, desired client.Object, ao ...ApplyOption) error {
+	applyAct := &applyAction{}
+	var existing client.Object
+	if desired.GetName() != "" {
+		live := &unstructured.Unstructured{}
+		live.GetObjectKind().SetGroupVersionKind(desired.GetObjectKind().GroupVersionKind())
+		err := a.c.Get(ctx, types.NamespacedName{Name: desired.GetName(), Namespace: desired.GetNamespace()}, live)
+		if err != nil && !kerrors.IsNotFound(err) {
+			return errors.Wrap(err, "cannot get object")
+		}
+		if err == nil {
+			existing = live
+		}
+	}
+	if err := executeApplyOptions(applyAct, existing, desired, ao); err != nil {
+		return err
+	}
+	if applyAct.skipUpdate && existing != nil {
+		loggingApply("skip update", desired)
+		return nil
+	}
+
+	// server-side apply requires the object without managed fields and resource version
+	desired.SetManagedFields(nil)
+	desired.SetResourceVersion("")
+	opts := []client.PatchOption{client.FieldOwner(FieldManager)}
+	if a.force {
+		opts = append(opts, client.ForceOwnership)
+	}
+	loggingApply("server-side applying object", desired)
+	err := a.c.Patch(ctx, desired, client.Apply, opts...)
+	switch {
+	case err == nil:
+		// the resource version is kept by the no-op apply
+		if existing == nil || existing.GetResourceVersion() != desired.GetResourceVersion() {
+			applyAct.markChanged()
+		}
+		return nil
+	case kerrors.IsUnsupportedMediaType(err):
+		loggingApply("server-side apply is not supported, fall back to three-way merge", desired)
+		return a.fallback.Apply(ctx, desired, ao...)
+	case kerrors.IsConflict(err):
+		return conflictError(desired, err)
+	}
+	return errors.Wrap(err, "cannot apply object")
+}
+
+// conflictError returns the error describing the fields managed by other field managers
+func conflictError(desired client.Object, err error) error {
+	var conflicts []string
+	if status, ok := err.(kerrors.APIStatus); ok && status.Status().Details != nil {
+		for _, cause := range status.Status().Details.Causes {
+			if cause.Type == metav1.CauseTypeFieldManagerConflict {
+				conflicts = append(conflicts, cause.Message)
+			}
+		}
+	}
+	if len(conflicts) == 0 {
+		return errors.Wrap(err, "cannot apply object")
+	}
+	return fmt.Errorf(constants.ErrApplyConflict, desired.GetObjectKind().GroupVersionKind().Kind,
+		client.ObjectKeyFromObject(desired), strings.Join(conflicts, "; "))
+}
@@ -0,0 +1,164 @@
+package apply
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"kdp-oam-operator/pkg/controllers/bdc/constants"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// applyClient records the apply patches and returns patchErr for them, since the fake client doesn't support server-side apply,
+// the apply patches succeeded are no-ops
+type applyClient struct {
+	client.Client
+	patchErr error
+	patched  []client.PatchOptions
+}
+
+func (c *applyClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if patch.Type() != types.ApplyPatchType {
+		return c.Client.Patch(ctx, obj, patch, opts...)
+	}
+	patchOpts := client.PatchOptions{}
+	patchOpts.ApplyOptions(opts)
+	c.patched = append(c.patched, patchOpts)
+	if c.patchErr != nil {
+		return c.patchErr
+	}
+	// the apply is a no-op, which keeps the resource version of the live object
+	live := &unstructured.Unstructured{}
+	live.GetObjectKind().SetGroupVersionKind(obj.GetObjectKind().GroupVersionKind())
+	if err := c.Client.Get(ctx, client.ObjectKeyFromObject(obj), live); err == nil {
+		obj.SetResourceVersion(live.GetResourceVersion())
+	}
+	return nil
+}
+
+type recordApplicator struct {
+	applied []string
+}
+
+func (a *recordApplicator) Apply(_ context.Context, obj client.Object, _ ...ApplyOption) error {
+	a.applied = append(a.applied, obj.GetName())
+	return nil
+}
+
+func configMap(name string, annotations map[string]string) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetAPIVersion("v1")
+	u.SetKind("ConfigMap")
+	u.SetNamespace("test-ns")
+	u.SetName(name)
+	u.SetAnnotations(annotations)
+	u.SetResourceVersion("1")
+	return u
+}
+
+func TestMode(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		defaultMode string
+		want        string
+	}{
+		{name: "default", want: ThreeWayMergeMode},
+		{name: "default mode", defaultMode: ServerSideMode, want: ServerSideMode},
+		{name: "annotated", annotations: map[string]string{constants.AnnotationApplyMode: ServerSideForceMode}, defaultMode: ServerSideMode, want: ServerSideForceMode},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Mode(configMap("cm", tt.annotations), tt.defaultMode); got != tt.want {
+				t.Errorf("Mode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestServerSideApplicator_Apply(t *testing.T) {
+	gr := schema.GroupResource{Resource: "configmaps"}
+	conflict := kerrors.NewApplyConflict([]metav1.StatusCause{
+		{Type: metav1.CauseTypeFieldManagerConflict, Message: `conflict with "kubectl-edit": .data.port`, Field: ".data.port"},
+	}, "Apply failed with 1 conflict")
+	tests := []struct {
+		name         string
+		force        bool
+		patchErr     error
+		wantErr      string
+		wantFallback bool
+	}{
+		{name: "applied"},
+		{name: "forced", force: true},
+		{name: "conflict", patchErr: conflict, wantErr: `cannot apply ConfigMap test-ns/cm, fields are managed by others: conflict with "kubectl-edit": .data.port`},
+		{name: "not supported", patchErr: kerrors.NewGenericServerResponse(415, "PATCH", gr, "cm", "", 0, false), wantFallback: true},
+		{name: "other error", patchErr: kerrors.NewForbidden(gr, "cm", nil), wantErr: "cannot apply object"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli := &applyClient{Client: fake.NewClientBuilder().Build(), patchErr: tt.patchErr}
+			fallback := &recordApplicator{}
+			applicator := NewServerSideApplicator(cli, tt.force)
+			applicator.fallback = fallback
+
+			desired := configMap("cm", nil)
+			err := applicator.Apply(context.Background(), desired)
+			if tt.wantErr == "" && err != nil || tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("Apply() error = %v, want %q", err, tt.wantErr)
+			}
+			if len(cli.patched) != 1 {
+				t.Fatalf("Apply() patched %d times, want once", len(cli.patched))
+			}
+			patched := cli.patched[0]
+			if patched.FieldManager != FieldManager || (patched.Force != nil && *patched.Force) != tt.force {
+				t.Errorf("Apply() field manager = %s, force = %v", patched.FieldManager, patched.Force)
+			}
+			if desired.GetResourceVersion() != "" {
+				t.Errorf("Apply() resource version = %s, want empty", desired.GetResourceVersion())
+			}
+			if (len(fallback.applied) == 1) != tt.wantFallback {
+				t.Errorf("Apply() fallback applied = %v, want %v", fallback.applied, tt.wantFallback)
+			}
+		})
+	}
+}
+
+func TestChanged(t *testing.T) {
+	ctx := context.Background()
+	cli := &applyClient{Client: fake.NewClientBuilder().Build()}
+	withData := func(value string) *unstructured.Unstructured {
+		u := configMap("test-cm", nil)
+		u.SetResourceVersion("")
+		u.Object["data"] = map[string]interface{}{"key": value}
+		return u
+	}
+	tests := []struct {
+		name       string
+		applicator Applicator
+		desired    *unstructured.Unstructured
+		want       bool
+	}{
+		{name: "created", applicator: NewAPIApplicator(cli), desired: withData("a"), want: true},
+		{name: "unchanged", applicator: NewAPIApplicator(cli), desired: withData("a"), want: false},
+		{name: "patched", applicator: NewAPIApplicator(cli), desired: withData("b"), want: true},
+		// the resource version isn't changed by the no-op server-side apply
+		{name: "server-side unchanged", applicator: NewServerSideApplicator(cli, false), desired: withData("c"), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changed := false
+			if err := tt.applicator.Apply(ctx, tt.desired, Changed(&changed)); err != nil {
+				t.Fatalf("Apply() error = %v", err)
+			}
+			if changed != tt.want {
+				t.Errorf("Apply() changed = %v, want %v", changed, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2023 KDP(Kubernetes Data Platform).
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apprevision
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	bdcv1alpha1 "kdp-oam-operator/api/bdc/v1alpha1"
+	"kdp-oam-operator/pkg/controllers/bdc/constants"
+	"kdp-oam-operator/pkg/utils"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Name returns the name of the ApplicationRevision of appName with number revision
+func Name(appName string, revision int64) string {
+	return fmt.Sprintf("%s-v%d", appName, revision)
+}
+
+// List lists the ApplicationRevisions of appName, sorted by revision number from the oldest to the latest
+func List(ctx context.Context, c client.Reader, appName string) ([]bdcv1alpha1.ApplicationRevision, error) {
+	var list bdcv1alpha1.ApplicationRevisionList
+	if err := c.List(ctx, &list, client.MatchingLabels{constants.LabelAppRevisionOf: appName}); err != nil {
+		return nil, err
+	}
+	revisions := list.Items
+	sort.Slice(revisions, func(i, j int) bool {
+		return revisions[i].Spec.Revision < revisions[j].Spec.Revision
+	})
+	return revisions, nil
+}
+
+// Find finds the revision referred by ref, which is either the name or the number of the revision
+func Find(revisions []bdcv1alpha1.ApplicationRevision, ref string) *bdcv1alpha1.ApplicationRevision {
+	number, err := strconv.ParseInt(ref, 10, 64)
+	for i := range revisions {
+		if revisions[i].Name == ref || (err == nil && revisions[i].Spec.Revision == number) {
+			return &revisions[i]
+		}
+	}
+	return nil
+}
+
+// ManifestHash returns the hash of the rendered manifests
+func ManifestHash(manifests []*unstructured.Unstructured) (string, error) {
+	values := make([]string, 0, len(manifests))
+	for _, m := range manifests {
+		data, err := json.Marshal(m.Object)
+		if err != nil {
+			return "", err
+		}
+		values = append(values, string(data))
+	}
+	return utils.GenerateShortHashID(16, values...)
+}
@@ -0,0 +1,82 @@
+package apprevision
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	bdcv1alpha1 "kdp-oam-operator/api/bdc/v1alpha1"
+	"kdp-oam-operator/pkg/controllers/bdc/constants"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func revision(appName string, number int64) *bdcv1alpha1.ApplicationRevision {
+	return &bdcv1alpha1.ApplicationRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   Name(appName, number),
+			Labels: map[string]string{constants.LabelAppRevisionOf: appName},
+		},
+		Spec: bdcv1alpha1.ApplicationRevisionSpec{ApplicationName: appName, Revision: number},
+	}
+}
+
+func TestListAndFind(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = bdcv1alpha1.AddToScheme(scheme)
+	cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		revision("hdfs", 10), revision("hdfs", 2), revision("hdfs", 1), revision("hive", 1),
+	).Build()
+
+	revisions, err := List(context.Background(), cli, "hdfs")
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(revisions) != 3 || revisions[0].Spec.Revision != 1 || revisions[2].Spec.Revision != 10 {
+		t.Fatalf("List() got %d revisions not sorted by number", len(revisions))
+	}
+
+	tests := []struct {
+		name string
+		ref  string
+		want string
+	}{
+		{name: "by name", ref: "hdfs-v2", want: "hdfs-v2"},
+		{name: "by number", ref: "10", want: "hdfs-v10"},
+		{name: "not found", ref: "3", want: ""},
+		{name: "other application", ref: "hive-v1", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			if r := Find(revisions, tt.ref); r != nil {
+				got = r.Name
+			}
+			if got != tt.want {
+				t.Errorf("Find() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestManifestHash(t *testing.T) {
+	manifest := func(replicas int64) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"kind": "Deployment",
+			"spec": map[string]interface{}{"replicas": replicas},
+		}}
+	}
+	h1, err := ManifestHash([]*unstructured.Unstructured{manifest(1)})
+	if err != nil {
+		t.Fatalf("ManifestHash() error = %v", err)
+	}
+	h2, _ := ManifestHash([]*unstructured.Unstructured{manifest(1)})
+	h3, _ := ManifestHash([]*unstructured.Unstructured{manifest(2)})
+	if h1 != h2 {
+		t.Errorf("ManifestHash() is not stable, %s != %s", h1, h2)
+	}
+	if h1 == h3 {
+		t.Errorf("ManifestHash() should change with manifests")
+	}
+}
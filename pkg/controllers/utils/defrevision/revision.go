@@ -0,0 +1,104 @@
+/*
+Copyright 2023 KDP(Kubernetes Data Platform).
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package defrevision
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	bdcv1alpha1 "kdp-oam-operator/api/bdc/v1alpha1"
+	"kdp-oam-operator/pkg/controllers/bdc/constants"
+	"kdp-oam-operator/pkg/utils"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// LatestRevision refers to the latest XDefinition, which is the default revision of an object
+const LatestRevision = "latest"
+
+// Name returns the name of the XDefinitionRevision of defName with number revision
+func Name(defName string, revision int64) string {
+	return fmt.Sprintf("%s-v%d", defName, revision)
+}
+
+// List lists the XDefinitionRevisions of defName, sorted by revision number from the oldest to the latest
+func List(ctx context.Context, c client.Reader, defName string) ([]bdcv1alpha1.XDefinitionRevision, error) {
+	var list bdcv1alpha1.XDefinitionRevisionList
+	if err := c.List(ctx, &list, client.MatchingLabels{constants.LabelDefRevisionOf: defName}); err != nil {
+		return nil, err
+	}
+	revisions := list.Items
+	sort.Slice(revisions, func(i, j int) bool {
+		return revisions[i].Spec.Revision < revisions[j].Spec.Revision
+	})
+	return revisions, nil
+}
+
+// Find finds the revision referred by ref, which is either the name or the number of the revision
+func Find(revisions []bdcv1alpha1.XDefinitionRevision, ref string) *bdcv1alpha1.XDefinitionRevision {
+	number, err := strconv.ParseInt(ref, 10, 64)
+	for i := range revisions {
+		if revisions[i].Name == ref || (err == nil && revisions[i].Spec.Revision == number) {
+			return &revisions[i]
+		}
+	}
+	return nil
+}
+
+// Hash returns the hash of the XDefinition spec
+func Hash(spec bdcv1alpha1.XDefinitionSpec) (string, error) {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return "", err
+	}
+	return utils.GenerateShortHashID(16, string(data))
+}
+
+// Pin returns the revision obj is pinned to, by spec.definitionRevision of Application or
+// annotation bdc.kdp.io/definition-revision, empty if obj follows the latest XDefinition
+func Pin(obj client.Object) string {
+	pin := obj.GetAnnotations()[constants.AnnotationDefinitionRevision]
+	if app, ok := obj.(*bdcv1alpha1.Application); ok && app.Spec.DefinitionRevision != "" {
+		pin = app.Spec.DefinitionRevision
+	}
+	if pin == LatestRevision {
+		return ""
+	}
+	return pin
+}
+
+// Resolve returns the revision of xDefinition to render an object with, empty for the latest XDefinition.
+// The pinned revision is used if any, otherwise the stable revision of xDefinition is used unless the object
+// belongs to a canary BigDataCluster.
+func Resolve(xDefinition *bdcv1alpha1.XDefinition, pin, bdcName string) string {
+	if pin != "" {
+		return pin
+	}
+	stable := xDefinition.Annotations[constants.AnnotationStableRevision]
+	if stable == "" || stable == LatestRevision {
+		return ""
+	}
+	for _, canary := range strings.Split(xDefinition.Annotations[constants.AnnotationCanaryBigDataClusters], ",") {
+		if bdcName != "" && strings.TrimSpace(canary) == bdcName {
+			return ""
+		}
+	}
+	return stable
+}
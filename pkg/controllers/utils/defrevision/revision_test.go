@@ -0,0 +1,110 @@
+package defrevision
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"kdp-oam-operator/api/bdc/common"
+	bdcv1alpha1 "kdp-oam-operator/api/bdc/v1alpha1"
+	"kdp-oam-operator/pkg/controllers/bdc/constants"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestPin(t *testing.T) {
+	tests := []struct {
+		name string
+		obj  client.Object
+		want string
+	}{
+		{
+			name: "not pinned",
+			obj:  &bdcv1alpha1.ContextSetting{},
+			want: "",
+		},
+		{
+			name: "pinned by annotation",
+			obj:  &bdcv1alpha1.ContextSetting{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{constants.AnnotationDefinitionRevision: "2"}}},
+			want: "2",
+		},
+		{
+			name: "spec overrides annotation",
+			obj: &bdcv1alpha1.Application{
+				ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{constants.AnnotationDefinitionRevision: "2"}},
+				Spec:       bdcv1alpha1.ApplicationSpec{DefinitionRevision: "hdfs-def-v3"},
+			},
+			want: "hdfs-def-v3",
+		},
+		{
+			name: "latest",
+			obj:  &bdcv1alpha1.Application{Spec: bdcv1alpha1.ApplicationSpec{DefinitionRevision: LatestRevision}},
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Pin(tt.obj); got != tt.want {
+				t.Errorf("Pin() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	xDefinition := &bdcv1alpha1.XDefinition{ObjectMeta: metav1.ObjectMeta{
+		Name: "hdfs-def",
+		Annotations: map[string]string{
+			constants.AnnotationStableRevision:        "3",
+			constants.AnnotationCanaryBigDataClusters: "bdc-test, bdc-dev",
+		},
+	}}
+	tests := []struct {
+		name        string
+		xDefinition *bdcv1alpha1.XDefinition
+		pin         string
+		bdcName     string
+		want        string
+	}{
+		{name: "pinned", xDefinition: xDefinition, pin: "1", bdcName: "bdc-test", want: "1"},
+		{name: "canary", xDefinition: xDefinition, bdcName: "bdc-dev", want: ""},
+		{name: "stable", xDefinition: xDefinition, bdcName: "bdc-prod", want: "3"},
+		{name: "no stable revision", xDefinition: &bdcv1alpha1.XDefinition{}, bdcName: "bdc-prod", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Resolve(tt.xDefinition, tt.pin, tt.bdcName); got != tt.want {
+				t.Errorf("Resolve() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHash(t *testing.T) {
+	spec := func(template string) bdcv1alpha1.XDefinitionSpec {
+		return bdcv1alpha1.XDefinitionSpec{Schematic: &common.Schematic{CUE: &common.CUE{Template: template}}}
+	}
+	h1, err := Hash(spec("output: {}"))
+	if err != nil {
+		t.Fatalf("Hash() error = %v", err)
+	}
+	h2, _ := Hash(spec("output: {}"))
+	h3, _ := Hash(spec("outputs: {}"))
+	if h1 != h2 || h1 == h3 {
+		t.Errorf("Hash() should be stable and change with the template")
+	}
+}
+
+func TestFind(t *testing.T) {
+	revisions := []bdcv1alpha1.XDefinitionRevision{
+		{ObjectMeta: metav1.ObjectMeta{Name: Name("hdfs-def", 1)}, Spec: bdcv1alpha1.XDefinitionRevisionSpec{Revision: 1}},
+		{ObjectMeta: metav1.ObjectMeta{Name: Name("hdfs-def", 2)}, Spec: bdcv1alpha1.XDefinitionRevisionSpec{Revision: 2}},
+	}
+	if got := Find(revisions, "2"); got == nil || got.Name != "hdfs-def-v2" {
+		t.Errorf("Find() by number got %v", got)
+	}
+	if got := Find(revisions, "hdfs-def-v1"); got == nil || got.Spec.Revision != 1 {
+		t.Errorf("Find() by name got %v", got)
+	}
+	if got := Find(revisions, "3"); got != nil {
+		t.Errorf("Find() got %v, want nil", got)
+	}
+}
@@ -18,10 +18,17 @@ package dispatch
 
 import (
 	"context"
+	"time"
+
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
+	"kdp-oam-operator/pkg/controllers/bdc/constants"
 	"kdp-oam-operator/pkg/controllers/utils/apply"
+	"kdp-oam-operator/pkg/controllers/utils/metrics"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -29,32 +36,73 @@ import (
 type ManifestsDispatcher struct {
 	C          client.Client
 	Applicator apply.Applicator
+	// ServerSideApplicator and ForceServerSideApplicator apply the manifests in server-side apply mode
+	ServerSideApplicator      apply.Applicator
+	ForceServerSideApplicator apply.Applicator
+	// ApplyMode is the default apply mode of manifests, which is overridden by the annotation of manifest
+	ApplyMode string
+	// Recorder records the Events of the applied and pruned resources on Owner, no Event is recorded if it's nil
+	Recorder record.EventRecorder
+	Owner    runtime.Object
 }
 
 // NewManifestsDispatcher creates an ManifestsDispatcher.
 func NewManifestsDispatcher(c client.Client) *ManifestsDispatcher {
 	return &ManifestsDispatcher{
-		C:          c,
-		Applicator: apply.NewAPIApplicator(c),
+		C:                         c,
+		Applicator:                apply.NewAPIApplicator(c),
+		ServerSideApplicator:      apply.NewServerSideApplicator(c, false),
+		ForceServerSideApplicator: apply.NewServerSideApplicator(c, true),
+	}
+}
+
+// applicatorOf returns the applicator of the apply mode of rsc
+func (a *ManifestsDispatcher) applicatorOf(rsc *unstructured.Unstructured) apply.Applicator {
+	switch mode := apply.Mode(rsc, a.ApplyMode); mode {
+	case apply.ServerSideMode:
+		return a.ServerSideApplicator
+	case apply.ServerSideForceMode:
+		return a.ForceServerSideApplicator
+	case apply.ThreeWayMergeMode:
+	default:
+		klog.InfoS("Unknown apply mode, fall back to three-way merge", "object", klog.KObj(rsc), "mode", mode)
 	}
+	return a.Applicator
+}
+
+// event records an Event on the owner of the manifests
+func (a *ManifestsDispatcher) event(eventtype, reason, messageFmt string, args ...interface{}) {
+	if a.Recorder == nil || a.Owner == nil {
+		return
+	}
+	a.Recorder.Eventf(a.Owner, eventtype, reason, messageFmt, args...)
 }
 
 // Dispatch apply manifests into k8s
 func (a *ManifestsDispatcher) Dispatch(ctx context.Context, manifests ...*unstructured.Unstructured) error {
-	var applyOpts []apply.ApplyOption
 	for _, rsc := range manifests {
 		if rsc == nil {
 			continue
 		}
 		// each resource applied by dispatcher MUST be controlled by resource tracker
-		if err := a.Applicator.Apply(ctx, rsc, applyOpts...); err != nil {
+		changed := false
+		start := time.Now()
+		err := a.applicatorOf(rsc).Apply(ctx, rsc, apply.Changed(&changed))
+		metrics.ObserveApply(rsc.GroupVersionKind(), start, err)
+		if err != nil {
 			klog.ErrorS(err, "Failed to apply a resource", "object",
 				klog.KObj(rsc), "apiVersion", rsc.GetAPIVersion(), "kind", rsc.GetKind())
+			a.event(corev1.EventTypeWarning, constants.EventReasonApplyFailed, "Failed to apply %s %s: %v",
+				rsc.GetKind(), klog.KObj(rsc), err)
 			return errors.Wrapf(err, "cannot apply manifest, name: %q apiVersion: %q kind: %q",
 				rsc.GetName(), rsc.GetAPIVersion(), rsc.GetKind())
 		}
 		klog.InfoS("Successfully apply a resource", "object",
 			klog.KObj(rsc), "apiVersion", rsc.GetAPIVersion(), "kind", rsc.GetKind())
+		// the unchanged resources are applied on every reconcile, only the changes are recorded
+		if changed {
+			a.event(corev1.EventTypeNormal, constants.EventReasonApplied, "Applied %s %s", rsc.GetKind(), klog.KObj(rsc))
+		}
 	}
 	return nil
 }
@@ -0,0 +1,156 @@
+package dispatch
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/record"
+	"kdp-oam-operator/pkg/controllers/bdc/constants"
+	"kdp-oam-operator/pkg/controllers/utils/apply"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+type recordApplicator struct {
+	applied []string
+}
+
+func (a *recordApplicator) Apply(_ context.Context, obj client.Object, _ ...apply.ApplyOption) error {
+	a.applied = append(a.applied, obj.GetName())
+	return nil
+}
+
+func TestDispatchApplyMode(t *testing.T) {
+	annotated := func(name, mode string) *unstructured.Unstructured {
+		m := configMapManifest(name)
+		m.SetAnnotations(map[string]string{constants.AnnotationApplyMode: mode})
+		return m
+	}
+	tests := []struct {
+		name          string
+		applyMode     string
+		wantThreeWay  []string
+		wantSSA       []string
+		wantForcedSSA []string
+	}{
+		{
+			name:          "three-way merge by default",
+			wantThreeWay:  []string{"plain", "unknown"},
+			wantSSA:       []string{"ssa"},
+			wantForcedSSA: []string{"forced"},
+		},
+		{
+			name:          "server-side by default",
+			applyMode:     apply.ServerSideMode,
+			wantThreeWay:  []string{"unknown", "three-way"},
+			wantSSA:       []string{"plain", "ssa"},
+			wantForcedSSA: []string{"forced"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			threeWay, ssa, forcedSSA := &recordApplicator{}, &recordApplicator{}, &recordApplicator{}
+			dispatcher := &ManifestsDispatcher{
+				C:                         fake.NewClientBuilder().Build(),
+				Applicator:                threeWay,
+				ServerSideApplicator:      ssa,
+				ForceServerSideApplicator: forcedSSA,
+				ApplyMode:                 tt.applyMode,
+			}
+			manifests := []*unstructured.Unstructured{
+				configMapManifest("plain"),
+				annotated("ssa", apply.ServerSideMode),
+				annotated("forced", apply.ServerSideForceMode),
+				annotated("unknown", "unknown"),
+			}
+			if tt.applyMode != "" {
+				manifests = append(manifests, annotated("three-way", apply.ThreeWayMergeMode))
+			}
+			if err := dispatcher.Dispatch(context.Background(), manifests...); err != nil {
+				t.Fatalf("Dispatch() error = %v", err)
+			}
+			if !reflect.DeepEqual(threeWay.applied, tt.wantThreeWay) || !reflect.DeepEqual(ssa.applied, tt.wantSSA) ||
+				!reflect.DeepEqual(forcedSSA.applied, tt.wantForcedSSA) {
+				t.Errorf("Dispatch() three-way = %v, server-side = %v, forced server-side = %v",
+					threeWay.applied, ssa.applied, forcedSSA.applied)
+			}
+		})
+	}
+}
+
+type failedApplicator struct{}
+
+func (a *failedApplicator) Apply(_ context.Context, _ client.Object, _ ...apply.ApplyOption) error {
+	return errors.New("boom")
+}
+
+func TestDispatchEvents(t *testing.T) {
+	owner := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "owner", Namespace: "test-ns"}}
+	withData := func(value string) *unstructured.Unstructured {
+		m := configMapManifest("cm")
+		m.Object["data"] = map[string]interface{}{"key": value}
+		return m
+	}
+	cli := fake.NewClientBuilder().Build()
+	tests := []struct {
+		name       string
+		applicator apply.Applicator
+		manifest   *unstructured.Unstructured
+		wantErr    bool
+		wantEvent  string
+	}{
+		{
+			name:       "created",
+			applicator: apply.NewAPIApplicator(cli),
+			manifest:   withData("a"),
+			wantEvent:  "Normal Applied Applied ConfigMap test-ns/cm",
+		},
+		{
+			name:       "unchanged",
+			applicator: apply.NewAPIApplicator(cli),
+			manifest:   withData("a"),
+		},
+		{
+			name:       "changed",
+			applicator: apply.NewAPIApplicator(cli),
+			manifest:   withData("b"),
+			wantEvent:  "Normal Applied Applied ConfigMap test-ns/cm",
+		},
+		{
+			name:       "apply failed",
+			applicator: &failedApplicator{},
+			manifest:   withData("c"),
+			wantErr:    true,
+			wantEvent:  "Warning ApplyFailed Failed to apply ConfigMap test-ns/cm: boom",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := record.NewFakeRecorder(10)
+			dispatcher := &ManifestsDispatcher{
+				C:          cli,
+				Applicator: tt.applicator,
+				Recorder:   recorder,
+				Owner:      owner,
+			}
+			if err := dispatcher.Dispatch(context.Background(), tt.manifest); (err != nil) != tt.wantErr {
+				t.Fatalf("Dispatch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			select {
+			case got := <-recorder.Events:
+				if got != tt.wantEvent {
+					t.Errorf("event = %q, want %q", got, tt.wantEvent)
+				}
+			default:
+				if tt.wantEvent != "" {
+					t.Errorf("no event recorded, want %q", tt.wantEvent)
+				}
+			}
+		})
+	}
+}
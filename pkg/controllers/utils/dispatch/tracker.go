@@ -0,0 +1,134 @@
+/*
+Copyright 2023 KDP(Kubernetes Data Platform).
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatch
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/klog/v2"
+	"kdp-oam-operator/pkg/controllers/bdc/constants"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Track returns the references of manifests to be recorded by the resource tracker of their owner.
+// The UID is recorded if the manifest has been applied, so that a resource recreated by others is never deleted.
+func Track(manifests ...*unstructured.Unstructured) []corev1.ObjectReference {
+	var refs []corev1.ObjectReference
+	for _, rsc := range manifests {
+		if rsc == nil {
+			continue
+		}
+		refs = append(refs, corev1.ObjectReference{
+			APIVersion: rsc.GetAPIVersion(),
+			Kind:       rsc.GetKind(),
+			Namespace:  rsc.GetNamespace(),
+			Name:       rsc.GetName(),
+			UID:        rsc.GetUID(),
+		})
+	}
+	return refs
+}
+
+// Prune deletes the tracked resources which are not rendered in manifests any more.
+// It returns the resources should be tracked from now on, including the ones failed to be deleted.
+func (a *ManifestsDispatcher) Prune(ctx context.Context, tracked []corev1.ObjectReference, manifests ...*unstructured.Unstructured) ([]corev1.ObjectReference, error) {
+	desired := Track(manifests...)
+	var errs []error
+	for _, ref := range Stale(tracked, manifests...) {
+		if err := a.delete(ctx, ref); err != nil {
+			errs = append(errs, err)
+			desired = append(desired, ref)
+			a.event(corev1.EventTypeWarning, constants.EventReasonPruneFailed, "Failed to prune %s %s: %v",
+				ref.Kind, klog.KRef(ref.Namespace, ref.Name), err)
+			continue
+		}
+		klog.InfoS("Pruned a resource no longer rendered", "object", klog.KRef(ref.Namespace, ref.Name),
+			"apiVersion", ref.APIVersion, "kind", ref.Kind)
+		a.event(corev1.EventTypeNormal, constants.EventReasonPruned, "Pruned %s %s", ref.Kind, klog.KRef(ref.Namespace, ref.Name))
+	}
+	return desired, utilerrors.NewAggregate(errs)
+}
+
+// Stale returns the tracked resources which are not rendered in manifests any more
+func Stale(tracked []corev1.ObjectReference, manifests ...*unstructured.Unstructured) []corev1.ObjectReference {
+	rendered := map[string]bool{}
+	for _, ref := range Track(manifests...) {
+		rendered[trackKey(ref)] = true
+	}
+	var stale []corev1.ObjectReference
+	for _, ref := range tracked {
+		if !rendered[trackKey(ref)] {
+			stale = append(stale, ref)
+		}
+	}
+	return stale
+}
+
+// DeleteTracked deletes all tracked resources and the resources rendered in manifests
+func (a *ManifestsDispatcher) DeleteTracked(ctx context.Context, tracked []corev1.ObjectReference, manifests ...*unstructured.Unstructured) error {
+	seen := map[string]bool{}
+	var errs []error
+	for _, ref := range append(Track(manifests...), tracked...) {
+		key := trackKey(ref)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		if err := a.delete(ctx, ref); err != nil {
+			errs = append(errs, err)
+			a.event(corev1.EventTypeWarning, constants.EventReasonFinalizeFailed, "Failed to delete %s %s: %v",
+				ref.Kind, klog.KRef(ref.Namespace, ref.Name), err)
+			continue
+		}
+		klog.InfoS("Deleted a tracked resource", "object", klog.KRef(ref.Namespace, ref.Name),
+			"apiVersion", ref.APIVersion, "kind", ref.Kind)
+		a.event(corev1.EventTypeNormal, constants.EventReasonFinalizing, "Deleted %s %s", ref.Kind, klog.KRef(ref.Namespace, ref.Name))
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+func (a *ManifestsDispatcher) delete(ctx context.Context, ref corev1.ObjectReference) error {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(ref.APIVersion)
+	obj.SetKind(ref.Kind)
+	if err := a.C.Get(ctx, client.ObjectKey{Namespace: ref.Namespace, Name: ref.Name}, obj); err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "cannot get tracked resource, name: %q apiVersion: %q kind: %q", ref.Name, ref.APIVersion, ref.Kind)
+	}
+	// the resource has been recreated and is not the one applied by dispatcher
+	if ref.UID != "" && obj.GetUID() != ref.UID {
+		return nil
+	}
+	if err := a.C.Delete(ctx, obj); err != nil && !kerrors.IsNotFound(err) {
+		return errors.Wrapf(err, "cannot delete tracked resource, name: %q apiVersion: %q kind: %q", ref.Name, ref.APIVersion, ref.Kind)
+	}
+	return nil
+}
+
+// trackKey identifies a tracked resource regardless of its API version
+func trackKey(ref corev1.ObjectReference) string {
+	gk := schema.FromAPIVersionAndKind(ref.APIVersion, ref.Kind).GroupKind()
+	return gk.String() + "/" + ref.Namespace + "/" + ref.Name
+}
@@ -0,0 +1,84 @@
+package dispatch
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func configMapManifest(name string) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetAPIVersion("v1")
+	u.SetKind("ConfigMap")
+	u.SetNamespace("test-ns")
+	u.SetName(name)
+	return u
+}
+
+func TestPrune(t *testing.T) {
+	ctx := context.Background()
+	cli := fake.NewClientBuilder().WithObjects(
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "kept", Namespace: "test-ns", UID: "uid-kept"}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "dropped", Namespace: "test-ns", UID: "uid-dropped"}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "recreated", Namespace: "test-ns", UID: "uid-new"}},
+	).Build()
+	dispatcher := NewManifestsDispatcher(cli)
+
+	tracked := []corev1.ObjectReference{
+		{APIVersion: "v1", Kind: "ConfigMap", Namespace: "test-ns", Name: "kept", UID: "uid-kept"},
+		{APIVersion: "v1", Kind: "ConfigMap", Namespace: "test-ns", Name: "dropped", UID: "uid-dropped"},
+		{APIVersion: "v1", Kind: "ConfigMap", Namespace: "test-ns", Name: "recreated", UID: "uid-old"},
+		{APIVersion: "v1", Kind: "ConfigMap", Namespace: "test-ns", Name: "gone"},
+	}
+	got, err := dispatcher.Prune(ctx, tracked, configMapManifest("kept"), configMapManifest("added"))
+	if err != nil {
+		t.Fatalf("Prune() error = %v", err)
+	}
+	if len(got) != 2 || got[0].Name != "kept" || got[1].Name != "added" {
+		t.Errorf("Prune() got = %v, want kept and added", got)
+	}
+
+	tests := []struct {
+		name       string
+		wantExists bool
+	}{
+		{name: "kept", wantExists: true},
+		{name: "dropped", wantExists: false},
+		{name: "recreated", wantExists: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := cli.Get(ctx, client.ObjectKey{Namespace: "test-ns", Name: tt.name}, &corev1.ConfigMap{})
+			if exists := !kerrors.IsNotFound(err); exists != tt.wantExists {
+				t.Errorf("configmap %s exists = %v, want %v", tt.name, exists, tt.wantExists)
+			}
+		})
+	}
+}
+
+func TestDeleteTracked(t *testing.T) {
+	ctx := context.Background()
+	cli := fake.NewClientBuilder().WithObjects(
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "old", Namespace: "test-ns"}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "current", Namespace: "test-ns"}},
+	).Build()
+	dispatcher := NewManifestsDispatcher(cli)
+
+	tracked := []corev1.ObjectReference{{APIVersion: "v1", Kind: "ConfigMap", Namespace: "test-ns", Name: "old"}}
+	if err := dispatcher.DeleteTracked(ctx, tracked, configMapManifest("current")); err != nil {
+		t.Fatalf("DeleteTracked() error = %v", err)
+	}
+	var list corev1.ConfigMapList
+	if err := cli.List(ctx, &list); err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Items) != 0 {
+		t.Errorf("DeleteTracked() left %d configmaps", len(list.Items))
+	}
+}
@@ -0,0 +1,377 @@
+/*
+Copyright 2023 KDP(Kubernetes Data Platform).
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dryrun
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog/v2"
+	"kdp-oam-operator/api/bdc/common"
+	pkgcommon "kdp-oam-operator/pkg/common"
+	"kdp-oam-operator/pkg/controllers/bdc/constants"
+	"kdp-oam-operator/pkg/controllers/utils/dispatch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// ManifestsKey is the key of the rendered manifests in the dry-run ConfigMap
+	ManifestsKey = "manifests.yaml"
+	// DiffKey is the key of the diff between the live objects and the rendered manifests in the dry-run ConfigMap
+	DiffKey = "diff"
+
+	// ActionCreate means the rendered resource doesn't exist and would be created
+	ActionCreate = "create"
+	// ActionUpdate means the live resource differs from the rendered one and would be updated
+	ActionUpdate = "update"
+	// ActionPrune means the tracked resource is no longer rendered and would be pruned
+	ActionPrune = "prune"
+
+	redacted        = "<redacted>"
+	redactedChanged = "<redacted, changed>"
+)
+
+// Enabled returns whether obj is annotated to be dry-run
+func Enabled(obj metav1.Object) bool {
+	return obj.GetAnnotations()[constants.AnnotationDryRun] == "true"
+}
+
+// ConfigMapName returns the name of the dry-run ConfigMap of the object
+func ConfigMapName(kind, name string) string {
+	return fmt.Sprintf("dry-run-%s-%s", strings.ToLower(kind), name)
+}
+
+// Result is the rendered manifests of a dry-run and their diff against the live objects
+type Result struct {
+	// Manifests are the rendered manifests in YAML, with the Secret data redacted
+	Manifests string
+	// Diff is the diff from the live objects to the rendered manifests
+	Diff string
+	// Changes are the resources which would be created, updated or pruned
+	Changes []string
+	// Drifts are the live objects which differ from the rendered manifests,
+	// in the form of "<kind> <namespace>/<name>: <field paths>"
+	Drifts []string
+}
+
+// Reconcile records the manifests rendered for owner and their diff against the live objects in the dry-run
+// ConfigMap, and patches status, which is the dryRun status field of owner, to refer to it.
+// tracked are the resources applied for owner, the ones no longer rendered are reported to be pruned.
+func Reconcile(ctx context.Context, c client.Client, owner client.Object, status **common.DryRunStatus,
+	tracked []corev1.ObjectReference, manifests []*unstructured.Unstructured) error {
+	gvk, err := apiutil.GVKForObject(owner, c.Scheme())
+	if err != nil {
+		return err
+	}
+	result, err := Diff(ctx, c, tracked, manifests)
+	if err != nil {
+		return err
+	}
+
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+		Name:      ConfigMapName(gvk.Kind, owner.GetName()),
+		Namespace: pkgcommon.SystemDefaultNamespace,
+	}}
+	if _, err := controllerutil.CreateOrUpdate(ctx, c, cm, func() error {
+		labels := cm.GetLabels()
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		labels[constants.LabelDryRunKind] = strings.ToLower(gvk.Kind)
+		cm.SetLabels(labels)
+		cm.Data = map[string]string{ManifestsKey: result.Manifests, DiffKey: result.Diff}
+		return controllerutil.SetOwnerReference(owner, cm, c.Scheme())
+	}); err != nil {
+		return errors.Wrapf(err, "cannot record the dry-run of %s %s", gvk.Kind, owner.GetName())
+	}
+	klog.InfoS("Recorded the dry-run", "kind", gvk.Kind, "name", owner.GetName(), "configMap", klog.KObj(cm),
+		"changes", len(result.Changes))
+
+	desired := &common.DryRunStatus{ConfigMapRef: cm.Name, Changes: result.Changes}
+	if equality.Semantic.DeepEqual(*status, desired) {
+		return nil
+	}
+	patch := client.MergeFrom(owner.DeepCopyObject().(client.Object))
+	*status = desired
+	return c.Status().Patch(ctx, owner, patch)
+}
+
+// Clear deletes the dry-run ConfigMap referred by status, which is the dryRun status field of owner, and clears it
+func Clear(ctx context.Context, c client.Client, owner client.Object, status **common.DryRunStatus) error {
+	if *status == nil {
+		return nil
+	}
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+		Name:      (*status).ConfigMapRef,
+		Namespace: pkgcommon.SystemDefaultNamespace,
+	}}
+	if err := c.Delete(ctx, cm); err != nil && !kerrors.IsNotFound(err) {
+		return errors.Wrapf(err, "cannot delete the dry-run ConfigMap %s", cm.Name)
+	}
+	patch := client.MergeFrom(owner.DeepCopyObject().(client.Object))
+	*status = nil
+	return c.Status().Patch(ctx, owner, patch)
+}
+
+// Diff compares the manifests with the live objects. Only the fields rendered in the manifests are compared,
+// and the values of the Secret data are redacted from the result.
+func Diff(ctx context.Context, c client.Reader, tracked []corev1.ObjectReference, manifests []*unstructured.Unstructured) (*Result, error) {
+	result := &Result{}
+	var rendered, diff strings.Builder
+	for _, m := range manifests {
+		if m == nil {
+			continue
+		}
+		desired := m.DeepCopy()
+		live := &unstructured.Unstructured{}
+		live.SetGroupVersionKind(desired.GroupVersionKind())
+		action := ActionUpdate
+		if err := c.Get(ctx, client.ObjectKeyFromObject(desired), live); err != nil {
+			if !kerrors.IsNotFound(err) {
+				return nil, errors.Wrapf(err, "cannot get %s %s", desired.GetKind(), objectName(desired.GetNamespace(), desired.GetName()))
+			}
+			action, live = ActionCreate, nil
+		}
+		if desired.GetAPIVersion() == "v1" && desired.GetKind() == "Secret" {
+			redactSecret(desired, live)
+		}
+		if live != nil {
+			live.Object = project(live.Object, desired.Object).(map[string]interface{})
+		}
+
+		desiredYAML, err := yaml.Marshal(desired.Object)
+		if err != nil {
+			return nil, err
+		}
+		rendered.WriteString("---\n")
+		rendered.Write(desiredYAML)
+
+		var liveYAML []byte
+		if live != nil {
+			if liveYAML, err = yaml.Marshal(live.Object); err != nil {
+				return nil, err
+			}
+		}
+		if string(liveYAML) == string(desiredYAML) {
+			continue
+		}
+		ref := objectName(desired.GetNamespace(), desired.GetName())
+		if live == nil {
+			result.Drifts = append(result.Drifts, fmt.Sprintf("%s %s: not found", desired.GetKind(), ref))
+		} else if paths := drifted("", live.Object, desired.Object); len(paths) > 0 {
+			result.Drifts = append(result.Drifts, fmt.Sprintf("%s %s: %s", desired.GetKind(), ref, strings.Join(paths, ", ")))
+		}
+		result.Changes = append(result.Changes, fmt.Sprintf("%s %s %s", action, desired.GetKind(), ref))
+		fmt.Fprintf(&diff, "--- live/%s/%s\n+++ rendered/%s/%s\n", desired.GetKind(), ref, desired.GetKind(), ref)
+		diff.WriteString(lineDiff(string(liveYAML), string(desiredYAML)))
+	}
+	for _, ref := range dispatch.Stale(tracked, manifests...) {
+		name := objectName(ref.Namespace, ref.Name)
+		result.Changes = append(result.Changes, fmt.Sprintf("%s %s %s", ActionPrune, ref.Kind, name))
+		fmt.Fprintf(&diff, "--- live/%s/%s\n+++ /dev/null\n", ref.Kind, name)
+	}
+	result.Manifests = rendered.String()
+	result.Diff = diff.String()
+	return result, nil
+}
+
+// project keeps the fields of live which are rendered in desired, so that the fields defaulted by the
+// api-server or managed by others are not reported as changes
+func project(live, desired interface{}) interface{} {
+	switch d := desired.(type) {
+	case map[string]interface{}:
+		l, ok := live.(map[string]interface{})
+		if !ok {
+			return live
+		}
+		out := make(map[string]interface{}, len(d))
+		for k := range d {
+			if v, ok := l[k]; ok {
+				out[k] = project(v, d[k])
+			}
+		}
+		return out
+	case []interface{}:
+		l, ok := live.([]interface{})
+		if !ok || len(l) != len(d) {
+			return live
+		}
+		out := make([]interface{}, len(l))
+		for i := range l {
+			out[i] = project(l[i], d[i])
+		}
+		return out
+	default:
+		return live
+	}
+}
+
+// drifted returns the paths of the fields rendered in desired whose live values differ
+func drifted(path string, live, desired interface{}) []string {
+	switch d := desired.(type) {
+	case map[string]interface{}:
+		l, ok := live.(map[string]interface{})
+		if !ok {
+			return []string{path}
+		}
+		keys := make([]string, 0, len(d))
+		for k := range d {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		var paths []string
+		for _, k := range keys {
+			p := k
+			if path != "" {
+				p = path + "." + k
+			}
+			v, ok := l[k]
+			if !ok {
+				paths = append(paths, p)
+				continue
+			}
+			paths = append(paths, drifted(p, v, d[k])...)
+		}
+		return paths
+	case []interface{}:
+		l, ok := live.([]interface{})
+		if !ok || len(l) != len(d) {
+			return []string{path}
+		}
+		var paths []string
+		for i := range d {
+			paths = append(paths, drifted(fmt.Sprintf("%s[%d]", path, i), l[i], d[i])...)
+		}
+		return paths
+	default:
+		// the numbers decoded from the template and the api-server may be of different types
+		liveJSON, _ := json.Marshal(live)
+		desiredJSON, _ := json.Marshal(desired)
+		if string(liveJSON) != string(desiredJSON) {
+			return []string{path}
+		}
+		return nil
+	}
+}
+
+// redactSecret replaces the values of the Secret data so that they are never stored by the dry-run,
+// the values of live which differ from the desired ones are marked as changed
+func redactSecret(desired, live *unstructured.Unstructured) {
+	var liveData map[string]interface{}
+	if live != nil {
+		liveData, _, _ = unstructured.NestedMap(live.Object, "data")
+	}
+	for _, field := range []string{"data", "stringData"} {
+		values, found, _ := unstructured.NestedMap(desired.Object, field)
+		if !found {
+			continue
+		}
+		liveValues := map[string]interface{}{}
+		for k, v := range values {
+			values[k] = redacted
+			current, ok := liveData[k]
+			if !ok {
+				continue
+			}
+			// the live Secret has no stringData, which is merged into its data by the api-server
+			if field == "stringData" {
+				v = base64.StdEncoding.EncodeToString([]byte(fmt.Sprint(v)))
+			}
+			liveValues[k] = redacted
+			if current != v {
+				liveValues[k] = redactedChanged
+			}
+		}
+		_ = unstructured.SetNestedMap(desired.Object, values, field)
+		if live != nil {
+			_ = unstructured.SetNestedMap(live.Object, liveValues, field)
+		}
+	}
+}
+
+// lineDiff returns the line based diff from the text a to b, the lines are prefixed with "-", "+" or " "
+func lineDiff(a, b string) string {
+	from, to := strings.SplitAfter(a, "\n"), strings.SplitAfter(b, "\n")
+	// lcs[i][j] is the length of the longest common lines of from[i:] and to[j:]
+	lcs := make([][]int, len(from)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(to)+1)
+	}
+	for i := len(from) - 1; i >= 0; i-- {
+		for j := len(to) - 1; j >= 0; j-- {
+			if from[i] == to[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var sb strings.Builder
+	write := func(prefix, line string) {
+		if line == "" {
+			return
+		}
+		sb.WriteString(prefix + line)
+		if !strings.HasSuffix(line, "\n") {
+			sb.WriteString("\n")
+		}
+	}
+	i, j := 0, 0
+	for i < len(from) && j < len(to) {
+		switch {
+		case from[i] == to[j]:
+			write(" ", from[i])
+			i, j = i+1, j+1
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			write("-", from[i])
+			i++
+		default:
+			write("+", to[j])
+			j++
+		}
+	}
+	for ; i < len(from); i++ {
+		write("-", from[i])
+	}
+	for ; j < len(to); j++ {
+		write("+", to[j])
+	}
+	return sb.String()
+}
+
+func objectName(namespace, name string) string {
+	if namespace == "" {
+		return name
+	}
+	return namespace + "/" + name
+}
@@ -0,0 +1,177 @@
+package dryrun
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	bdcv1alpha1 "kdp-oam-operator/api/bdc/v1alpha1"
+	pkgcommon "kdp-oam-operator/pkg/common"
+	"kdp-oam-operator/pkg/controllers/bdc/constants"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func manifest(kind, name string, fields map[string]interface{}) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{Object: fields}
+	u.SetAPIVersion("v1")
+	u.SetKind(kind)
+	u.SetNamespace("test-ns")
+	u.SetName(name)
+	return u
+}
+
+func TestDiff(t *testing.T) {
+	ctx := context.Background()
+	cli := fake.NewClientBuilder().WithObjects(
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "same", Namespace: "test-ns", Labels: map[string]string{"managed-by": "others"}},
+			Data:       map[string]string{"key": "value"},
+		},
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "changed", Namespace: "test-ns"},
+			Data:       map[string]string{"key": "old"},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "secret", Namespace: "test-ns"},
+			Data:       map[string][]byte{"same": []byte("s3cr3t"), "changed": []byte("old-s3cr3t")},
+		},
+	).Build()
+
+	manifests := []*unstructured.Unstructured{
+		manifest("ConfigMap", "same", map[string]interface{}{"data": map[string]interface{}{"key": "value"}}),
+		manifest("ConfigMap", "changed", map[string]interface{}{"data": map[string]interface{}{"key": "new"}}),
+		manifest("ConfigMap", "added", map[string]interface{}{"data": map[string]interface{}{"key": "value"}}),
+		manifest("Secret", "secret", map[string]interface{}{"stringData": map[string]interface{}{
+			"same": "s3cr3t", "changed": "new-s3cr3t",
+		}}),
+	}
+	tracked := []corev1.ObjectReference{
+		{APIVersion: "v1", Kind: "ConfigMap", Namespace: "test-ns", Name: "same"},
+		{APIVersion: "v1", Kind: "ConfigMap", Namespace: "test-ns", Name: "dropped"},
+	}
+	result, err := Diff(ctx, cli, tracked, manifests)
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+
+	wantChanges := []string{
+		"update ConfigMap test-ns/changed",
+		"create ConfigMap test-ns/added",
+		"update Secret test-ns/secret",
+		"prune ConfigMap test-ns/dropped",
+	}
+	if !reflect.DeepEqual(result.Changes, wantChanges) {
+		t.Errorf("Diff() changes = %v, want %v", result.Changes, wantChanges)
+	}
+
+	wantDrifts := []string{
+		"ConfigMap test-ns/changed: data.key",
+		"ConfigMap test-ns/added: not found",
+		"Secret test-ns/secret: stringData.changed",
+	}
+	if !reflect.DeepEqual(result.Drifts, wantDrifts) {
+		t.Errorf("Diff() drifts = %v, want %v", result.Drifts, wantDrifts)
+	}
+
+	tests := []struct {
+		name     string
+		text     string
+		contains []string
+		excludes []string
+	}{
+		{
+			name:     "diff of changed fields",
+			text:     result.Diff,
+			contains: []string{"-  key: old\n", "+  key: new\n", "+++ rendered/ConfigMap/test-ns/added\n", "+++ /dev/null\n"},
+			excludes: []string{"managed-by", "rendered/ConfigMap/test-ns/same"},
+		},
+		{
+			name:     "diff of secret",
+			text:     result.Diff,
+			contains: []string{"-  changed: <redacted, changed>\n", "+  changed: <redacted>\n", "   same: <redacted>\n"},
+			excludes: []string{"s3cr3t"},
+		},
+		{
+			name:     "rendered manifests",
+			text:     result.Manifests,
+			contains: []string{"name: same\n", "name: added\n", "changed: <redacted>\n"},
+			excludes: []string{"s3cr3t"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, s := range tt.contains {
+				if !strings.Contains(tt.text, s) {
+					t.Errorf("%q is not found in:\n%s", s, tt.text)
+				}
+			}
+			for _, s := range tt.excludes {
+				if strings.Contains(tt.text, s) {
+					t.Errorf("%q should not be found in:\n%s", s, tt.text)
+				}
+			}
+		})
+	}
+}
+
+func TestReconcileAndClear(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = bdcv1alpha1.AddToScheme(scheme)
+	setting := &bdcv1alpha1.ContextSetting{ObjectMeta: metav1.ObjectMeta{
+		Name:        "hdfs-setting",
+		UID:         "uid-setting",
+		Annotations: map[string]string{constants.AnnotationDryRun: "true"},
+	}}
+	cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(setting).Build()
+	if !Enabled(setting) {
+		t.Fatalf("Enabled() = false, want true")
+	}
+
+	manifests := []*unstructured.Unstructured{
+		manifest("ConfigMap", "hdfs-config", map[string]interface{}{"data": map[string]interface{}{"key": "value"}}),
+	}
+	if err := Reconcile(ctx, cli, setting, &setting.Status.DryRun, nil, manifests); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	cmKey := client.ObjectKey{Namespace: pkgcommon.SystemDefaultNamespace, Name: "dry-run-contextsetting-hdfs-setting"}
+	var cm corev1.ConfigMap
+	if err := cli.Get(ctx, cmKey, &cm); err != nil {
+		t.Fatalf("get dry-run ConfigMap error = %v", err)
+	}
+	if !strings.Contains(cm.Data[ManifestsKey], "name: hdfs-config") || cm.Data[DiffKey] == "" {
+		t.Errorf("dry-run ConfigMap data = %v", cm.Data)
+	}
+	if len(cm.OwnerReferences) != 1 || cm.OwnerReferences[0].UID != setting.UID {
+		t.Errorf("dry-run ConfigMap ownerReferences = %v", cm.OwnerReferences)
+	}
+	if err := cli.Get(ctx, client.ObjectKeyFromObject(setting), setting); err != nil {
+		t.Fatal(err)
+	}
+	if setting.Status.DryRun == nil || setting.Status.DryRun.ConfigMapRef != cmKey.Name ||
+		!reflect.DeepEqual(setting.Status.DryRun.Changes, []string{"create ConfigMap test-ns/hdfs-config"}) {
+		t.Errorf("dryRun status = %v", setting.Status.DryRun)
+	}
+
+	if err := Clear(ctx, cli, setting, &setting.Status.DryRun); err != nil {
+		t.Fatalf("Clear() error = %v", err)
+	}
+	if err := cli.Get(ctx, cmKey, &corev1.ConfigMap{}); !kerrors.IsNotFound(err) {
+		t.Errorf("dry-run ConfigMap should be deleted, got error %v", err)
+	}
+	if err := cli.Get(ctx, client.ObjectKeyFromObject(setting), setting); err != nil {
+		t.Fatal(err)
+	}
+	if setting.Status.DryRun != nil {
+		t.Errorf("dryRun status = %v, want nil", setting.Status.DryRun)
+	}
+}
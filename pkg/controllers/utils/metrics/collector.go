@@ -0,0 +1,180 @@
+/*
+Copyright 2024 KDP(Kubernetes Data Platform).
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/klog/v2"
+	bdcv1alpha1 "kdp-oam-operator/api/bdc/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// UnknownStatus is the status label of the objects whose status is not set yet
+const UnknownStatus = "Unknown"
+
+var (
+	objectsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "", "objects"),
+		"Number of objects by kind and status.",
+		[]string{"kind", "status"}, nil,
+	)
+	reconcileAgeDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "", "last_successful_reconcile_age_seconds"),
+		"Seconds since the last successful reconcile of an object.",
+		[]string{"kind", "name"}, nil,
+	)
+)
+
+// now is replaced in tests
+var now = time.Now
+
+type reconcileKey struct {
+	kind string
+	name string
+}
+
+// lastReconciles records the time of the last successful reconcile of objects
+var lastReconciles = struct {
+	sync.RWMutex
+	times map[reconcileKey]time.Time
+}{times: map[reconcileKey]time.Time{}}
+
+// RecordReconcile records a successful reconcile of the object kind/name
+func RecordReconcile(kind, name string) {
+	lastReconciles.Lock()
+	defer lastReconciles.Unlock()
+	lastReconciles.times[reconcileKey{kind: kind, name: name}] = now()
+}
+
+// InstrumentReconciler records the successful reconciles of r, whose objects are of kind
+func InstrumentReconciler(kind string, r reconcile.Reconciler) reconcile.Reconciler {
+	return reconcile.Func(func(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+		result, err := r.Reconcile(ctx, req)
+		if err == nil {
+			RecordReconcile(kind, req.Name)
+		}
+		return result, err
+	})
+}
+
+// collectedKind is a kind of objects collected by ObjectCollector
+type collectedKind struct {
+	kind    string
+	newList func() client.ObjectList
+}
+
+var collectedKinds = []collectedKind{
+	{kind: "BigDataCluster", newList: func() client.ObjectList { return &bdcv1alpha1.BigDataClusterList{} }},
+	{kind: "Application", newList: func() client.ObjectList { return &bdcv1alpha1.ApplicationList{} }},
+	{kind: "ContextSetting", newList: func() client.ObjectList { return &bdcv1alpha1.ContextSettingList{} }},
+	{kind: "ContextSecret", newList: func() client.ObjectList { return &bdcv1alpha1.ContextSecretList{} }},
+	{kind: "XDefinition", newList: func() client.ObjectList { return &bdcv1alpha1.XDefinitionList{} }},
+}
+
+// statusOf returns the status of obj, false if obj has no status to be counted
+func statusOf(obj interface{}) (string, bool) {
+	var status string
+	switch o := obj.(type) {
+	case *bdcv1alpha1.BigDataCluster:
+		status = string(o.Status.Status)
+	case *bdcv1alpha1.Application:
+		status = o.Status.Status
+	case *bdcv1alpha1.ContextSetting:
+		status = o.Status.Status
+	case *bdcv1alpha1.ContextSecret:
+		status = o.Status.Status
+	default:
+		return "", false
+	}
+	if status == "" {
+		status = UnknownStatus
+	}
+	return status, true
+}
+
+// ObjectCollector collects the number of objects by status and the age of their last successful reconcile
+// from the reader at scrape time, the reconcile records of the deleted objects are dropped meanwhile
+type ObjectCollector struct {
+	reader client.Reader
+}
+
+// NewObjectCollector creates an ObjectCollector reading objects from reader, which is usually the cache of manager
+func NewObjectCollector(reader client.Reader) *ObjectCollector {
+	return &ObjectCollector{reader: reader}
+}
+
+// Describe implements prometheus.Collector
+func (c *ObjectCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- objectsDesc
+	ch <- reconcileAgeDesc
+}
+
+// Collect implements prometheus.Collector
+func (c *ObjectCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	for _, ck := range collectedKinds {
+		list := ck.newList()
+		if err := c.reader.List(ctx, list); err != nil {
+			klog.ErrorS(err, "Failed to list objects for metrics", "kind", ck.kind)
+			continue
+		}
+		items, err := meta.ExtractList(list)
+		if err != nil {
+			klog.ErrorS(err, "Failed to extract objects for metrics", "kind", ck.kind)
+			continue
+		}
+
+		counts := map[string]int{}
+		names := map[string]bool{}
+		for _, item := range items {
+			if status, ok := statusOf(item); ok {
+				counts[status]++
+			}
+			if obj, ok := item.(client.Object); ok {
+				names[obj.GetName()] = true
+			}
+		}
+		for status, count := range counts {
+			ch <- prometheus.MustNewConstMetric(objectsDesc, prometheus.GaugeValue, float64(count), ck.kind, status)
+		}
+		c.collectReconcileAges(ch, ck.kind, names)
+	}
+}
+
+// collectReconcileAges collects the reconcile ages of the existing objects of kind, and drops the others
+func (c *ObjectCollector) collectReconcileAges(ch chan<- prometheus.Metric, kind string, names map[string]bool) {
+	lastReconciles.Lock()
+	defer lastReconciles.Unlock()
+	current := now()
+	for key, t := range lastReconciles.times {
+		if key.kind != kind {
+			continue
+		}
+		if !names[key.name] {
+			delete(lastReconciles.times, key)
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(reconcileAgeDesc, prometheus.GaugeValue, current.Sub(t).Seconds(), kind, key.name)
+	}
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2024 KDP(Kubernetes Data Platform).
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const namespace = "kdp_oam"
+
+const (
+	// AdoptionSucceeded and AdoptionFailed are the results of adopting an existing resource
+	AdoptionSucceeded = "succeeded"
+	AdoptionFailed    = "failed"
+)
+
+var (
+	// RenderDuration is the time of rendering the template of an XDefinition into manifests
+	RenderDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "render_duration_seconds",
+		Help:      "Time of rendering the template of an XDefinition into manifests.",
+		Buckets:   []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5},
+	}, []string{"xdefinition", "schematic"})
+
+	// ApplyDuration is the time of applying a manifest by the ManifestsDispatcher
+	ApplyDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "apply_duration_seconds",
+		Help:      "Time of applying a dispatched manifest.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"group", "version", "kind"})
+
+	// ApplyFailures counts the manifests failed to be applied by the ManifestsDispatcher
+	ApplyFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "apply_failures_total",
+		Help:      "Number of dispatched manifests failed to be applied.",
+	}, []string{"group", "version", "kind"})
+
+	// Adoptions counts the existing resources adopted by the syncers, e.g. ConfigMaps into ContextSettings
+	Adoptions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "adoptions_total",
+		Help:      "Number of existing resources adopted by the syncers.",
+	}, []string{"kind", "result"})
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(RenderDuration, ApplyDuration, ApplyFailures, Adoptions)
+}
+
+// ObserveRender records the rendering time of the template of xdefinition since start
+func ObserveRender(xdefinition, schematic string, start time.Time) {
+	RenderDuration.WithLabelValues(xdefinition, schematic).Observe(time.Since(start).Seconds())
+}
+
+// ObserveApply records the applying time of a manifest of gvk since start, and counts the failure if err is not nil
+func ObserveApply(gvk schema.GroupVersionKind, start time.Time, err error) {
+	ApplyDuration.WithLabelValues(gvk.Group, gvk.Version, gvk.Kind).Observe(time.Since(start).Seconds())
+	if err != nil {
+		ApplyFailures.WithLabelValues(gvk.Group, gvk.Version, gvk.Kind).Inc()
+	}
+}
+
+// RecordAdoption counts an adoption of a resource into kind
+func RecordAdoption(kind string, err error) {
+	result := AdoptionSucceeded
+	if err != nil {
+		result = AdoptionFailed
+	}
+	Adoptions.WithLabelValues(kind, result).Inc()
+}
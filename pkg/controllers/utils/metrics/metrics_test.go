@@ -0,0 +1,100 @@
+package metrics
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	bdcv1alpha1 "kdp-oam-operator/api/bdc/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestObjectCollector(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = bdcv1alpha1.AddToScheme(scheme)
+	cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&bdcv1alpha1.BigDataCluster{ObjectMeta: metav1.ObjectMeta{Name: "bdc-a"}, Status: bdcv1alpha1.BigDataClusterStatus{Status: "Active"}},
+		&bdcv1alpha1.BigDataCluster{ObjectMeta: metav1.ObjectMeta{Name: "bdc-b"}, Status: bdcv1alpha1.BigDataClusterStatus{Status: "Active"}},
+		&bdcv1alpha1.BigDataCluster{ObjectMeta: metav1.ObjectMeta{Name: "bdc-c"}, Status: bdcv1alpha1.BigDataClusterStatus{Status: "Frozen"}},
+		&bdcv1alpha1.Application{ObjectMeta: metav1.ObjectMeta{Name: "app-a"}, Status: bdcv1alpha1.ApplicationStatus{Status: "running"}},
+		&bdcv1alpha1.ContextSetting{ObjectMeta: metav1.ObjectMeta{Name: "setting-a"}},
+	).Build()
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	now = func() time.Time { return start }
+	defer func() { now = time.Now }()
+
+	succeeded := InstrumentReconciler("BigDataCluster", reconcile.Func(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+		return reconcile.Result{}, nil
+	}))
+	failed := InstrumentReconciler("BigDataCluster", reconcile.Func(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+		return reconcile.Result{}, errors.New("boom")
+	}))
+	for _, name := range []string{"bdc-a", "bdc-deleted"} {
+		if _, err := succeeded.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Name: name}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := failed.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "bdc-b"}}); err == nil {
+		t.Fatal("the error of the wrapped reconciler should be returned")
+	}
+	now = func() time.Time { return start.Add(30 * time.Second) }
+
+	want := `
+# HELP kdp_oam_last_successful_reconcile_age_seconds Seconds since the last successful reconcile of an object.
+# TYPE kdp_oam_last_successful_reconcile_age_seconds gauge
+kdp_oam_last_successful_reconcile_age_seconds{kind="BigDataCluster",name="bdc-a"} 30
+# HELP kdp_oam_objects Number of objects by kind and status.
+# TYPE kdp_oam_objects gauge
+kdp_oam_objects{kind="Application",status="running"} 1
+kdp_oam_objects{kind="BigDataCluster",status="Active"} 2
+kdp_oam_objects{kind="BigDataCluster",status="Frozen"} 1
+kdp_oam_objects{kind="ContextSetting",status="Unknown"} 1
+`
+	if err := testutil.CollectAndCompare(NewObjectCollector(cli), strings.NewReader(want)); err != nil {
+		t.Error(err)
+	}
+	if _, ok := lastReconciles.times[reconcileKey{kind: "BigDataCluster", name: "bdc-deleted"}]; ok {
+		t.Error("the reconcile record of the deleted object should be dropped")
+	}
+}
+
+func TestObserveApplyAndAdoption(t *testing.T) {
+	gvk := schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}
+	tests := []struct {
+		name         string
+		err          error
+		wantFailures float64
+	}{
+		{name: "applied", err: nil, wantFailures: 0},
+		{name: "failed", err: errors.New("conflict"), wantFailures: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ApplyFailures.Reset()
+			Adoptions.Reset()
+			ObserveApply(gvk, time.Now(), tt.err)
+			RecordAdoption("ContextSetting", tt.err)
+			if got := testutil.ToFloat64(ApplyFailures.WithLabelValues("apps", "v1", "Deployment")); got != tt.wantFailures {
+				t.Errorf("apply failures = %v, want %v", got, tt.wantFailures)
+			}
+			if got := testutil.ToFloat64(Adoptions.WithLabelValues("ContextSetting", AdoptionFailed)); got != tt.wantFailures {
+				t.Errorf("failed adoptions = %v, want %v", got, tt.wantFailures)
+			}
+			if got := testutil.ToFloat64(Adoptions.WithLabelValues("ContextSetting", AdoptionSucceeded)); got != 1-tt.wantFailures {
+				t.Errorf("succeeded adoptions = %v, want %v", got, 1-tt.wantFailures)
+			}
+		})
+	}
+	if got := testutil.CollectAndCount(ApplyDuration); got != 1 {
+		t.Errorf("apply duration series = %v, want 1", got)
+	}
+}
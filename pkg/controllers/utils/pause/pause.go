@@ -0,0 +1,66 @@
+/*
+Copyright 2023 KDP(Kubernetes Data Platform).
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pause
+
+import (
+	"context"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog/v2"
+	conditiontype "kdp-oam-operator/api/bdc/condition"
+	"kdp-oam-operator/pkg/controllers/bdc/constants"
+	"kdp-oam-operator/pkg/controllers/utils/condition"
+	"kdp-oam-operator/pkg/controllers/utils/dryrun"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Paused returns whether the reconciliation of obj is paused by the annotation.
+// The deleting objects are never paused, so that their downstream resources are still cleaned up.
+func Paused(obj metav1.Object) bool {
+	return obj.GetAnnotations()[constants.AnnotationPaused] == "true" && obj.GetDeletionTimestamp().IsZero()
+}
+
+// Pause sets the Paused condition of obj if it's not set yet
+func Pause(ctx context.Context, c client.StatusClient, obj condition.ConditionedObject) error {
+	if obj.GetCondition(conditiontype.TypePaused).Status == corev1.ConditionTrue {
+		return nil
+	}
+	klog.InfoS("Reconciliation is paused", "object", klog.KObj(obj))
+	return condition.PatchCondition(ctx, c, obj, conditiontype.Paused())
+}
+
+// Resume compares the manifests with the live objects if obj was paused, and records the downstream fields
+// drifted from the template during the pause in the Paused condition, before the manifests are applied again
+func Resume(ctx context.Context, c client.Client, obj condition.ConditionedObject, manifests []*unstructured.Unstructured) error {
+	if obj.GetCondition(conditiontype.TypePaused).Status != corev1.ConditionTrue {
+		return nil
+	}
+	result, err := dryrun.Diff(ctx, c, nil, manifests)
+	if err != nil {
+		return err
+	}
+	msg := "no downstream resource drifted from the template"
+	if len(result.Drifts) > 0 {
+		msg = "downstream resources drifted from the template: " + strings.Join(result.Drifts, "; ")
+		klog.InfoS("Downstream resources drifted during the pause", "object", klog.KObj(obj), "diff", result.Diff)
+	}
+	klog.InfoS("Reconciliation is resumed", "object", klog.KObj(obj), "drifts", len(result.Drifts))
+	return condition.PatchCondition(ctx, c, obj, conditiontype.Unpaused(msg))
+}
@@ -0,0 +1,91 @@
+package pause
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	conditiontype "kdp-oam-operator/api/bdc/condition"
+	bdcv1alpha1 "kdp-oam-operator/api/bdc/v1alpha1"
+	"kdp-oam-operator/pkg/controllers/bdc/constants"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestPaused(t *testing.T) {
+	now := metav1.Now()
+	tests := []struct {
+		name string
+		meta metav1.ObjectMeta
+		want bool
+	}{
+		{name: "not annotated", meta: metav1.ObjectMeta{}, want: false},
+		{name: "paused", meta: metav1.ObjectMeta{Annotations: map[string]string{constants.AnnotationPaused: "true"}}, want: true},
+		{name: "not true", meta: metav1.ObjectMeta{Annotations: map[string]string{constants.AnnotationPaused: "false"}}, want: false},
+		{
+			name: "deleting",
+			meta: metav1.ObjectMeta{Annotations: map[string]string{constants.AnnotationPaused: "true"}, DeletionTimestamp: &now},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Paused(&bdcv1alpha1.ContextSetting{ObjectMeta: tt.meta}); got != tt.want {
+				t.Errorf("Paused() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPauseAndResume(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = bdcv1alpha1.AddToScheme(scheme)
+	setting := &bdcv1alpha1.ContextSetting{ObjectMeta: metav1.ObjectMeta{
+		Name:        "hdfs-setting",
+		Annotations: map[string]string{constants.AnnotationPaused: "true"},
+	}}
+	cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		setting,
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "hdfs-config", Namespace: "test-ns"},
+			Data:       map[string]string{"replicas": "5", "owner": "hand-edited"},
+		},
+	).Build()
+
+	if err := Pause(ctx, cli, setting); err != nil {
+		t.Fatalf("Pause() error = %v", err)
+	}
+	if err := cli.Get(ctx, client.ObjectKeyFromObject(setting), setting); err != nil {
+		t.Fatal(err)
+	}
+	if paused := setting.GetCondition(conditiontype.TypePaused); paused.Status != corev1.ConditionTrue {
+		t.Fatalf("Paused condition = %v, want true", paused)
+	}
+
+	manifest := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]interface{}{"name": "hdfs-config", "namespace": "test-ns"},
+		"data":       map[string]interface{}{"replicas": "3"},
+	}}
+	if err := Resume(ctx, cli, setting, []*unstructured.Unstructured{manifest}); err != nil {
+		t.Fatalf("Resume() error = %v", err)
+	}
+	if err := cli.Get(ctx, client.ObjectKeyFromObject(setting), setting); err != nil {
+		t.Fatal(err)
+	}
+	paused := setting.GetCondition(conditiontype.TypePaused)
+	if paused.Status != corev1.ConditionFalse || paused.Reason != conditiontype.ReasonUnpaused {
+		t.Errorf("Paused condition = %v, want unpaused", paused)
+	}
+	if want := "ConfigMap test-ns/hdfs-config: data.replicas"; !strings.HasSuffix(paused.Message, want) {
+		t.Errorf("Paused condition message = %q, want drift %q", paused.Message, want)
+	}
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2023 KDP(Kubernetes Data Platform).
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package watch
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/klog/v2"
+	conditiontype "kdp-oam-operator/api/bdc/condition"
+	bdcv1alpha1 "kdp-oam-operator/api/bdc/v1alpha1"
+	"kdp-oam-operator/pkg/controllers/bdc/constants"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// EnqueueRequestsForBigDataCluster returns an EventHandler which enqueues the resources bound to
+// a BigDataCluster by annotation bdc.kdp.io/name, newList creates an empty list of the resources.
+func EnqueueRequestsForBigDataCluster(c client.Client, newList func() client.ObjectList) handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(bdc client.Object) []reconcile.Request {
+		objects, err := ListBoundObjects(context.Background(), c, bdc.GetName(), newList())
+		if err != nil {
+			klog.Errorf("list resources of bigdatacluster %s error: %v", bdc.GetName(), err)
+			return nil
+		}
+		requests := make([]reconcile.Request, 0, len(objects))
+		for _, obj := range objects {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(obj)})
+		}
+		return requests
+	})
+}
+
+// ListBoundObjects lists the resources bound to the BigDataCluster named bdcName
+func ListBoundObjects(ctx context.Context, c client.Client, bdcName string, list client.ObjectList) ([]client.Object, error) {
+	if err := c.List(ctx, list); err != nil {
+		return nil, err
+	}
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return nil, err
+	}
+	var objects []client.Object
+	for _, item := range items {
+		obj, ok := item.(client.Object)
+		if !ok || obj.GetAnnotations()[constants.AnnotationBDCName] != bdcName {
+			continue
+		}
+		objects = append(objects, obj)
+	}
+	return objects, nil
+}
+
+// EnqueueRequestForBoundBigDataCluster returns an EventHandler which enqueues the BigDataCluster a resource is bound to
+// by annotation bdc.kdp.io/name, the resources not bound to any BigDataCluster are ignored.
+func EnqueueRequestForBoundBigDataCluster() handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(obj client.Object) []reconcile.Request {
+		bdcName := obj.GetAnnotations()[constants.AnnotationBDCName]
+		if bdcName == "" {
+			return nil
+		}
+		return []reconcile.Request{{NamespacedName: client.ObjectKey{Name: bdcName}}}
+	})
+}
+
+// ApplicationStateChangedPredicate passes the creations and deletions of the applications, and the updates changing
+// their status or Suspended condition, which are reported in the status of their BigDataCluster.
+func ApplicationStateChangedPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldApp, ok := e.ObjectOld.(*bdcv1alpha1.Application)
+			newApp, ok2 := e.ObjectNew.(*bdcv1alpha1.Application)
+			if !ok || !ok2 {
+				return false
+			}
+			return oldApp.Status.Status != newApp.Status.Status ||
+				!oldApp.GetCondition(conditiontype.TypeSuspended).Equal(newApp.GetCondition(conditiontype.TypeSuspended)) ||
+				oldApp.GetAnnotations()[constants.AnnotationBDCName] != newApp.GetAnnotations()[constants.AnnotationBDCName]
+		},
+	}
+}
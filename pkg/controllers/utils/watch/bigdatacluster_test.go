@@ -0,0 +1,72 @@
+package watch
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"kdp-oam-operator/api/bdc/common"
+	conditiontype "kdp-oam-operator/api/bdc/condition"
+	bdcv1alpha1 "kdp-oam-operator/api/bdc/v1alpha1"
+	"kdp-oam-operator/pkg/controllers/bdc/constants"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllertest"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func boundApplication(bdcName string, status string, conditions ...conditiontype.Condition) *bdcv1alpha1.Application {
+	app := &bdcv1alpha1.Application{ObjectMeta: metav1.ObjectMeta{Name: "hdfs"}}
+	if bdcName != "" {
+		app.Annotations = map[string]string{constants.AnnotationBDCName: bdcName}
+	}
+	app.Status.Status = status
+	app.SetConditions(conditions...)
+	return app
+}
+
+func TestEnqueueRequestForBoundBigDataCluster(t *testing.T) {
+	mapper := EnqueueRequestForBoundBigDataCluster()
+	queue := &controllertest.Queue{Interface: workqueue.New()}
+	mapper.Create(event.CreateEvent{Object: boundApplication("", "running")}, queue)
+	if queue.Len() != 0 {
+		t.Fatalf("expected no request for the unbound application, got %d", queue.Len())
+	}
+	mapper.Create(event.CreateEvent{Object: boundApplication("bdc-a", "running")}, queue)
+	if queue.Len() != 1 {
+		t.Fatalf("expected 1 request, got %d", queue.Len())
+	}
+	if item, _ := queue.Get(); item != (reconcile.Request{NamespacedName: types.NamespacedName{Name: "bdc-a"}}) {
+		t.Fatalf("expected the request of bdc-a, got %v", item)
+	}
+}
+
+func TestApplicationStateChangedPredicate(t *testing.T) {
+	p := ApplicationStateChangedPredicate()
+	running := boundApplication("bdc-a", "running")
+	tests := []struct {
+		name   string
+		newApp *bdcv1alpha1.Application
+		want   bool
+	}{
+		{name: "unchanged", newApp: boundApplication("bdc-a", "running"), want: false},
+		{name: "status changed", newApp: boundApplication("bdc-a", common.ApplicationStarting), want: true},
+		{
+			name:   "suspended",
+			newApp: boundApplication("bdc-a", "running", conditiontype.Suspended(conditiontype.ReasonBigDataClusterDisabled)),
+			want:   true,
+		},
+		{
+			name:   "other condition changed",
+			newApp: boundApplication("bdc-a", "running", conditiontype.ReconcileSuccess()),
+			want:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := p.Update(event.UpdateEvent{ObjectOld: running, ObjectNew: tt.newApp}); got != tt.want {
+				t.Errorf("Update() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+/*
+Copyright 2023 KDP(Kubernetes Data Platform).
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package watch
+
+import (
+	"context"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+	bdcv1alpha1 "kdp-oam-operator/api/bdc/v1alpha1"
+	pkgcommon "kdp-oam-operator/pkg/common"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+)
+
+// EnqueueRequestsForKDPContext returns an EventHandler which enqueues the resources whose last render referred
+// to the keys changed in the KDP context ConfigMaps, newList creates an empty list of the resources.
+// The other ConfigMaps are ignored.
+func EnqueueRequestsForKDPContext(c client.Client, newList func() client.ObjectList, rolloutRate int) handler.EventHandler {
+	return &enqueueRequestsForKDPContext{
+		client:      c,
+		newList:     newList,
+		rolloutRate: rolloutRate,
+	}
+}
+
+type enqueueRequestsForKDPContext struct {
+	client      client.Client
+	newList     func() client.ObjectList
+	rolloutRate int
+}
+
+var _ handler.EventHandler = &enqueueRequestsForKDPContext{}
+
+// Create enqueues the resources referring to the keys of the new context ConfigMap
+func (e *enqueueRequestsForKDPContext) Create(evt event.CreateEvent, q workqueue.RateLimitingInterface) {
+	e.enqueue(ChangedContextKeys(nil, evt.Object), q)
+}
+
+// Update enqueues the resources referring to the keys changed
+func (e *enqueueRequestsForKDPContext) Update(evt event.UpdateEvent, q workqueue.RateLimitingInterface) {
+	e.enqueue(ChangedContextKeys(evt.ObjectOld, evt.ObjectNew), q)
+}
+
+// Delete enqueues the resources referring to the keys of the deleted context ConfigMap
+func (e *enqueueRequestsForKDPContext) Delete(evt event.DeleteEvent, q workqueue.RateLimitingInterface) {
+	e.enqueue(ChangedContextKeys(evt.Object, nil), q)
+}
+
+// Generic does nothing
+func (e *enqueueRequestsForKDPContext) Generic(event.GenericEvent, workqueue.RateLimitingInterface) {
+}
+
+func (e *enqueueRequestsForKDPContext) enqueue(changed []string, q workqueue.RateLimitingInterface) {
+	if len(changed) == 0 {
+		return
+	}
+	objects, err := ListContextReferrers(context.Background(), e.client, changed, e.newList())
+	if err != nil {
+		klog.Errorf("list resources referring to kdp context %v error: %v", changed, err)
+		return
+	}
+	klog.InfoS("Enqueue resources referring to changed kdp context", "keys", changed, "count", len(objects))
+	enqueueWithRolloutRate(q, objects, e.rolloutRate)
+}
+
+// ChangedContextKeys returns the sorted keys changed from oldObj to newObj, either of them is regarded as
+// empty if it's nil or not a KDP context ConfigMap
+func ChangedContextKeys(oldObj, newObj client.Object) []string {
+	oldData, newData := contextData(oldObj), contextData(newObj)
+	var changed []string
+	for k, v := range oldData {
+		if nv, ok := newData[k]; !ok || nv != v {
+			changed = append(changed, k)
+		}
+	}
+	for k := range newData {
+		if _, ok := oldData[k]; !ok {
+			changed = append(changed, k)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// ListContextReferrers lists the resources whose last render referred to any of keys
+func ListContextReferrers(ctx context.Context, c client.Client, keys []string, list client.ObjectList) ([]client.Object, error) {
+	if err := c.List(ctx, list); err != nil {
+		return nil, err
+	}
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return nil, err
+	}
+	changed := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		changed[k] = true
+	}
+	var objects []client.Object
+	for _, item := range items {
+		obj, ok := item.(client.Object)
+		if !ok {
+			continue
+		}
+		for _, k := range contextKeys(obj) {
+			if changed[k] {
+				objects = append(objects, obj)
+				break
+			}
+		}
+	}
+	return objects, nil
+}
+
+// IsKDPContext checks whether obj is a KDP context ConfigMap, whose data is merged into the context of templates
+func IsKDPContext(obj client.Object) bool {
+	if obj == nil || obj.GetNamespace() != pkgcommon.SystemDefaultNamespace {
+		return false
+	}
+	return obj.GetLabels()[pkgcommon.KdpContextLabelKey] == pkgcommon.KdpContextLabelValue
+}
+
+func contextData(obj client.Object) map[string]string {
+	cm, ok := obj.(*corev1.ConfigMap)
+	if !ok || !IsKDPContext(cm) {
+		return nil
+	}
+	return cm.Data
+}
+
+// contextKeys returns the keys of KDP context referred by the last render of obj
+func contextKeys(obj client.Object) []string {
+	switch o := obj.(type) {
+	case *bdcv1alpha1.Application:
+		return o.Status.ContextKeys
+	case *bdcv1alpha1.BigDataCluster:
+		return o.Status.ContextKeys
+	case *bdcv1alpha1.ContextSetting:
+		return o.Status.ContextKeys
+	case *bdcv1alpha1.ContextSecret:
+		return o.Status.ContextKeys
+	}
+	return nil
+}
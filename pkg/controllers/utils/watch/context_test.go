@@ -0,0 +1,84 @@
+package watch
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	bdcv1alpha1 "kdp-oam-operator/api/bdc/v1alpha1"
+	pkgcommon "kdp-oam-operator/pkg/common"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func contextConfigMap(labeled bool, data map[string]string) *corev1.ConfigMap {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "kdp-context", Namespace: pkgcommon.SystemDefaultNamespace},
+		Data:       data,
+	}
+	if labeled {
+		cm.Labels = map[string]string{pkgcommon.KdpContextLabelKey: pkgcommon.KdpContextLabelValue}
+	}
+	return cm
+}
+
+func TestChangedContextKeys(t *testing.T) {
+	tests := []struct {
+		name   string
+		oldObj client.Object
+		newObj client.Object
+		want   []string
+	}{
+		{
+			name:   "created",
+			newObj: contextConfigMap(true, map[string]string{"domain": "kdp.io", "storageClass": "default"}),
+			want:   []string{"domain", "storageClass"},
+		},
+		{
+			name:   "updated",
+			oldObj: contextConfigMap(true, map[string]string{"domain": "kdp.io", "storageClass": "default", "removed": "v"}),
+			newObj: contextConfigMap(true, map[string]string{"domain": "kdp.com", "storageClass": "default", "added": "v"}),
+			want:   []string{"added", "domain", "removed"},
+		},
+		{
+			name:   "label removed",
+			oldObj: contextConfigMap(true, map[string]string{"domain": "kdp.io"}),
+			newObj: contextConfigMap(false, map[string]string{"domain": "kdp.io"}),
+			want:   []string{"domain"},
+		},
+		{
+			name:   "not context",
+			oldObj: contextConfigMap(false, map[string]string{"domain": "kdp.io"}),
+			newObj: contextConfigMap(false, map[string]string{"domain": "kdp.com"}),
+			want:   nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ChangedContextKeys(tt.oldObj, tt.newObj); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ChangedContextKeys() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListContextReferrers(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = bdcv1alpha1.AddToScheme(scheme)
+	cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&bdcv1alpha1.Application{ObjectMeta: metav1.ObjectMeta{Name: "domain-app"}, Status: bdcv1alpha1.ApplicationStatus{ContextKeys: []string{"domain"}}},
+		&bdcv1alpha1.Application{ObjectMeta: metav1.ObjectMeta{Name: "storage-app"}, Status: bdcv1alpha1.ApplicationStatus{ContextKeys: []string{"storageClass"}}},
+		&bdcv1alpha1.Application{ObjectMeta: metav1.ObjectMeta{Name: "plain-app"}},
+	).Build()
+
+	objects, err := ListContextReferrers(context.Background(), cli, []string{"domain", "ingressClass"}, &bdcv1alpha1.ApplicationList{})
+	if err != nil {
+		t.Fatalf("ListContextReferrers() error = %v", err)
+	}
+	if len(objects) != 1 || objects[0].GetName() != "domain-app" {
+		t.Errorf("ListContextReferrers() got %d objects, want domain-app only", len(objects))
+	}
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2023 KDP(Kubernetes Data Platform).
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package watch
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+	"kdp-oam-operator/api/bdc/common"
+	bdcv1alpha1 "kdp-oam-operator/api/bdc/v1alpha1"
+	pkgcommon "kdp-oam-operator/pkg/common"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// DefinitionMapName is the ConfigMap storing the mapping from <type>-<kind> to XDefinition name
+const DefinitionMapName = "bdc-definition-map"
+
+// EnqueueRequestsForXDefinition returns an EventHandler which enqueues the resources of kind rendered by
+// a created or updated XDefinition, newList creates an empty list of the resources.
+// If rolloutRate is positive, at most rolloutRate resources are enqueued per second, the others are
+// delayed, so that a change of XDefinition doesn't re-render all the resources at once.
+func EnqueueRequestsForXDefinition(c client.Client, kind string, newList func() client.ObjectList, rolloutRate int) handler.EventHandler {
+	return &enqueueRequestsForXDefinition{
+		client:      c,
+		kind:        kind,
+		newList:     newList,
+		rolloutRate: rolloutRate,
+	}
+}
+
+type enqueueRequestsForXDefinition struct {
+	client      client.Client
+	kind        string
+	newList     func() client.ObjectList
+	rolloutRate int
+}
+
+var _ handler.EventHandler = &enqueueRequestsForXDefinition{}
+
+// Create enqueues the dependents, which may failed to render before the XDefinition is created
+func (e *enqueueRequestsForXDefinition) Create(evt event.CreateEvent, q workqueue.RateLimitingInterface) {
+	e.enqueue(evt.Object, q)
+}
+
+// Update enqueues the dependents of the new XDefinition
+func (e *enqueueRequestsForXDefinition) Update(evt event.UpdateEvent, q workqueue.RateLimitingInterface) {
+	e.enqueue(evt.ObjectNew, q)
+}
+
+// Delete does nothing, the dependents can't be rendered without XDefinition
+func (e *enqueueRequestsForXDefinition) Delete(event.DeleteEvent, workqueue.RateLimitingInterface) {}
+
+// Generic does nothing
+func (e *enqueueRequestsForXDefinition) Generic(event.GenericEvent, workqueue.RateLimitingInterface) {
+}
+
+func (e *enqueueRequestsForXDefinition) enqueue(obj client.Object, q workqueue.RateLimitingInterface) {
+	xDefinition, ok := obj.(*bdcv1alpha1.XDefinition)
+	if !ok || xDefinition.Spec.APIResource.Definition.Kind != e.kind {
+		return
+	}
+	objects, err := ListDependents(context.Background(), e.client, xDefinition, e.newList())
+	if err != nil {
+		klog.Errorf("list %s resources of xdefinition %s error: %v", e.kind, xDefinition.Name, err)
+		return
+	}
+	klog.InfoS("Enqueue resources rendered by changed xdefinition", "xdefinition", xDefinition.Name, "kind", e.kind, "count", len(objects))
+	enqueueWithRolloutRate(q, objects, e.rolloutRate)
+}
+
+// enqueueWithRolloutRate enqueues objects, at most rolloutRate objects per second if it's positive
+func enqueueWithRolloutRate(q workqueue.RateLimitingInterface, objects []client.Object, rolloutRate int) {
+	for i, o := range objects {
+		req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(o)}
+		if rolloutRate <= 0 {
+			q.Add(req)
+			continue
+		}
+		q.AddAfter(req, time.Duration(i/rolloutRate)*time.Second)
+	}
+}
+
+// ListDependents lists the resources rendered by xDefinition, which is looked up by bdc-definition-map
+// with the type and kind of the resource, the same as the parser.
+func ListDependents(ctx context.Context, c client.Client, xDefinition *bdcv1alpha1.XDefinition, list client.ObjectList) ([]client.Object, error) {
+	var cm corev1.ConfigMap
+	if err := c.Get(ctx, client.ObjectKey{Namespace: pkgcommon.SystemDefaultNamespace, Name: DefinitionMapName}, &cm); err != nil && !apierrors.IsNotFound(err) {
+		return nil, err
+	}
+	if err := c.List(ctx, list); err != nil {
+		return nil, err
+	}
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return nil, err
+	}
+	kind := xDefinition.Spec.APIResource.Definition.Kind
+	var objects []client.Object
+	for _, item := range items {
+		obj, ok := item.(client.Object)
+		if !ok {
+			continue
+		}
+		key := definitionMapKey(definitionType(obj), kind)
+		// the mapping may not be updated by the xdefinition controller yet
+		if resolveDefinitionName(cm.Data, obj, kind) != xDefinition.Name && key != definitionMapKey(xDefinition.Spec.APIResource.Definition.Type, kind) {
+			continue
+		}
+		objects = append(objects, obj)
+	}
+	return objects, nil
+}
+
+// resolveDefinitionName returns the name of XDefinition rendering obj, see deftemplate.GetBDCDefinition
+func resolveDefinitionName(definitionMap map[string]string, obj client.Object, kind string) string {
+	refDefName := definitionType(obj)
+	name := definitionMap[definitionMapKey(refDefName, kind)]
+	if name == "" && refDefName != "" {
+		name = refDefName
+	}
+	return name
+}
+
+func definitionMapKey(defType, kind string) string {
+	if defType == "" {
+		defType = common.DefaultAPIResourceType
+	}
+	return fmt.Sprintf("%s-%s", defType, kind)
+}
+
+// definitionType returns the spec.type of obj, which refers to a type of XDefinition
+func definitionType(obj client.Object) string {
+	switch o := obj.(type) {
+	case *bdcv1alpha1.Application:
+		return o.Spec.Type
+	case *bdcv1alpha1.ContextSetting:
+		return o.Spec.Type
+	case *bdcv1alpha1.ContextSecret:
+		return o.Spec.Type
+	}
+	return ""
+}
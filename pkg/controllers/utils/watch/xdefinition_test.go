@@ -0,0 +1,73 @@
+package watch
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	bdcv1alpha1 "kdp-oam-operator/api/bdc/v1alpha1"
+	pkgcommon "kdp-oam-operator/pkg/common"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestListDependents(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = bdcv1alpha1.AddToScheme(scheme)
+	cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: DefinitionMapName, Namespace: pkgcommon.SystemDefaultNamespace},
+			Data: map[string]string{
+				"default-ContextSetting": "default-setting-def",
+				"hdfs-ContextSetting":    "hdfs-setting-def",
+			},
+		},
+		&bdcv1alpha1.ContextSetting{ObjectMeta: metav1.ObjectMeta{Name: "default-setting"}},
+		&bdcv1alpha1.ContextSetting{ObjectMeta: metav1.ObjectMeta{Name: "hdfs-setting"}, Spec: bdcv1alpha1.ContextSettingSpec{Type: "hdfs"}},
+		&bdcv1alpha1.ContextSetting{ObjectMeta: metav1.ObjectMeta{Name: "hive-setting"}, Spec: bdcv1alpha1.ContextSettingSpec{Type: "hive"}},
+		&bdcv1alpha1.ContextSetting{ObjectMeta: metav1.ObjectMeta{Name: "by-name-setting"}, Spec: bdcv1alpha1.ContextSettingSpec{Type: "by-name-def"}},
+	).Build()
+
+	definition := func(name, defType string) *bdcv1alpha1.XDefinition {
+		xdef := &bdcv1alpha1.XDefinition{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		xdef.Spec.APIResource.Definition.Kind = "ContextSetting"
+		xdef.Spec.APIResource.Definition.Type = defType
+		return xdef
+	}
+	tests := []struct {
+		name        string
+		xDefinition *bdcv1alpha1.XDefinition
+		want        []string
+	}{
+		{name: "default type", xDefinition: definition("default-setting-def", ""), want: []string{"default-setting"}},
+		{name: "mapped type", xDefinition: definition("hdfs-setting-def", "hdfs"), want: []string{"hdfs-setting"}},
+		{name: "mapping not updated yet", xDefinition: definition("hive-setting-def", "hive"), want: []string{"hive-setting"}},
+		{name: "type refers to definition name", xDefinition: definition("by-name-def", "by-name"), want: []string{"by-name-setting"}},
+		{name: "no dependents", xDefinition: definition("kafka-setting-def", "kafka"), want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objects, err := ListDependents(context.Background(), cli, tt.xDefinition, &bdcv1alpha1.ContextSettingList{})
+			if err != nil {
+				t.Fatalf("ListDependents() error = %v", err)
+			}
+			var got []string
+			for _, obj := range objects {
+				got = append(got, obj.GetName())
+			}
+			sort.Strings(got)
+			if len(got) != len(tt.want) {
+				t.Fatalf("ListDependents() got = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("ListDependents() got = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2023 KDP(Kubernetes Data Platform).
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	velacommon "github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	velav1beta1 "github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	"kdp-oam-operator/pkg/controllers/bdc/constants"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// scalerTrait is the built-in trait of KubeVela setting the replicas of the deployments and statefulsets
+const scalerTrait = "scaler"
+
+// scalableKinds are the workloads the scaler trait applies to
+var scalableKinds = sets.NewString("Deployment", "StatefulSet")
+
+// zeroReplicas is the properties of the scaler trait suspending the workload
+var zeroReplicas = []byte(`{"replicas":0}`)
+
+// Suspend renders the scalable workloads of the vela application with zero replicas by the scaler trait, so that
+// the replicas stay owned by KubeVela instead of being patched on the workloads. The original scaler traits are
+// recorded in an annotation of the vela application for Resume. It returns true once KubeVela has applied the
+// suspension, the components whose workloads are unknown yet are suspended once they're reported in the status.
+func Suspend(ctx context.Context, c client.Client, app *velav1beta1.Application) (bool, error) {
+	suspended, err := suspendedComponents(app)
+	if err != nil {
+		return false, err
+	}
+	scalable := sets.NewString()
+	for _, service := range app.Status.Services {
+		if scalableKinds.Has(service.WorkloadDefinition.Kind) {
+			scalable.Insert(service.Name)
+		}
+	}
+
+	patch := client.MergeFromWithOptions(app.DeepCopy(), client.MergeFromWithOptimisticLock{})
+	changed := false
+	for i := range app.Spec.Components {
+		comp := &app.Spec.Components[i]
+		if _, ok := suspended[comp.Name]; ok || !scalable.Has(comp.Name) {
+			continue
+		}
+		// nil records that the scaler trait is added by the suspension
+		var original *runtime.RawExtension
+		if j := traitIndex(comp.Traits, scalerTrait); j >= 0 {
+			original = comp.Traits[j].Properties
+			comp.Traits[j].Properties = &runtime.RawExtension{Raw: zeroReplicas}
+		} else {
+			comp.Traits = append(comp.Traits, velacommon.ApplicationTrait{Type: scalerTrait, Properties: &runtime.RawExtension{Raw: zeroReplicas}})
+		}
+		suspended[comp.Name] = original
+		changed = true
+	}
+	if !changed {
+		return app.Status.ObservedGeneration >= app.Generation, nil
+	}
+	if err := setSuspendedComponents(app, suspended); err != nil {
+		return false, err
+	}
+	if err := c.Patch(ctx, app, patch); err != nil {
+		return false, fmt.Errorf(constants.ErrSuspendVelaApplication, app.Namespace, app.Name, err)
+	}
+	klog.InfoS("Suspended vela application", "application", klog.KObj(app), "components", len(suspended))
+	return false, nil
+}
+
+// Resume reverts the scaler traits set by Suspend. The traits already replaced by a new render of the template are
+// left untouched.
+func Resume(ctx context.Context, c client.Client, app *velav1beta1.Application) error {
+	if _, ok := app.GetAnnotations()[constants.AnnotationSuspendedComponents]; !ok {
+		return nil
+	}
+	suspended, err := suspendedComponents(app)
+	if err != nil {
+		return err
+	}
+
+	patch := client.MergeFromWithOptions(app.DeepCopy(), client.MergeFromWithOptimisticLock{})
+	for i := range app.Spec.Components {
+		comp := &app.Spec.Components[i]
+		original, ok := suspended[comp.Name]
+		j := traitIndex(comp.Traits, scalerTrait)
+		if !ok || j < 0 || comp.Traits[j].Properties == nil || !jsonEqual(comp.Traits[j].Properties.Raw, zeroReplicas) {
+			continue
+		}
+		if original == nil {
+			comp.Traits = append(comp.Traits[:j], comp.Traits[j+1:]...)
+		} else {
+			comp.Traits[j].Properties = original
+		}
+	}
+	annotations := app.GetAnnotations()
+	delete(annotations, constants.AnnotationSuspendedComponents)
+	app.SetAnnotations(annotations)
+	if err := c.Patch(ctx, app, patch); err != nil {
+		return fmt.Errorf(constants.ErrSuspendVelaApplication, app.Namespace, app.Name, err)
+	}
+	klog.InfoS("Resumed vela application", "application", klog.KObj(app), "components", len(suspended))
+	return nil
+}
+
+func suspendedComponents(app *velav1beta1.Application) (map[string]*runtime.RawExtension, error) {
+	suspended := map[string]*runtime.RawExtension{}
+	if value, ok := app.GetAnnotations()[constants.AnnotationSuspendedComponents]; ok {
+		if err := json.Unmarshal([]byte(value), &suspended); err != nil {
+			return nil, fmt.Errorf(constants.ErrSuspendVelaApplication, app.Namespace, app.Name, err)
+		}
+	}
+	return suspended, nil
+}
+
+func setSuspendedComponents(app *velav1beta1.Application, suspended map[string]*runtime.RawExtension) error {
+	value, err := json.Marshal(suspended)
+	if err != nil {
+		return err
+	}
+	annotations := app.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[constants.AnnotationSuspendedComponents] = string(value)
+	app.SetAnnotations(annotations)
+	return nil
+}
+
+func traitIndex(traits []velacommon.ApplicationTrait, traitType string) int {
+	for i, trait := range traits {
+		if trait.Type == traitType {
+			return i
+		}
+	}
+	return -1
+}
+
+func jsonEqual(a, b []byte) bool {
+	var va, vb interface{}
+	if json.Unmarshal(a, &va) != nil || json.Unmarshal(b, &vb) != nil {
+		return false
+	}
+	return reflect.DeepEqual(va, vb)
+}
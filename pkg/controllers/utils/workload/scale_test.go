@@ -0,0 +1,116 @@
+package workload
+
+import (
+	"context"
+	"testing"
+
+	velacommon "github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	velav1beta1 "github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"kdp-oam-operator/pkg/controllers/bdc/constants"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func properties(raw string) *runtime.RawExtension {
+	return &runtime.RawExtension{Raw: []byte(raw)}
+}
+
+func TestSuspendAndResume(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	_ = velav1beta1.AddToScheme(scheme)
+	app := &velav1beta1.Application{
+		ObjectMeta: metav1.ObjectMeta{Name: "hdfs", Namespace: "test-ns", Generation: 1},
+		Spec: velav1beta1.ApplicationSpec{Components: []velacommon.ApplicationComponent{
+			{Name: "namenode", Type: "k8s-objects"},
+			{Name: "datanode", Type: "k8s-objects", Traits: []velacommon.ApplicationTrait{{Type: "scaler", Properties: properties(`{"replicas":3}`)}}},
+			{Name: "config", Type: "k8s-objects"},
+		}},
+		Status: velacommon.AppStatus{
+			ObservedGeneration: 1,
+			Services: []velacommon.ApplicationComponentStatus{
+				{Name: "namenode", WorkloadDefinition: velacommon.WorkloadGVK{APIVersion: "apps/v1", Kind: "StatefulSet"}},
+				{Name: "datanode", WorkloadDefinition: velacommon.WorkloadGVK{APIVersion: "apps/v1", Kind: "Deployment"}},
+				{Name: "config", WorkloadDefinition: velacommon.WorkloadGVK{APIVersion: "v1", Kind: "ConfigMap"}},
+			},
+		},
+	}
+	cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(app).Build()
+	key := client.ObjectKeyFromObject(app)
+
+	var got velav1beta1.Application
+	if err := cli.Get(ctx, key, &got); err != nil {
+		t.Fatal(err)
+	}
+	done, err := Suspend(ctx, cli, &got)
+	if err != nil || done {
+		t.Fatalf("Suspend() done = %v, err = %v, want false, nil", done, err)
+	}
+	if err := cli.Get(ctx, key, &got); err != nil {
+		t.Fatal(err)
+	}
+	for _, comp := range got.Spec.Components[:2] {
+		if i := traitIndex(comp.Traits, scalerTrait); i < 0 || !jsonEqual(comp.Traits[i].Properties.Raw, zeroReplicas) {
+			t.Fatalf("Suspend() component %s traits = %v, want zero replicas", comp.Name, comp.Traits)
+		}
+	}
+	if len(got.Spec.Components[1].Traits) != 1 || len(got.Spec.Components[2].Traits) != 0 {
+		t.Fatalf("Suspend() traits = %v, want the existing scaler replaced and the unscalable component untouched", got.Spec.Components)
+	}
+
+	// the fake client doesn't bump the generation on the spec change
+	got.Generation++
+	// waiting for KubeVela to render the suspended components
+	if done, err = Suspend(ctx, cli, &got); err != nil || done {
+		t.Fatalf("Suspend() done = %v, err = %v before KubeVela observes the suspension", done, err)
+	}
+	got.Status.ObservedGeneration = got.Generation
+	if done, err = Suspend(ctx, cli, &got); err != nil || !done {
+		t.Fatalf("Suspend() done = %v, err = %v, want true, nil", done, err)
+	}
+
+	if err := Resume(ctx, cli, &got); err != nil {
+		t.Fatalf("Resume() err = %v", err)
+	}
+	if err := cli.Get(ctx, key, &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Spec.Components[0].Traits) != 0 {
+		t.Errorf("Resume() namenode traits = %v, want the added scaler removed", got.Spec.Components[0].Traits)
+	}
+	if traits := got.Spec.Components[1].Traits; len(traits) != 1 || !jsonEqual(traits[0].Properties.Raw, []byte(`{"replicas":3}`)) {
+		t.Errorf("Resume() datanode traits = %v, want the original scaler", traits)
+	}
+	if _, ok := got.Annotations[constants.AnnotationSuspendedComponents]; ok {
+		t.Errorf("Resume() should remove annotation %s", constants.AnnotationSuspendedComponents)
+	}
+}
+
+func TestResumeRerendered(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	_ = velav1beta1.AddToScheme(scheme)
+	// the template is dispatched again before the resume, which replaces the suspended scaler
+	app := &velav1beta1.Application{
+		ObjectMeta: metav1.ObjectMeta{Name: "hdfs", Namespace: "test-ns", Annotations: map[string]string{
+			constants.AnnotationSuspendedComponents: `{"datanode":{"replicas":3}}`,
+		}},
+		Spec: velav1beta1.ApplicationSpec{Components: []velacommon.ApplicationComponent{
+			{Name: "datanode", Type: "k8s-objects", Traits: []velacommon.ApplicationTrait{{Type: "scaler", Properties: properties(`{"replicas":5}`)}}},
+		}},
+	}
+	cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(app).Build()
+
+	if err := Resume(ctx, cli, app); err != nil {
+		t.Fatalf("Resume() err = %v", err)
+	}
+	var got velav1beta1.Application
+	if err := cli.Get(ctx, client.ObjectKeyFromObject(app), &got); err != nil {
+		t.Fatal(err)
+	}
+	if traits := got.Spec.Components[0].Traits; len(traits) != 1 || !jsonEqual(traits[0].Properties.Raw, []byte(`{"replicas":5}`)) {
+		t.Errorf("Resume() traits = %v, want the rendered scaler kept", traits)
+	}
+}
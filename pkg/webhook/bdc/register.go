@@ -17,7 +17,11 @@ limitations under the License.
 package bdc
 
 import (
+	"kdp-oam-operator/pkg/webhook/bdc/v1alpha1/application"
 	"kdp-oam-operator/pkg/webhook/bdc/v1alpha1/bigdatacluster"
+	"kdp-oam-operator/pkg/webhook/bdc/v1alpha1/contextsecret"
+	"kdp-oam-operator/pkg/webhook/bdc/v1alpha1/contextsetting"
+	"kdp-oam-operator/pkg/webhook/bdc/v1alpha1/xdefinition"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
 )
@@ -25,6 +29,10 @@ import (
 func Register(mgr manager.Manager) {
 	bigdatacluster.RegisterValidatingHandler(mgr)
 	bigdatacluster.RegisterMutatingHandler(mgr)
+	application.RegisterValidatingHandler(mgr)
+	contextsetting.RegisterValidatingHandler(mgr)
+	contextsecret.RegisterValidatingHandler(mgr)
+	xdefinition.RegisterValidatingHandler(mgr)
 
 	server := mgr.GetWebhookServer()
 	server.Register("/convert", &conversion.Webhook{})
@@ -0,0 +1,54 @@
+/*
+Copyright 2023 KDP(Kubernetes Data Platform).
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	"context"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	bdcv1alpha1 "kdp-oam-operator/api/bdc/v1alpha1"
+	"kdp-oam-operator/pkg/controllers/bdc/constants"
+	webhookutils "kdp-oam-operator/pkg/webhook/utils"
+)
+
+// ValidateCreate validates the Application on creation
+func (h *ValidatingHandler) ValidateCreate(ctx context.Context, application *bdcv1alpha1.Application) field.ErrorList {
+	var allErrs field.ErrorList
+	allErrs = append(allErrs, webhookutils.ValidateBigDataClusterWritable(ctx, h.Client, application)...)
+	if len(allErrs) > 0 {
+		return allErrs
+	}
+	allErrs = append(allErrs, webhookutils.ValidateProperties(ctx, h.Client, application, "Application", application.Spec.Type, application.Spec.Properties)...)
+	return allErrs
+}
+
+// ValidateUpdate validates the Application on update
+func (h *ValidatingHandler) ValidateUpdate(ctx context.Context, newApplication, oldApplication *bdcv1alpha1.Application) field.ErrorList {
+	var allErrs field.ErrorList
+	// changes of metadata only, such as labels and finalizers, are always allowed
+	if apiequality.Semantic.DeepEqual(newApplication.Spec, oldApplication.Spec) &&
+		newApplication.GetAnnotations()[constants.AnnotationBDCName] == oldApplication.GetAnnotations()[constants.AnnotationBDCName] {
+		return allErrs
+	}
+	allErrs = append(allErrs, webhookutils.ValidateBigDataClusterWritable(ctx, h.Client, newApplication)...)
+	if len(allErrs) > 0 {
+		return allErrs
+	}
+	allErrs = append(allErrs, webhookutils.ValidateProperties(ctx, h.Client, newApplication, "Application", newApplication.Spec.Type, newApplication.Spec.Properties)...)
+	return allErrs
+}
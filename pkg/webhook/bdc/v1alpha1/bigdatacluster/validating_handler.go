@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	bigdatacluster "kdp-oam-operator/api/bdc/v1alpha1"
+	webhookutils "kdp-oam-operator/pkg/webhook/utils"
 	"net/http"
 
 	admissionv1 "k8s.io/api/admission/v1"
@@ -71,14 +72,6 @@ func simplifyError(err error) error {
 	}
 }
 
-func mergeErrors(errs field.ErrorList) error {
-	s := ""
-	for _, err := range errs {
-		s += fmt.Sprintf("field \"%s\": %s error encountered, %s. ", err.Field, err.Type, err.Detail)
-	}
-	return fmt.Errorf(s)
-}
-
 // Handle validate BigDataCluster Spec here
 func (h *ValidatingHandler) Handle(ctx context.Context, req admission.Request) admission.Response {
 	bdc := &bigdatacluster.BigDataCluster{}
@@ -88,7 +81,7 @@ func (h *ValidatingHandler) Handle(ctx context.Context, req admission.Request) a
 	switch req.Operation {
 	case admissionv1.Create:
 		if allErrs := h.ValidateCreate(ctx, bdc); len(allErrs) > 0 {
-			return admission.Errored(http.StatusUnprocessableEntity, mergeErrors(allErrs))
+			return webhookutils.InvalidResponse("BigDataCluster", bdc.Name, allErrs)
 		}
 	case admissionv1.Update:
 		oldBdc := &bigdatacluster.BigDataCluster{}
@@ -97,7 +90,7 @@ func (h *ValidatingHandler) Handle(ctx context.Context, req admission.Request) a
 		}
 		if bdc.ObjectMeta.DeletionTimestamp.IsZero() {
 			if allErrs := h.ValidateUpdate(ctx, bdc, oldBdc); len(allErrs) > 0 {
-				return admission.Errored(http.StatusUnprocessableEntity, mergeErrors(allErrs))
+				return webhookutils.InvalidResponse("BigDataCluster", bdc.Name, allErrs)
 			}
 		}
 	default:
@@ -18,15 +18,22 @@ package bigdatacluster
 
 import (
 	"context"
-	_ "k8s.io/apimachinery/pkg/runtime/schema"
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	bdc "kdp-oam-operator/api/bdc/v1alpha1"
+	"kdp-oam-operator/pkg/controllers/bdc/constants"
 )
 
 // ValidateCreate validates the BigDataCluster on creation
 func (h *ValidatingHandler) ValidateCreate(ctx context.Context, bdc *bdc.BigDataCluster) field.ErrorList {
 	var allErrs field.ErrorList
-
+	allErrs = append(allErrs, validateNamespaces(bdc.Spec.Namespaces, namespacesPath())...)
+	allErrs = append(allErrs, h.validateNamespacesUnclaimed(ctx, bdc)...)
 	return allErrs
 }
 
@@ -34,6 +41,118 @@ func (h *ValidatingHandler) ValidateCreate(ctx context.Context, bdc *bdc.BigData
 func (h *ValidatingHandler) ValidateUpdate(ctx context.Context, newBdc, oldBDC *bdc.BigDataCluster) field.ErrorList {
 	// check if the newBdc is valid
 	errs := h.ValidateCreate(ctx, newBdc)
-	// TODO: add more validating
+	errs = append(errs, h.validateNamespacesRemovable(ctx, newBdc, oldBDC)...)
 	return errs
 }
+
+// validateNamespaces validates that the namespaces are valid DNS-1123 labels without duplicates,
+// and exactly one of them is the default namespace
+func validateNamespaces(namespaces []bdc.Namespace, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	names := sets.NewString()
+	defaultNamespace := ""
+	for i, ns := range namespaces {
+		namePath := path.Index(i).Child("name")
+		for _, msg := range validation.IsDNS1123Label(ns.Name) {
+			allErrs = append(allErrs, field.Invalid(namePath, ns.Name, msg))
+		}
+		if names.Has(ns.Name) {
+			allErrs = append(allErrs, field.Duplicate(namePath, ns.Name))
+		}
+		names.Insert(ns.Name)
+		if !ns.IsDefault {
+			continue
+		}
+		if defaultNamespace != "" {
+			allErrs = append(allErrs, field.Invalid(path.Index(i).Child("isDefault"), ns.IsDefault,
+				fmt.Sprintf("only one namespace can be default, %s is already the default namespace", defaultNamespace)))
+			continue
+		}
+		defaultNamespace = ns.Name
+	}
+	if defaultNamespace == "" {
+		allErrs = append(allErrs, field.Required(path, "exactly one namespace must be the default namespace"))
+	}
+	return allErrs
+}
+
+// validateNamespacesUnclaimed validates that the namespaces are not claimed by the other BigDataClusters
+func (h *ValidatingHandler) validateNamespacesUnclaimed(ctx context.Context, bigDataCluster *bdc.BigDataCluster) field.ErrorList {
+	var allErrs field.ErrorList
+	var bdcList bdc.BigDataClusterList
+	if err := h.Client.List(ctx, &bdcList); err != nil {
+		return append(allErrs, field.InternalError(namespacesPath(), err))
+	}
+	claimedBy := make(map[string]string)
+	for _, other := range bdcList.Items {
+		if other.Name == bigDataCluster.Name {
+			continue
+		}
+		for _, ns := range other.Spec.Namespaces {
+			claimedBy[ns.Name] = other.Name
+		}
+	}
+	for i, ns := range bigDataCluster.Spec.Namespaces {
+		if owner, ok := claimedBy[ns.Name]; ok {
+			allErrs = append(allErrs, field.Forbidden(namespacesPath().Index(i).Child("name"),
+				fmt.Sprintf(constants.ErrNamespaceClaimed, ns.Name, owner)))
+		}
+	}
+	return allErrs
+}
+
+// validateNamespacesRemovable validates that the namespaces removed from the BigDataCluster hold no Applications.
+// An Application is held by the namespaces of its output resources, or by the default namespace if it's not rendered yet.
+func (h *ValidatingHandler) validateNamespacesRemovable(ctx context.Context, newBdc, oldBDC *bdc.BigDataCluster) field.ErrorList {
+	var allErrs field.ErrorList
+	newNamespaces := sets.NewString()
+	for _, ns := range newBdc.Spec.Namespaces {
+		newNamespaces.Insert(ns.Name)
+	}
+	removed := sets.NewString()
+	oldDefault := ""
+	for _, ns := range oldBDC.Spec.Namespaces {
+		if !newNamespaces.Has(ns.Name) {
+			removed.Insert(ns.Name)
+		}
+		if ns.IsDefault {
+			oldDefault = ns.Name
+		}
+	}
+	if removed.Len() == 0 {
+		return allErrs
+	}
+
+	var appList bdc.ApplicationList
+	if err := h.Client.List(ctx, &appList); err != nil {
+		return append(allErrs, field.InternalError(namespacesPath(), err))
+	}
+	heldApps := make(map[string][]string)
+	for _, app := range appList.Items {
+		if app.Annotations[constants.AnnotationBDCName] != oldBDC.Name {
+			continue
+		}
+		held := sets.NewString()
+		for _, ref := range app.Status.OutputResources {
+			held.Insert(ref.Namespace)
+		}
+		if len(app.Status.OutputResources) == 0 {
+			held.Insert(oldDefault)
+		}
+		for _, ns := range held.Intersection(removed).UnsortedList() {
+			heldApps[ns] = append(heldApps[ns], app.Name)
+		}
+	}
+	for _, ns := range removed.List() {
+		if apps := heldApps[ns]; len(apps) > 0 {
+			sort.Strings(apps)
+			allErrs = append(allErrs, field.Forbidden(namespacesPath(),
+				fmt.Sprintf(constants.ErrNamespaceHoldsApplications, ns, strings.Join(apps, ", "))))
+		}
+	}
+	return allErrs
+}
+
+func namespacesPath() *field.Path {
+	return field.NewPath("spec", "namespaces")
+}
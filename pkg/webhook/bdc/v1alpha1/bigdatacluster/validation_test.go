@@ -0,0 +1,130 @@
+package bigdatacluster
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	bdcv1alpha1 "kdp-oam-operator/api/bdc/v1alpha1"
+	"kdp-oam-operator/pkg/controllers/bdc/constants"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func bigDataCluster(name string, namespaces ...bdcv1alpha1.Namespace) *bdcv1alpha1.BigDataCluster {
+	return &bdcv1alpha1.BigDataCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       bdcv1alpha1.BigDataClusterSpec{Namespaces: namespaces},
+	}
+}
+
+func newHandler() *ValidatingHandler {
+	scheme := runtime.NewScheme()
+	_ = bdcv1alpha1.AddToScheme(scheme)
+	cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		bigDataCluster("bdc-other", bdcv1alpha1.Namespace{Name: "claimed", IsDefault: true}),
+		&bdcv1alpha1.Application{
+			ObjectMeta: metav1.ObjectMeta{Name: "hdfs", Annotations: map[string]string{constants.AnnotationBDCName: "bdc-test"}},
+			Status: bdcv1alpha1.ApplicationStatus{OutputResources: []corev1.ObjectReference{
+				{Kind: "Application", Namespace: "data", Name: "hdfs"},
+			}},
+		},
+		&bdcv1alpha1.Application{
+			ObjectMeta: metav1.ObjectMeta{Name: "pending", Annotations: map[string]string{constants.AnnotationBDCName: "bdc-test"}},
+		},
+	).Build()
+	return &ValidatingHandler{Client: cli}
+}
+
+func TestValidateCreate(t *testing.T) {
+	tests := []struct {
+		name       string
+		namespaces []bdcv1alpha1.Namespace
+		wantFields []string
+	}{
+		{
+			name:       "valid",
+			namespaces: []bdcv1alpha1.Namespace{{Name: "default-ns", IsDefault: true}, {Name: "data"}},
+		},
+		{
+			name:       "no default namespace",
+			namespaces: []bdcv1alpha1.Namespace{{Name: "data"}},
+			wantFields: []string{"spec.namespaces"},
+		},
+		{
+			name:       "more than one default namespace",
+			namespaces: []bdcv1alpha1.Namespace{{Name: "default-ns", IsDefault: true}, {Name: "data", IsDefault: true}},
+			wantFields: []string{"spec.namespaces[1].isDefault"},
+		},
+		{
+			name:       "duplicate namespace",
+			namespaces: []bdcv1alpha1.Namespace{{Name: "data", IsDefault: true}, {Name: "data"}},
+			wantFields: []string{"spec.namespaces[1].name"},
+		},
+		{
+			name:       "invalid namespace name",
+			namespaces: []bdcv1alpha1.Namespace{{Name: "Data_NS", IsDefault: true}},
+			wantFields: []string{"spec.namespaces[0].name"},
+		},
+		{
+			name:       "namespace claimed by another bigdatacluster",
+			namespaces: []bdcv1alpha1.Namespace{{Name: "data", IsDefault: true}, {Name: "claimed"}},
+			wantFields: []string{"spec.namespaces[1].name"},
+		},
+	}
+	h := newHandler()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := h.ValidateCreate(context.Background(), bigDataCluster("bdc-test", tt.namespaces...))
+			assertFields(t, errs, tt.wantFields)
+		})
+	}
+}
+
+func TestValidateUpdate(t *testing.T) {
+	oldBdc := bigDataCluster("bdc-test",
+		bdcv1alpha1.Namespace{Name: "default-ns", IsDefault: true},
+		bdcv1alpha1.Namespace{Name: "data"},
+		bdcv1alpha1.Namespace{Name: "empty"})
+	tests := []struct {
+		name       string
+		namespaces []bdcv1alpha1.Namespace
+		wantFields []string
+	}{
+		{
+			name:       "remove empty namespace",
+			namespaces: []bdcv1alpha1.Namespace{{Name: "default-ns", IsDefault: true}, {Name: "data"}},
+		},
+		{
+			name:       "remove namespace holding applications",
+			namespaces: []bdcv1alpha1.Namespace{{Name: "default-ns", IsDefault: true}, {Name: "empty"}},
+			wantFields: []string{"spec.namespaces"},
+		},
+		{
+			name:       "remove default namespace holding application not rendered yet",
+			namespaces: []bdcv1alpha1.Namespace{{Name: "data", IsDefault: true}, {Name: "empty"}},
+			wantFields: []string{"spec.namespaces"},
+		},
+	}
+	h := newHandler()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := h.ValidateUpdate(context.Background(), bigDataCluster("bdc-test", tt.namespaces...), oldBdc)
+			assertFields(t, errs, tt.wantFields)
+		})
+	}
+}
+
+func assertFields(t *testing.T, errs field.ErrorList, wantFields []string) {
+	t.Helper()
+	if len(errs) != len(wantFields) {
+		t.Fatalf("got errors %v, want errors of fields %v", errs, wantFields)
+	}
+	for i := range errs {
+		if errs[i].Field != wantFields[i] {
+			t.Errorf("got error of field %s, want %s", errs[i].Field, wantFields[i])
+		}
+	}
+}
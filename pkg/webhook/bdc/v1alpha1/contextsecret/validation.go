@@ -0,0 +1,65 @@
+/*
+Copyright 2023 KDP(Kubernetes Data Platform).
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package contextsecret
+
+import (
+	"context"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	bdcv1alpha1 "kdp-oam-operator/api/bdc/v1alpha1"
+	"kdp-oam-operator/pkg/controllers/bdc/constants"
+	webhookutils "kdp-oam-operator/pkg/webhook/utils"
+)
+
+// ValidateCreate validates the ContextSecret on creation
+func (h *ValidatingHandler) ValidateCreate(ctx context.Context, contextSecret *bdcv1alpha1.ContextSecret) field.ErrorList {
+	var allErrs field.ErrorList
+	allErrs = append(allErrs, webhookutils.ValidateBigDataClusterWritable(ctx, h.Client, contextSecret)...)
+	if len(allErrs) > 0 {
+		return allErrs
+	}
+	if adopting(contextSecret) {
+		return allErrs
+	}
+	allErrs = append(allErrs, webhookutils.ValidateProperties(ctx, h.Client, contextSecret, "ContextSecret", contextSecret.Spec.Type, contextSecret.Spec.Properties)...)
+	return allErrs
+}
+
+// ValidateUpdate validates the ContextSecret on update
+func (h *ValidatingHandler) ValidateUpdate(ctx context.Context, newContextSecret, oldContextSecret *bdcv1alpha1.ContextSecret) field.ErrorList {
+	var allErrs field.ErrorList
+	// changes of metadata only, such as labels and finalizers, are always allowed
+	if apiequality.Semantic.DeepEqual(newContextSecret.Spec, oldContextSecret.Spec) &&
+		newContextSecret.GetAnnotations()[constants.AnnotationBDCName] == oldContextSecret.GetAnnotations()[constants.AnnotationBDCName] {
+		return allErrs
+	}
+	allErrs = append(allErrs, webhookutils.ValidateBigDataClusterWritable(ctx, h.Client, newContextSecret)...)
+	if len(allErrs) > 0 {
+		return allErrs
+	}
+	if adopting(newContextSecret) {
+		return allErrs
+	}
+	allErrs = append(allErrs, webhookutils.ValidateProperties(ctx, h.Client, newContextSecret, "ContextSecret", newContextSecret.Spec.Type, newContextSecret.Spec.Properties)...)
+	return allErrs
+}
+
+// adopting returns whether the ContextSecret adopts an existing Secret, which has no properties to be rendered
+func adopting(contextSecret *bdcv1alpha1.ContextSecret) bool {
+	return contextSecret.GetAnnotations()[constants.AnnotationCtxSecretAdopt] == "true"
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2023 KDP(Kubernetes Data Platform).
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package contextsetting
+
+import (
+	"context"
+	"sort"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	bdcv1alpha1 "kdp-oam-operator/api/bdc/v1alpha1"
+	"kdp-oam-operator/pkg/controllers/bdc/constants"
+	"kdp-oam-operator/pkg/utils"
+	webhookutils "kdp-oam-operator/pkg/webhook/utils"
+)
+
+// ValidateCreate validates the ContextSetting on creation
+func (h *ValidatingHandler) ValidateCreate(ctx context.Context, contextSetting *bdcv1alpha1.ContextSetting) field.ErrorList {
+	var allErrs field.ErrorList
+	allErrs = append(allErrs, webhookutils.ValidateBigDataClusterWritable(ctx, h.Client, contextSetting)...)
+	if len(allErrs) > 0 {
+		return allErrs
+	}
+	if contextSetting.GetAnnotations()[constants.AnnotationCtxSettingAdopt] == "true" {
+		return append(allErrs, validateAdoptedProperties(contextSetting)...)
+	}
+	allErrs = append(allErrs, webhookutils.ValidateProperties(ctx, h.Client, contextSetting, "ContextSetting", contextSetting.Spec.Type, contextSetting.Spec.Properties)...)
+	return allErrs
+}
+
+// ValidateUpdate validates the ContextSetting on update
+func (h *ValidatingHandler) ValidateUpdate(ctx context.Context, newContextSetting, oldContextSetting *bdcv1alpha1.ContextSetting) field.ErrorList {
+	var allErrs field.ErrorList
+	// changes of metadata only, such as labels and finalizers, are always allowed
+	if apiequality.Semantic.DeepEqual(newContextSetting.Spec, oldContextSetting.Spec) &&
+		newContextSetting.GetAnnotations()[constants.AnnotationBDCName] == oldContextSetting.GetAnnotations()[constants.AnnotationBDCName] {
+		return allErrs
+	}
+	allErrs = append(allErrs, webhookutils.ValidateBigDataClusterWritable(ctx, h.Client, newContextSetting)...)
+	if len(allErrs) > 0 {
+		return allErrs
+	}
+	if newContextSetting.GetAnnotations()[constants.AnnotationCtxSettingAdopt] == "true" {
+		return append(allErrs, validateAdoptedProperties(newContextSetting)...)
+	}
+	allErrs = append(allErrs, webhookutils.ValidateProperties(ctx, h.Client, newContextSetting, "ContextSetting", newContextSetting.Spec.Type, newContextSetting.Spec.Properties)...)
+	return allErrs
+}
+
+// validateAdoptedProperties validates the properties of the ContextSetting adopting a ConfigMap, they are written back
+// to the ConfigMap instead of being rendered, so all the values must be strings
+func validateAdoptedProperties(contextSetting *bdcv1alpha1.ContextSetting) field.ErrorList {
+	var allErrs field.ErrorList
+	path := field.NewPath("spec", "properties")
+	props, err := utils.RawExtension2Map(contextSetting.Spec.Properties)
+	if err != nil {
+		return append(allErrs, field.Invalid(path, field.OmitValueType{}, err.Error()))
+	}
+	keys := make([]string, 0, len(props))
+	for k := range props {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if _, ok := props[k].(string); !ok {
+			allErrs = append(allErrs, field.Invalid(path.Key(k), field.OmitValueType{}, "must be a string to be written back to the ConfigMap"))
+		}
+	}
+	return allErrs
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2023 KDP(Kubernetes Data Platform).
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xdefinition
+
+import (
+	"context"
+	"net/http"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	bdcv1alpha1 "kdp-oam-operator/api/bdc/v1alpha1"
+	webhookutils "kdp-oam-operator/pkg/webhook/utils"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+var _ admission.Handler = &ValidatingHandler{}
+
+// ValidatingHandler handles XDefinition
+type ValidatingHandler struct {
+	Client client.Client
+	// Decoder decodes objects
+	Decoder *admission.Decoder
+}
+
+var _ inject.Client = &ValidatingHandler{}
+
+// InjectClient injects the client into the XDefinitionValidateHandler
+func (h *ValidatingHandler) InjectClient(c client.Client) error {
+	if h.Client != nil {
+		return nil
+	}
+	h.Client = c
+	return nil
+}
+
+var _ admission.DecoderInjector = &ValidatingHandler{}
+
+// InjectDecoder injects the decoder into the XDefinitionValidateHandler
+func (h *ValidatingHandler) InjectDecoder(d *admission.Decoder) error {
+	if h.Decoder != nil {
+		return nil
+	}
+	h.Decoder = d
+	return nil
+}
+
+// Handle validate XDefinition Spec here
+func (h *ValidatingHandler) Handle(ctx context.Context, req admission.Request) admission.Response {
+	xDefinition := &bdcv1alpha1.XDefinition{}
+	if req.Operation == admissionv1.Delete || req.Operation == admissionv1.Connect {
+		// Do nothing for DELETE and CONNECT
+		return admission.ValidationResponse(true, "")
+	}
+	if err := h.Decoder.Decode(req, xDefinition); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	switch req.Operation {
+	case admissionv1.Create:
+		if allErrs := h.ValidateCreate(ctx, xDefinition); len(allErrs) > 0 {
+			return webhookutils.InvalidResponse("XDefinition", xDefinition.Name, allErrs)
+		}
+	case admissionv1.Update:
+		oldXDefinition := &bdcv1alpha1.XDefinition{}
+		if err := h.Decoder.DecodeRaw(req.AdmissionRequest.OldObject, oldXDefinition); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		if xDefinition.ObjectMeta.DeletionTimestamp.IsZero() {
+			if allErrs := h.ValidateUpdate(ctx, xDefinition, oldXDefinition); len(allErrs) > 0 {
+				return webhookutils.InvalidResponse("XDefinition", xDefinition.Name, allErrs)
+			}
+		}
+	}
+	return admission.ValidationResponse(true, "")
+}
+
+// RegisterValidatingHandler will register xdefinition validate handler to the webhook
+func RegisterValidatingHandler(mgr manager.Manager) {
+	server := mgr.GetWebhookServer()
+	server.Register("/validate-bdc-kdp-io-v1alpha1-xdefinition", &webhook.Admission{Handler: &ValidatingHandler{}})
+}
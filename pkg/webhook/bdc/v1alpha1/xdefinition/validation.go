@@ -0,0 +1,179 @@
+/*
+Copyright 2023 KDP(Kubernetes Data Platform).
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xdefinition
+
+import (
+	"context"
+	"fmt"
+
+	"cuelang.org/go/cue"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"kdp-oam-operator/api/bdc/common"
+	bdcv1alpha1 "kdp-oam-operator/api/bdc/v1alpha1"
+	"kdp-oam-operator/pkg/controllers/bdc/constants"
+	"kdp-oam-operator/pkg/controllers/bdc/deftemplate"
+)
+
+// ValidateCreate validates the XDefinition on creation
+func (h *ValidatingHandler) ValidateCreate(ctx context.Context, xDefinition *bdcv1alpha1.XDefinition) field.ErrorList {
+	var allErrs field.ErrorList
+	allErrs = append(allErrs, h.validateTemplate(ctx, xDefinition)...)
+	allErrs = append(allErrs, h.validateAPIResourceConflict(ctx, xDefinition)...)
+	return allErrs
+}
+
+// ValidateUpdate validates the XDefinition on update
+func (h *ValidatingHandler) ValidateUpdate(ctx context.Context, newXDefinition, oldXDefinition *bdcv1alpha1.XDefinition) field.ErrorList {
+	var allErrs field.ErrorList
+	// changes of metadata only, such as labels and finalizers, are always allowed
+	if apiequality.Semantic.DeepEqual(newXDefinition.Spec, oldXDefinition.Spec) &&
+		newXDefinition.GetAnnotations()[constants.AnnotationDefinitionOverride] == oldXDefinition.GetAnnotations()[constants.AnnotationDefinitionOverride] {
+		return allErrs
+	}
+	return h.ValidateCreate(ctx, newXDefinition)
+}
+
+// validateTemplate validates that the CUE template compiles, defines parameter and output,
+// and the OpenAPI schema of parameter can be generated. The chart of Helm schematic should be loaded,
+// and the Go template and its schema should be parsed.
+func (h *ValidatingHandler) validateTemplate(ctx context.Context, xDefinition *bdcv1alpha1.XDefinition) field.ErrorList {
+	var allErrs field.ErrorList
+	schematic := xDefinition.Spec.Schematic
+	if schematic != nil && schematicCount(schematic) > 1 {
+		return append(allErrs, field.Forbidden(field.NewPath("spec", "schematic"), "only one of cue, helm and goTemplate schematic can be set"))
+	}
+	if schematic != nil && schematic.Helm != nil {
+		return h.validateHelmChart(ctx, schematic.Helm)
+	}
+	if schematic != nil && schematic.GoTemplate != nil {
+		return validateGoTemplate(schematic.GoTemplate)
+	}
+	if schematic == nil || schematic.CUE == nil || schematic.CUE.Template == "" {
+		return append(allErrs, field.Required(templatePath(), "cue template is required"))
+	}
+
+	val, err := deftemplate.ParseToCUEValue(schematic.CUE.Template)
+	if err == nil {
+		err = val.Err()
+	}
+	if err != nil {
+		return append(allErrs, field.Invalid(templatePath(), field.OmitValueType{}, fmt.Sprintf("cannot compile the template: %v", err)))
+	}
+	for _, name := range []string{deftemplate.ParameterFieldName, deftemplate.OutputFieldName} {
+		if !val.LookupPath(cue.ParsePath(name)).Exists() {
+			allErrs = append(allErrs, field.Invalid(templatePath(), field.OmitValueType{}, fmt.Sprintf("%s is not defined in the template", name)))
+		}
+	}
+	if len(allErrs) > 0 {
+		return allErrs
+	}
+
+	def := deftemplate.NewCapabilityXDef(xDefinition)
+	if _, _, err := def.GetOpenAPIAndUischemaSchema(xDefinition.Name); err != nil {
+		allErrs = append(allErrs, field.Invalid(templatePath(), field.OmitValueType{}, fmt.Sprintf("cannot generate the schema of parameter: %v", err)))
+	}
+	return allErrs
+}
+
+// validateHelmChart validates that exactly one source of chart is set and the chart can be loaded
+func (h *ValidatingHandler) validateHelmChart(ctx context.Context, helm *common.Helm) field.ErrorList {
+	var allErrs field.ErrorList
+	switch {
+	case helm.Chart == "" && helm.ChartArchive == nil:
+		return append(allErrs, field.Required(helmPath(), "either chart or chartArchive is required"))
+	case helm.Chart != "" && helm.ChartArchive != nil:
+		return append(allErrs, field.Forbidden(helmPath().Child("chartArchive"), "chart and chartArchive are mutually exclusive"))
+	}
+	if _, err := deftemplate.LoadHelmChart(ctx, h.Client, helm); err != nil {
+		allErrs = append(allErrs, field.Invalid(helmPath(), field.OmitValueType{}, err.Error()))
+	}
+	return allErrs
+}
+
+// validateGoTemplate validates that the Go template and the JSON Schema of parameter can be parsed
+func validateGoTemplate(goTemplate *common.GoTemplate) field.ErrorList {
+	var allErrs field.ErrorList
+	path := field.NewPath("spec", "schematic", "goTemplate")
+	if goTemplate.Template == "" {
+		allErrs = append(allErrs, field.Required(path.Child("template"), "go template is required"))
+	} else if _, err := deftemplate.ParseGoTemplate("template", goTemplate.Template); err != nil {
+		allErrs = append(allErrs, field.Invalid(path.Child("template"), field.OmitValueType{}, fmt.Sprintf("cannot parse the template: %v", err)))
+	}
+	if _, err := deftemplate.ParseParameterSchema(goTemplate.Schema); err != nil {
+		allErrs = append(allErrs, field.Invalid(path.Child("schema"), field.OmitValueType{}, err.Error()))
+	}
+	return allErrs
+}
+
+// schematicCount returns the number of kinds of schematic set
+func schematicCount(schematic *common.Schematic) int {
+	count := 0
+	if schematic.CUE != nil {
+		count++
+	}
+	if schematic.Helm != nil {
+		count++
+	}
+	if schematic.GoTemplate != nil {
+		count++
+	}
+	return count
+}
+
+// validateAPIResourceConflict validates that no other XDefinition registers the same type and kind of API resource,
+// unless the XDefinition is annotated to override it
+func (h *ValidatingHandler) validateAPIResourceConflict(ctx context.Context, xDefinition *bdcv1alpha1.XDefinition) field.ErrorList {
+	var allErrs field.ErrorList
+	if xDefinition.GetAnnotations()[constants.AnnotationDefinitionOverride] == "true" {
+		return allErrs
+	}
+	var xDefinitionList bdcv1alpha1.XDefinitionList
+	if err := h.Client.List(ctx, &xDefinitionList); err != nil {
+		return append(allErrs, field.InternalError(apiResourcePath(), err))
+	}
+	key := apiResourceKey(xDefinition.Spec.APIResource.Definition)
+	for _, other := range xDefinitionList.Items {
+		if other.Name == xDefinition.Name || apiResourceKey(other.Spec.APIResource.Definition) != key {
+			continue
+		}
+		allErrs = append(allErrs, field.Forbidden(apiResourcePath(),
+			fmt.Sprintf(constants.ErrDefinitionConflict, key, other.Name, constants.AnnotationDefinitionOverride)))
+	}
+	return allErrs
+}
+
+// apiResourceKey returns the <type>-<kind> key of bdc-definition-map
+func apiResourceKey(definition bdcv1alpha1.Definition) string {
+	defType := definition.Type
+	if defType == "" {
+		defType = common.DefaultAPIResourceType
+	}
+	return fmt.Sprintf("%s-%s", defType, definition.Kind)
+}
+
+func templatePath() *field.Path {
+	return field.NewPath("spec", "schematic", "cue", "template")
+}
+
+func helmPath() *field.Path {
+	return field.NewPath("spec", "schematic", "helm")
+}
+
+func apiResourcePath() *field.Path {
+	return field.NewPath("spec", "apiResource", "definition")
+}
@@ -0,0 +1,142 @@
+package xdefinition
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"kdp-oam-operator/api/bdc/common"
+	bdcv1alpha1 "kdp-oam-operator/api/bdc/v1alpha1"
+	"kdp-oam-operator/pkg/controllers/bdc/constants"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func xDefinition(name, defType, template string, annotations map[string]string) *bdcv1alpha1.XDefinition {
+	return &bdcv1alpha1.XDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Annotations: annotations},
+		Spec: bdcv1alpha1.XDefinitionSpec{
+			Schematic:   &common.Schematic{CUE: &common.CUE{Template: template}},
+			APIResource: bdcv1alpha1.APIResource{Definition: bdcv1alpha1.Definition{APIVersion: "bdc.kdp.io/v1alpha1", Kind: "Application", Type: defType}},
+		},
+	}
+}
+
+func helmXDefinition(helm *common.Helm) *bdcv1alpha1.XDefinition {
+	xDefinition := xDefinition("hive-def", "hive", "", nil)
+	xDefinition.Spec.Schematic = &common.Schematic{Helm: helm}
+	return xDefinition
+}
+
+func goTemplateXDefinition(template, schema string) *bdcv1alpha1.XDefinition {
+	xDefinition := xDefinition("hive-def", "hive", "", nil)
+	xDefinition.Spec.Schematic = &common.Schematic{GoTemplate: &common.GoTemplate{Template: template, Schema: schema}}
+	return xDefinition
+}
+
+func TestValidateCreate(t *testing.T) {
+	const validTemplate = `
+output: {
+	apiVersion: "v1"
+	kind:       "ConfigMap"
+	metadata: name: context.name
+}
+parameter: {
+	replicas: *1 | int
+}
+`
+	scheme := runtime.NewScheme()
+	_ = bdcv1alpha1.AddToScheme(scheme)
+	h := &ValidatingHandler{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		xDefinition("hdfs-def", "hdfs", validTemplate, nil),
+	).Build()}
+
+	tests := []struct {
+		name        string
+		xDefinition *bdcv1alpha1.XDefinition
+		wantErrs    field.ErrorList
+	}{
+		{
+			name:        "valid",
+			xDefinition: xDefinition("hive-def", "hive", validTemplate, nil),
+		},
+		{
+			name:        "update itself",
+			xDefinition: xDefinition("hdfs-def", "hdfs", validTemplate, nil),
+		},
+		{
+			name:        "template not compiled",
+			xDefinition: xDefinition("hive-def", "hive", "output: {", nil),
+			wantErrs:    field.ErrorList{field.Invalid(templatePath(), nil, "")},
+		},
+		{
+			name:        "parameter and output not defined",
+			xDefinition: xDefinition("hive-def", "hive", "outputs: {}", nil),
+			wantErrs:    field.ErrorList{field.Invalid(templatePath(), nil, ""), field.Invalid(templatePath(), nil, "")},
+		},
+		{
+			name:        "template missing",
+			xDefinition: xDefinition("hive-def", "hive", "", nil),
+			wantErrs:    field.ErrorList{field.Required(templatePath(), "")},
+		},
+		{
+			name:        "helm chart missing",
+			xDefinition: helmXDefinition(&common.Helm{}),
+			wantErrs:    field.ErrorList{field.Required(helmPath(), "")},
+		},
+		{
+			name:        "helm chart not found",
+			xDefinition: helmXDefinition(&common.Helm{Chart: "/charts/not-found"}),
+			wantErrs:    field.ErrorList{field.Invalid(helmPath(), nil, "")},
+		},
+		{
+			name:        "helm chart sources conflict",
+			xDefinition: helmXDefinition(&common.Helm{Chart: "/charts/hive", ChartArchive: &common.ChartArchiveReference{Name: "hive-chart", Key: "hive.tgz"}}),
+			wantErrs:    field.ErrorList{field.Forbidden(helmPath().Child("chartArchive"), "")},
+		},
+		{
+			name:        "go template",
+			xDefinition: goTemplateXDefinition("kind: ConfigMap\nmetadata:\n  name: {{ .context.name | lower }}", "type: object"),
+		},
+		{
+			name:        "go template not parsed",
+			xDefinition: goTemplateXDefinition("{{ .context.name ", "type: [object"),
+			wantErrs: field.ErrorList{
+				field.Invalid(field.NewPath("spec", "schematic", "goTemplate", "template"), nil, ""),
+				field.Invalid(field.NewPath("spec", "schematic", "goTemplate", "schema"), nil, ""),
+			},
+		},
+		{
+			name: "schematics conflict",
+			xDefinition: func() *bdcv1alpha1.XDefinition {
+				xDefinition := goTemplateXDefinition("kind: ConfigMap", "")
+				xDefinition.Spec.Schematic.CUE = &common.CUE{Template: validTemplate}
+				return xDefinition
+			}(),
+			wantErrs: field.ErrorList{field.Forbidden(field.NewPath("spec", "schematic"), "")},
+		},
+		{
+			name:        "type and kind conflict",
+			xDefinition: xDefinition("hdfs-v2-def", "hdfs", validTemplate, nil),
+			wantErrs:    field.ErrorList{field.Forbidden(apiResourcePath(), "")},
+		},
+		{
+			name:        "type and kind overridden",
+			xDefinition: xDefinition("hdfs-v2-def", "hdfs", validTemplate, map[string]string{constants.AnnotationDefinitionOverride: "true"}),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := h.ValidateCreate(context.Background(), tt.xDefinition)
+			if len(errs) != len(tt.wantErrs) {
+				t.Fatalf("ValidateCreate() errs = %v, want %v", errs, tt.wantErrs)
+			}
+			for i := range errs {
+				if errs[i].Field != tt.wantErrs[i].Field || errs[i].Type != tt.wantErrs[i].Type {
+					t.Errorf("ValidateCreate() errs[%d] = %v, want %s error of %s", i, errs[i], tt.wantErrs[i].Type, tt.wantErrs[i].Field)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2023 KDP(Kubernetes Data Platform).
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+	"fmt"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	bdcv1alpha1 "kdp-oam-operator/api/bdc/v1alpha1"
+	"kdp-oam-operator/pkg/controllers/bdc/constants"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// ValidateBigDataClusterWritable validates that the BigDataCluster which obj is bound to by annotation
+// bdc.kdp.io/name allows creating or updating its resources, frozen or disabled BigDataCluster doesn't.
+func ValidateBigDataClusterWritable(ctx context.Context, c client.Client, obj metav1.Object) field.ErrorList {
+	var allErrs field.ErrorList
+	bdcName, ok := obj.GetAnnotations()[constants.AnnotationBDCName]
+	if !ok || bdcName == "" {
+		return allErrs
+	}
+
+	var bigDataCluster bdcv1alpha1.BigDataCluster
+	if err := c.Get(ctx, client.ObjectKey{Name: bdcName}, &bigDataCluster); err != nil {
+		if !apierrors.IsNotFound(err) {
+			allErrs = append(allErrs, field.InternalError(bigDataClusterPath(), err))
+		}
+		return allErrs
+	}
+	if state := bigDataCluster.DesiredState(); state != bdcv1alpha1.ActiveBigDataCluster {
+		allErrs = append(allErrs, field.Forbidden(bigDataClusterPath(), fmt.Sprintf(constants.ErrBigDataClusterNotWritable, bdcName, state)))
+	}
+	return allErrs
+}
+
+// InvalidResponse returns a denied admission response with status 422, the field errors are carried
+// as the causes of the status details, so that clients can show them by field path
+func InvalidResponse(kind, name string, errs field.ErrorList) admission.Response {
+	statusErr := apierrors.NewInvalid(bdcv1alpha1.GroupVersion.WithKind(kind).GroupKind(), name, errs)
+	return admission.Response{AdmissionResponse: admissionv1.AdmissionResponse{
+		Allowed: false,
+		Result:  &statusErr.ErrStatus,
+	}}
+}
+
+func bigDataClusterPath() *field.Path {
+	return field.NewPath("metadata", "annotations").Key(constants.AnnotationBDCName)
+}
@@ -0,0 +1,46 @@
+package utils
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	bdcv1alpha1 "kdp-oam-operator/api/bdc/v1alpha1"
+	"kdp-oam-operator/pkg/controllers/bdc/constants"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestValidateBigDataClusterWritable(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = bdcv1alpha1.AddToScheme(scheme)
+	cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&bdcv1alpha1.BigDataCluster{ObjectMeta: metav1.ObjectMeta{Name: "active-bdc"}},
+		&bdcv1alpha1.BigDataCluster{ObjectMeta: metav1.ObjectMeta{Name: "frozen-bdc"}, Spec: bdcv1alpha1.BigDataClusterSpec{Frozen: true}},
+		&bdcv1alpha1.BigDataCluster{ObjectMeta: metav1.ObjectMeta{Name: "disabled-bdc"}, Spec: bdcv1alpha1.BigDataClusterSpec{Disabled: true}},
+	).Build()
+
+	tests := []struct {
+		name    string
+		bdcName string
+		wantErr bool
+	}{
+		{name: "active bigdatacluster", bdcName: "active-bdc", wantErr: false},
+		{name: "frozen bigdatacluster", bdcName: "frozen-bdc", wantErr: true},
+		{name: "disabled bigdatacluster", bdcName: "disabled-bdc", wantErr: true},
+		{name: "bigdatacluster not found", bdcName: "missing-bdc", wantErr: false},
+		{name: "not bound to bigdatacluster", bdcName: "", wantErr: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := &bdcv1alpha1.ContextSetting{ObjectMeta: metav1.ObjectMeta{
+				Name:        "test-cs",
+				Annotations: map[string]string{constants.AnnotationBDCName: tt.bdcName},
+			}}
+			errs := ValidateBigDataClusterWritable(context.Background(), cli, obj)
+			if (len(errs) > 0) != tt.wantErr {
+				t.Errorf("ValidateBigDataClusterWritable() errs = %v, wantErr %v", errs, tt.wantErr)
+			}
+		})
+	}
+}
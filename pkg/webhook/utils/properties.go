@@ -0,0 +1,221 @@
+/*
+Copyright 2023 KDP(Kubernetes Data Platform).
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"kdp-oam-operator/api/bdc/common"
+	bdcv1alpha1 "kdp-oam-operator/api/bdc/v1alpha1"
+	pkgcommon "kdp-oam-operator/pkg/common"
+	"kdp-oam-operator/pkg/controllers/bdc/constants"
+	"kdp-oam-operator/pkg/controllers/bdc/deftemplate"
+	"kdp-oam-operator/pkg/controllers/bdc/parser"
+	"kdp-oam-operator/pkg/controllers/utils/defrevision"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ValidateProperties validates spec.properties of obj against the OpenAPI schema of the XDefinition rendering it,
+// which is resolved by kind and spec.type the same as the controllers, then tries a full render of the template.
+// The values are omitted from the errors since the properties may be sensitive.
+func ValidateProperties(ctx context.Context, c client.Client, obj client.Object, kind, defType string, properties *runtime.RawExtension) field.ErrorList {
+	var allErrs field.ErrorList
+	xDefinition := new(bdcv1alpha1.XDefinition)
+	if err := deftemplate.GetBDCDefinition(ctx, c, xDefinition, kind, defType); err != nil {
+		if apierrors.IsNotFound(err) {
+			return append(allErrs, field.NotFound(field.NewPath("spec", "type"), defType))
+		}
+		return append(allErrs, field.InternalError(field.NewPath("spec", "type"), err))
+	}
+
+	schema, err := loadPropertiesSchema(ctx, c, obj, xDefinition)
+	if err != nil {
+		return append(allErrs, field.InternalError(propertiesPath(), err))
+	}
+	if schema != nil {
+		var value interface{} = map[string]interface{}{}
+		if properties != nil && len(properties.Raw) > 0 {
+			if err := json.Unmarshal(properties.Raw, &value); err != nil {
+				return append(allErrs, field.Invalid(propertiesPath(), field.OmitValueType{}, err.Error()))
+			}
+		}
+		allErrs = append(allErrs, schemaErrors(schema.VisitJSON(value, openapi3.MultiErrors()))...)
+	}
+	if len(allErrs) > 0 {
+		return allErrs
+	}
+	return validateRender(ctx, c, obj, kind)
+}
+
+// loadPropertiesSchema loads the parameter schema of the XDefinition revision rendering obj,
+// nil if the schema is not generated yet
+func loadPropertiesSchema(ctx context.Context, c client.Client, obj client.Object, xDefinition *bdcv1alpha1.XDefinition) (*openapi3.Schema, error) {
+	var data string
+	bdcName := obj.GetAnnotations()[constants.AnnotationBDCName]
+	if ref := defrevision.Resolve(xDefinition, defrevision.Pin(obj), bdcName); ref != "" {
+		revisions, err := defrevision.List(ctx, c, xDefinition.Name)
+		if err != nil {
+			return nil, err
+		}
+		revision := defrevision.Find(revisions, ref)
+		if revision == nil {
+			return nil, apierrors.NewNotFound(bdcv1alpha1.GroupVersion.WithResource("xdefinitionrevisions").GroupResource(), ref)
+		}
+		data = revision.Spec.OpenAPISchema
+	} else if xDefinition.Status.SchemaConfigMapRef != "" {
+		var cm corev1.ConfigMap
+		key := types.NamespacedName{Namespace: xDefinition.Status.SchemaConfigMapRefNamespace, Name: xDefinition.Status.SchemaConfigMapRef}
+		if key.Namespace == "" {
+			key.Namespace = pkgcommon.SystemDefaultNamespace
+		}
+		if err := c.Get(ctx, key, &cm); err != nil {
+			return nil, client.IgnoreNotFound(err)
+		}
+		data = cm.Data[common.OpenapiV3JSONSchema]
+	}
+	if data == "" {
+		return nil, nil
+	}
+	schema := new(openapi3.Schema)
+	if err := json.Unmarshal([]byte(data), schema); err != nil {
+		return nil, err
+	}
+	relaxSchema(schema)
+	return schema, nil
+}
+
+// relaxSchema removes the properties which could be omitted from the required ones. The schema generated from
+// CUE requires the fields with default values, the open lists and the structs of such fields, which are filled
+// by CUE when rendering. The "not" constraints are removed too, since CUE generates them with the misspelled
+// "allOff" which rejects any value, they are checked by the render instead.
+func relaxSchema(schema *openapi3.Schema) bool {
+	if schema == nil {
+		return true
+	}
+	schema.Not = nil
+	if schema.Items != nil {
+		relaxSchema(schema.Items.Value)
+	}
+	required := schema.Required[:0]
+	for _, name := range schema.Required {
+		ref, ok := schema.Properties[name]
+		if !ok || ref.Value == nil {
+			required = append(required, name)
+			continue
+		}
+		if optional := relaxSchema(ref.Value); !optional {
+			required = append(required, name)
+		}
+	}
+	schema.Required = required
+	for name, ref := range schema.Properties {
+		if ref.Value != nil && !containsString(schema.Required, name) {
+			relaxSchema(ref.Value)
+		}
+	}
+	switch {
+	case schema.Default != nil:
+		return true
+	case schema.Type == openapi3.TypeArray:
+		return schema.MinItems == 0
+	case schema.Type == openapi3.TypeObject:
+		return len(schema.Required) == 0
+	}
+	return false
+}
+
+// validateRender tries a full render of obj, the BigDataCluster of obj is required to render,
+// so the validation is skipped if it doesn't exist yet
+func validateRender(ctx context.Context, c client.Client, obj client.Object, kind string) field.ErrorList {
+	var allErrs field.ErrorList
+	bdcName := obj.GetAnnotations()[constants.AnnotationBDCName]
+	if bdcName == "" {
+		return allErrs
+	}
+	var bigDataCluster bdcv1alpha1.BigDataCluster
+	if err := c.Get(ctx, client.ObjectKey{Name: bdcName}, &bigDataCluster); err != nil {
+		if !apierrors.IsNotFound(err) {
+			allErrs = append(allErrs, field.InternalError(bigDataClusterPath(), err))
+		}
+		return allErrs
+	}
+
+	obj = obj.DeepCopyObject().(client.Object)
+	obj.GetObjectKind().SetGroupVersionKind(bdcv1alpha1.GroupVersion.WithKind(kind))
+	bdcFile, err := parser.NewParser(c).GenerateBigDataClusterFile(ctx, obj)
+	if err == nil {
+		_, err = bdcFile.PrepareManifests(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: obj.GetName()}})
+	}
+	if err != nil {
+		allErrs = append(allErrs, field.Invalid(propertiesPath(), field.OmitValueType{}, "cannot render the xdefinition template: "+err.Error()))
+	}
+	return allErrs
+}
+
+// schemaErrors converts the errors of openapi schema validation to field errors of spec.properties
+func schemaErrors(err error) field.ErrorList {
+	var allErrs field.ErrorList
+	if err == nil {
+		return allErrs
+	}
+	var multiErr openapi3.MultiError
+	if errors.As(err, &multiErr) {
+		for _, e := range multiErr {
+			allErrs = append(allErrs, schemaErrors(e)...)
+		}
+		return allErrs
+	}
+	var schemaErr *openapi3.SchemaError
+	if !errors.As(err, &schemaErr) {
+		return append(allErrs, field.Invalid(propertiesPath(), field.OmitValueType{}, err.Error()))
+	}
+	path := propertiesPath()
+	for _, key := range schemaErr.JSONPointer() {
+		if index, err := strconv.Atoi(key); err == nil {
+			path = path.Index(index)
+		} else {
+			path = path.Child(key)
+		}
+	}
+	if schemaErr.SchemaField == "required" {
+		return append(allErrs, field.Required(path, schemaErr.Reason))
+	}
+	return append(allErrs, field.Invalid(path, field.OmitValueType{}, schemaErr.Reason))
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func propertiesPath() *field.Path {
+	return field.NewPath("spec", "properties")
+}
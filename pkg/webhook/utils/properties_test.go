@@ -0,0 +1,132 @@
+package utils
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"kdp-oam-operator/api/bdc/common"
+	bdcv1alpha1 "kdp-oam-operator/api/bdc/v1alpha1"
+	pkgcommon "kdp-oam-operator/pkg/common"
+	"kdp-oam-operator/pkg/controllers/bdc/constants"
+	"kdp-oam-operator/pkg/controllers/bdc/deftemplate"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const hdfsTemplate = `
+output: {
+	apiVersion: "v1"
+	kind:       "ConfigMap"
+	metadata: name: context.name
+	data: {
+		replicas: "\(parameter.replicas)"
+		port:     "\(parameter.port)"
+	}
+}
+parameter: {
+	name:     string
+	replicas: *1 | int
+	port:     int & !=22
+	labels: [...string]
+}
+`
+
+func TestValidateProperties(t *testing.T) {
+	xDefinition := &bdcv1alpha1.XDefinition{
+		// the fake client doesn't ignore the namespace of cluster scoped objects like the real one
+		ObjectMeta: metav1.ObjectMeta{Name: "hdfs-def", Namespace: pkgcommon.SystemDefaultNamespace},
+		Spec: bdcv1alpha1.XDefinitionSpec{
+			Schematic:   &common.Schematic{CUE: &common.CUE{Template: hdfsTemplate}},
+			APIResource: bdcv1alpha1.APIResource{Definition: bdcv1alpha1.Definition{APIVersion: "bdc.kdp.io/v1alpha1", Kind: "Application", Type: "hdfs"}},
+		},
+		Status: bdcv1alpha1.XDefinitionStatus{SchemaConfigMapRef: "schema-hdfs-def-hdfs", SchemaConfigMapRefNamespace: pkgcommon.SystemDefaultNamespace},
+	}
+	def := deftemplate.NewCapabilityXDef(xDefinition)
+	schema, _, err := def.GetOpenAPIAndUischemaSchema(xDefinition.Name)
+	if err != nil {
+		t.Fatalf("GetOpenAPIAndUischemaSchema() error = %v", err)
+	}
+
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = bdcv1alpha1.AddToScheme(scheme)
+	cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		xDefinition,
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "bdc-definition-map", Namespace: pkgcommon.SystemDefaultNamespace},
+			Data:       map[string]string{"hdfs-Application": "hdfs-def"},
+		},
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "schema-hdfs-def-hdfs", Namespace: pkgcommon.SystemDefaultNamespace},
+			Data:       map[string]string{common.OpenapiV3JSONSchema: string(schema)},
+		},
+		&bdcv1alpha1.BigDataCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "bdc-test"},
+			Spec:       bdcv1alpha1.BigDataClusterSpec{Namespaces: []bdcv1alpha1.Namespace{{Name: "bdc-test-ns", IsDefault: true}}},
+		},
+	).Build()
+
+	tests := []struct {
+		name       string
+		defType    string
+		properties string
+		wantErrs   field.ErrorList
+	}{
+		{
+			name:       "valid",
+			defType:    "hdfs",
+			properties: `{"name": "hdfs", "port": 8020}`,
+		},
+		{
+			name:       "missing required property",
+			defType:    "hdfs",
+			properties: `{"port": 8020}`,
+			wantErrs:   field.ErrorList{field.Required(field.NewPath("spec", "properties", "name"), "")},
+		},
+		{
+			name:       "invalid property type",
+			defType:    "hdfs",
+			properties: `{"name": "hdfs", "port": 8020, "replicas": "two", "labels": [1]}`,
+			wantErrs: field.ErrorList{
+				field.Invalid(field.NewPath("spec", "properties", "labels").Index(0), nil, ""),
+				field.Invalid(field.NewPath("spec", "properties", "replicas"), nil, ""),
+			},
+		},
+		{
+			name:       "render failure",
+			defType:    "hdfs",
+			properties: `{"name": "hdfs", "port": 22}`,
+			wantErrs:   field.ErrorList{field.Invalid(field.NewPath("spec", "properties"), nil, "")},
+		},
+		{
+			name:     "xdefinition not found",
+			defType:  "kafka",
+			wantErrs: field.ErrorList{field.NotFound(field.NewPath("spec", "type"), "kafka")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := &bdcv1alpha1.Application{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-app", Annotations: map[string]string{constants.AnnotationBDCName: "bdc-test"}},
+				Spec:       bdcv1alpha1.ApplicationSpec{Name: "test-app", Type: tt.defType, Properties: &runtime.RawExtension{Raw: []byte(tt.properties)}},
+			}
+			errs := ValidateProperties(context.Background(), cli, app, "Application", app.Spec.Type, app.Spec.Properties)
+			if len(errs) != len(tt.wantErrs) {
+				t.Fatalf("ValidateProperties() errs = %v, want %v", errs, tt.wantErrs)
+			}
+			got := make(map[string]field.ErrorType)
+			for _, err := range errs {
+				got[err.Field] = err.Type
+			}
+			for _, want := range tt.wantErrs {
+				if got[want.Field] != want.Type {
+					t.Errorf("ValidateProperties() errs = %v, want %s error of %s", errs, want.Type, want.Field)
+				}
+			}
+		})
+	}
+}